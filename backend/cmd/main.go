@@ -11,6 +11,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/yorukot/blind-party/internal/config"
+	"github.com/yorukot/blind-party/internal/handler/game"
 	"github.com/yorukot/blind-party/internal/middleware"
 	"github.com/yorukot/blind-party/internal/router"
 	"github.com/yorukot/blind-party/pkg/logger"
@@ -33,23 +34,30 @@ func main() {
 		return
 	}
 
+	// Every log line names the service, for aggregated logs
+	zap.ReplaceGlobals(zap.L().With(zap.String("app", config.Env().AppName)))
+
 	r := chi.NewRouter()
 
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"http://localhost:5173", "https://localhost:5173", "http://100.64.0.100:5173", "https://yorukot.github.io", "https://eclectic-sawine-7dd6a4.netlify.app", "https://bgayp.netlify.app", "https://frank-kam.itch.io"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "X-Requested-With", "Upgrade", "Connection", "Sec-WebSocket-Key", "Sec-WebSocket-Version", "Sec-WebSocket-Protocol"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "X-Requested-With", "Upgrade", "Connection", "Sec-WebSocket-Key", "Sec-WebSocket-Version", "Sec-WebSocket-Protocol", "Idempotency-Key"},
+		ExposedHeaders:   []string{"Link", "X-Request-ID"},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
 	r.Use(middleware.ZapLoggerMiddleware(zap.L()))
+	r.Use(middleware.ZapRecovererMiddleware(zap.L()))
 	r.Use(chiMiddleware.StripSlashes)
 
 	setupRouter(r)
 
 	zap.L().Info("Starting server on http://localhost:" + config.Env().Port)
 	zap.L().Info("Environment: " + string(config.Env().AppEnv))
+	if config.Env().Debug {
+		zap.L().Info("Debug mode: verbose tick logging and /debug/pprof enabled")
+	}
 
 	err = http.ListenAndServe(":"+config.Env().Port, r)
 	if err != nil {
@@ -59,25 +67,26 @@ func main() {
 
 // setupRouter sets up the router
 func setupRouter(r chi.Router) {
+	var gameHandler *game.GameHandler
 	r.Route("/api", func(r chi.Router) {
-		router.GameRouter(r)
+		gameHandler = router.GameRouter(r)
 	})
 
 	if config.Env().AppEnv == config.AppEnvDev {
 		r.Get("/swagger/*", httpSwagger.WrapHandler)
 	}
 
-	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.Write([]byte("OK"))
-	})
+	router.DebugRouter(r, config.Env().Debug)
+
+	router.HealthRouter(r, gameHandler.GameCounts, gameHandler.SendBufferFullCount)
 
 	// Not found handler
 	r.NotFound(func(w http.ResponseWriter, r *http.Request) {
-		response.RespondWithError(w, http.StatusNotFound, "Not Found", "NOT_FOUND")
+		response.Fail(w, http.StatusNotFound, "NOT_FOUND", "Not Found")
 	})
 
 	r.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
-		response.RespondWithError(w, http.StatusMethodNotAllowed, "Method Not Allowed", "METHOD_NOT_ALLOWED")
+		response.Fail(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Method Not Allowed")
 	})
 
 	zap.L().Info("Router setup complete")
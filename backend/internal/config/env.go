@@ -1,6 +1,7 @@
 package config
 
 import (
+	"fmt"
 	"sync"
 
 	"github.com/caarlos0/env/v10"
@@ -22,6 +23,46 @@ type EnvConfig struct {
 	AppName    string `env:"APP_NAME" envDefault:"stargo"`
 	MinPlayers int    `env:"MIN_PLAYERS" envDefault:"4"`
 	MaxPlayers int    `env:"MAX_PLAYERS" envDefault:"16"`
+
+	// Lobby auto-start
+	AutoStartSeconds       float64 `env:"AUTO_START_SECONDS" envDefault:"5"`
+	AutoStartCapacityRatio float64 `env:"AUTO_START_CAPACITY_RATIO" envDefault:"0.75"`
+
+	// WebSocket deadlines, reads allow two of the frontend's 30s keepalive pings
+	WSReadTimeoutSeconds  int `env:"WS_READ_TIMEOUT_SECONDS" envDefault:"60"`
+	WSWriteTimeoutSeconds int `env:"WS_WRITE_TIMEOUT_SECONDS" envDefault:"10"`
+
+	// Initial states larger than this are gzipped for clients connecting with
+	// ?compress=gzip, 0 disables compression
+	WSCompressThresholdBytes int `env:"WS_COMPRESS_THRESHOLD_BYTES" envDefault:"16384"`
+
+	// Admin endpoints, disabled while empty
+	AdminToken string `env:"ADMIN_TOKEN" envDefault:""`
+
+	// Read-only observer streams, open to anyone while empty
+	ObserverKey string `env:"OBSERVER_KEY" envDefault:""`
+
+	// Anti-cheat violation reporting, "memory" or "file"
+	ViolationSink string `env:"VIOLATION_SINK" envDefault:"memory"`
+	ViolationFile string `env:"VIOLATION_FILE" envDefault:"violations/violations.ndjson"`
+
+	// In memory, each game keeps its newest violations and is forgotten once
+	// nothing was reported for it for the retention period
+	ViolationMaxPerGame       int `env:"VIOLATION_MAX_PER_GAME" envDefault:"1000"`
+	ViolationRetentionMinutes int `env:"VIOLATION_RETENTION_MINUTES" envDefault:"1440"`
+
+	// Game event webhooks
+	WebhookTimeoutSeconds int `env:"WEBHOOK_TIMEOUT_SECONDS" envDefault:"5"`
+	WebhookMaxRetries     int `env:"WEBHOOK_MAX_RETRIES" envDefault:"3"`
+	WebhookMaxPending     int `env:"WEBHOOK_MAX_PENDING" envDefault:"64"`
+
+	// Lets webhooks reach loopback and private addresses, for receivers on
+	// the operator's own network
+	WebhookAllowPrivate bool `env:"WEBHOOK_ALLOW_PRIVATE" envDefault:"false"`
+
+	// Replay recording
+	ReplayEnabled bool   `env:"REPLAY_ENABLED" envDefault:"false"`
+	ReplayDir     string `env:"REPLAY_DIR" envDefault:"replays"`
 }
 
 var (
@@ -35,9 +76,24 @@ func loadConfig() (*EnvConfig, error) {
 	if err := env.Parse(cfg); err != nil {
 		return nil, err
 	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
 	return cfg, nil
 }
 
+// validate rejects values every game would copy into its config but could
+// never play with
+func (c *EnvConfig) validate() error {
+	if c.AutoStartSeconds < 0 {
+		return fmt.Errorf("AUTO_START_SECONDS must not be negative, got %v", c.AutoStartSeconds)
+	}
+	if c.AutoStartCapacityRatio < 0 || c.AutoStartCapacityRatio > 1 {
+		return fmt.Errorf("AUTO_START_CAPACITY_RATIO must be between 0 and 1, got %v", c.AutoStartCapacityRatio)
+	}
+	return nil
+}
+
 // InitConfig initializes the config only once
 func InitConfig() (*EnvConfig, error) {
 	var err error
@@ -48,6 +104,11 @@ func InitConfig() (*EnvConfig, error) {
 	return appConfig, err
 }
 
+// Initialized reports whether InitConfig has loaded the config
+func Initialized() bool {
+	return appConfig != nil
+}
+
 // Env returns the config. Panics if not initialized.
 func Env() *EnvConfig {
 	if appConfig == nil {
@@ -0,0 +1,32 @@
+package config
+
+import "testing"
+
+func TestAutoStartEnvIsValidated(t *testing.T) {
+	tests := []struct {
+		name    string
+		seconds string
+		ratio   string
+		valid   bool
+	}{
+		{name: "defaults", valid: true},
+		{name: "bounds", seconds: "0", ratio: "1", valid: true},
+		{name: "negative seconds", seconds: "-1"},
+		{name: "ratio above 1", ratio: "1.5"},
+		{name: "negative ratio", ratio: "-0.5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.seconds != "" {
+				t.Setenv("AUTO_START_SECONDS", tt.seconds)
+			}
+			if tt.ratio != "" {
+				t.Setenv("AUTO_START_CAPACITY_RATIO", tt.ratio)
+			}
+			_, err := loadConfig()
+			if (err == nil) != tt.valid {
+				t.Errorf("loadConfig() = %v, want valid %v", err, tt.valid)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package game
+
+import (
+	"log"
+	"math"
+	"time"
+
+	"github.com/yorukot/blind-party/internal/schema"
+	"github.com/yorukot/blind-party/internal/violation"
+)
+
+// spawnGraceRadius is how far, in blocks, the first update after a spawn may be
+// from the spawn point and still be taken as the new baseline
+const spawnGraceRadius = 1.5
+
+// minSpeedSampleInterval is the shortest time a speed is measured over. Clients
+// sending faster than this would otherwise divide jitter by a near-zero delta.
+const minSpeedSampleInterval = 20 * time.Millisecond
+
+// placeAtSpawn puts the player on a spawn point and gives them the spawn grace
+func placeAtSpawn(player *schema.Player, spawn schema.Position) {
+	player.Position = spawn
+	player.LastValidPosition = spawn
+	player.SpawnGrace = true
+}
+
+// validateMovement checks a position update against the max movement speed and
+// applies the configured penalty if it's too fast. It reports whether the
+// update may be applied. Callers must hold game.Mu.
+func (h *GameHandler) validateMovement(game *schema.Game, player *schema.Player, newPosition schema.Position) bool {
+	now := time.Now()
+
+	// Nobody moves while an admin has the game paused
+	if game.PausedAt != nil {
+		sendToClient(game, player.Name, map[string]interface{}{
+			"event": "movement_rejected",
+			"data": map[string]interface{}{
+				"reason":         "game_paused",
+				"reset_position": player.LastValidPosition,
+				"message":        "Input ignored while the game is paused",
+			},
+		})
+		return false
+	}
+
+	// Frozen players' input is dropped until the freeze wears off
+	if now.Before(player.FrozenUntil) {
+		sendToClient(game, player.Name, map[string]interface{}{
+			"event": "movement_rejected",
+			"data": map[string]interface{}{
+				"reason":         "frozen",
+				"frozen_ms":      player.FrozenUntil.Sub(now).Milliseconds(),
+				"reset_position": player.LastValidPosition,
+				"message":        "Input ignored while frozen for invalid movement",
+			},
+		})
+		return false
+	}
+
+	// The client's idea of the spawn point can be slightly off the server's,
+	// so the first update after a spawn becomes the baseline if it's close
+	if player.SpawnGrace {
+		player.SpawnGrace = false
+		// Positions from before the spawn would make the jump look like a teleport
+		positionHistory(game, player.Name).Reset()
+		if math.Hypot(newPosition.X-player.LastValidPosition.X, newPosition.Y-player.LastValidPosition.Y) <= spawnGraceRadius {
+			player.LastValidPosition = newPosition
+			player.LastMoveTime = now
+			return true
+		}
+	}
+
+	// Allow the distance coverable at max speed, plus the lag compensation window
+	lag := h.lagCompensationFor(game, player)
+	elapsed := now.Sub(player.LastMoveTime) + lag
+	distance := math.Hypot(newPosition.X-player.LastValidPosition.X, newPosition.Y-player.LastValidPosition.Y)
+
+	// Too soon after the last checked update to measure a speed. Accept it if
+	// it stays within what a full sample interval allows but keep the baseline,
+	// so the movement accumulates until an update can be measured properly.
+	if now.Sub(player.LastMoveTime) < minSpeedSampleInterval &&
+		distance <= game.Config.MaxMovementSpeed*(minSpeedSampleInterval+lag).Seconds() {
+		return true
+	}
+
+	if distance <= game.Config.MaxMovementSpeed*elapsed.Seconds() && !exceedsWindowSpeed(game, player, newPosition, lag, now) {
+		player.LastValidPosition = newPosition
+		player.LastMoveTime = now
+		return true
+	}
+
+	player.ViolationCount++
+	speed := distance / math.Max(now.Sub(player.LastMoveTime).Seconds(), minSpeedSampleInterval.Seconds())
+	log.Printf("Player %s moved too fast in game %s: %.1f blocks/s (max %.1f), violation %d",
+		player.Name, game.ID, speed, game.Config.MaxMovementSpeed, player.ViolationCount)
+	h.reportViolation(game, player, "movement_too_fast", speed, now)
+
+	message := "Position reset due to invalid movement"
+	switch game.Config.AntiCheatMode {
+	case schema.AntiCheatFreeze:
+		player.FrozenUntil = now.Add(time.Duration(game.Config.FreezeDurationMs) * time.Millisecond)
+		message = "Input frozen due to invalid movement"
+	case schema.AntiCheatKick:
+		if player.ViolationCount >= game.Config.KickAfterViolations {
+			h.kickPlayer(game, player)
+			return false
+		}
+	}
+
+	// The client snaps back to the last valid position in every mode
+	player.Position = player.LastValidPosition
+	sendToClient(game, player.Name, map[string]interface{}{
+		"event": "movement_rejected",
+		"data": map[string]interface{}{
+			"reason":          "movement_too_fast",
+			"speed":           speed,
+			"max_speed":       game.Config.MaxMovementSpeed,
+			"reset_position":  player.LastValidPosition,
+			"violation_count": player.ViolationCount,
+			"message":         message,
+		},
+	})
+	return false
+}
+
+// reportViolation hands the rejection to the violation reporter, if one is configured
+func (h *GameHandler) reportViolation(game *schema.Game, player *schema.Player, reason string, speed float64, at time.Time) {
+	if h.Violations == nil {
+		return
+	}
+	err := h.Violations.Report(violation.Violation{
+		GameID:    game.ID,
+		Username:  player.Name,
+		UserID:    player.UserID,
+		Reason:    reason,
+		Speed:     speed,
+		Timestamp: at,
+	})
+	if err != nil {
+		log.Printf("Error reporting violation of player %s in game %s: %v", player.Name, game.ID, err)
+	}
+}
+
+// kickPlayer disconnects a repeat offender. Closing the connection ends its read
+// loop, which unregisters the client and removes the player as on any disconnect.
+func (h *GameHandler) kickPlayer(game *schema.Game, player *schema.Player) {
+	client, exists := game.Clients[player.Name]
+	if !exists {
+		return
+	}
+
+	log.Printf("Kicking player %s from game %s after %d violations", player.Name, game.ID, player.ViolationCount)
+	client.Kicked = true
+	go func() {
+		sendCloseMessage(client.Conn, closeKicked)
+		client.Conn.Close()
+	}()
+}
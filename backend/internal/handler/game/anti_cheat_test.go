@@ -0,0 +1,169 @@
+package game
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"golang.org/x/net/websocket"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+// cheatingPlayer returns a player of a running game who last moved a second ago
+// from 10.5, 10.5, with their client
+func cheatingPlayer(t *testing.T, mode schema.AntiCheatMode) (*GameHandler, *schema.Game, *schema.Player, *schema.WebSocketClient) {
+	t.Helper()
+	h, game, clients := startTestGame(t, func(cfg *schema.GameConfig) {
+		cfg.AntiCheatMode = mode
+		cfg.FreezeDurationMs = 200
+	}, "alice")
+	client, player := clients["alice"], game.Players["alice"]
+	player.Position = schema.Position{X: 10.5, Y: 10.5}
+	player.LastValidPosition = player.Position
+	player.LastMoveTime = time.Now().Add(-time.Second)
+	received(client)
+	return h, game, player, client
+}
+
+func TestResetModeSnapsBack(t *testing.T) {
+	h, game, player, client := cheatingPlayer(t, schema.AntiCheatReset)
+
+	if h.validateMovement(game, player, schema.Position{X: 18.5, Y: 10.5}) {
+		t.Fatal("8 blocks in a second was accepted")
+	}
+	rejections := withEvent(received(client), "movement_rejected")
+	if len(rejections) != 1 || rejections[0]["reason"] != "movement_too_fast" || rejections[0]["reset_position"] != player.LastValidPosition {
+		t.Errorf("movement_rejected = %v", rejections)
+	}
+	if player.Position.X != 10.5 || player.ViolationCount != 1 {
+		t.Errorf("position %.1f with %d violations, want reset to 10.5 with 1", player.Position.X, player.ViolationCount)
+	}
+
+	// The next legal move goes through
+	if !h.validateMovement(game, player, schema.Position{X: 11.5, Y: 10.5}) {
+		t.Error("a legal move after the reset was rejected")
+	}
+}
+
+func TestFreezeModeIgnoresInput(t *testing.T) {
+	h, game, player, client := cheatingPlayer(t, schema.AntiCheatFreeze)
+
+	if h.validateMovement(game, player, schema.Position{X: 18.5, Y: 10.5}) {
+		t.Fatal("8 blocks in a second was accepted")
+	}
+	received(client)
+
+	if h.validateMovement(game, player, schema.Position{X: 10.6, Y: 10.5}) {
+		t.Error("a frozen player's move was accepted")
+	}
+	rejections := withEvent(received(client), "movement_rejected")
+	if len(rejections) != 1 || rejections[0]["reason"] != "frozen" {
+		t.Errorf("movement_rejected = %v, want frozen", rejections)
+	}
+	if player.ViolationCount != 1 {
+		t.Errorf("%d violations, want the frozen move not to count", player.ViolationCount)
+	}
+
+	time.Sleep(250 * time.Millisecond)
+	if !h.validateMovement(game, player, schema.Position{X: 11.5, Y: 10.5}) {
+		t.Error("a legal move after the freeze was rejected")
+	}
+}
+
+func TestKickModeDisconnectsRepeatOffenders(t *testing.T) {
+	h, game := newTestGame(t, func(cfg *schema.GameConfig) {
+		cfg.AntiCheatMode = schema.AntiCheatKick
+		cfg.KickAfterViolations = 2
+		cfg.FirstRoundGraceSeconds = 100
+	})
+	server := serveGames(t, h)
+	runGame(t, h, game)
+
+	alice := dialGame(t, server, game.ID, "/ws?username=alice&user_id=u1")
+	receiveUntil(t, alice, "game_update")
+	game.Mu.Lock()
+	h.startGame(game)
+	game.Mu.Unlock()
+
+	for seq := 1; seq <= 2; seq++ {
+		if err := websocket.JSON.Send(alice, playerUpdate(100, 100, seq)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	closing := receiveClose(t, alice)
+	if closing == nil || closing["reason"] != closeKicked.Reason {
+		t.Errorf("closed with %v, want %s", closing, closeKicked.Reason)
+	}
+	game.Mu.RLock()
+	defer game.Mu.RUnlock()
+	if violations := game.Players["alice"].ViolationCount; violations != 2 {
+		t.Errorf("%d violations, want 2", violations)
+	}
+}
+
+func TestFirstUpdateAfterSpawnIsNotATeleport(t *testing.T) {
+	h, game, player, client := cheatingPlayer(t, schema.AntiCheatReset)
+	spawn := schema.Position{X: 4, Y: 4}
+	placeAtSpawn(player, spawn)
+	player.LastMoveTime = time.Now()
+
+	// The client puts the player a little off the server's spawn point
+	adopted := schema.Position{X: 5, Y: 4.6}
+	if !h.validateMovement(game, player, adopted) {
+		t.Fatal("the first update after the spawn was rejected")
+	}
+	if player.ViolationCount != 0 || player.LastValidPosition != adopted || player.SpawnGrace {
+		t.Errorf("%d violations, baseline %v, grace %v after the first update", player.ViolationCount, player.LastValidPosition, player.SpawnGrace)
+	}
+	if rejections := withEvent(received(client), "movement_rejected"); len(rejections) != 0 {
+		t.Errorf("movement_rejected = %v", rejections)
+	}
+
+	// The grace covers one update only
+	if h.validateMovement(game, player, schema.Position{X: 6.2, Y: 4.6}) {
+		t.Error("a second jump right after the spawn was accepted")
+	}
+}
+
+func TestSpawnGraceDoesNotCoverFarJumps(t *testing.T) {
+	h, game, player, _ := cheatingPlayer(t, schema.AntiCheatReset)
+	placeAtSpawn(player, schema.Position{X: 4, Y: 4})
+	player.LastMoveTime = time.Now()
+
+	if h.validateMovement(game, player, schema.Position{X: 10, Y: 4}) {
+		t.Error("a jump of 6 blocks right after the spawn was accepted")
+	}
+	if player.ViolationCount != 1 {
+		t.Errorf("%d violations, want 1", player.ViolationCount)
+	}
+}
+
+func TestSubMillisecondUpdatesAreNotTooFast(t *testing.T) {
+	h, game, player, client := cheatingPlayer(t, schema.AntiCheatReset)
+
+	// Updates microseconds apart, creeping forward with a little jitter
+	x, y := player.Position.X, player.Position.Y
+	for i := 0; i < 200; i++ {
+		x += 0.0005
+		jitter := 0.01
+		if i%2 == 0 {
+			jitter = -jitter
+		}
+		h.handlePlayerUpdate(game, "alice", playerUpdate(x, y+jitter, 0))
+		if i%4 == 0 {
+			time.Sleep(300 * time.Microsecond)
+		}
+	}
+
+	if player.ViolationCount != 0 {
+		t.Errorf("%d speed violations from sub-millisecond updates", player.ViolationCount)
+	}
+	if rejections := withEvent(received(client), "movement_rejected"); len(rejections) != 0 {
+		t.Errorf("movement_rejected = %v", rejections)
+	}
+	if math.Abs(player.Position.X-x) > 1e-9 {
+		t.Errorf("player at x %.4f, want %.4f", player.Position.X, x)
+	}
+}
@@ -0,0 +1,52 @@
+package game
+
+import (
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+// eliminationBonus rewards a player for the players they outlasted. With
+// placement 1 being the winner, a player outlasted totalPlayers - placement
+// others, so the winner earns the most and the first player out earns nothing.
+// The bonus is never negative, even for placements past totalPlayers.
+func eliminationBonus(cfg schema.GameConfig, totalPlayers, placement int) int {
+	outlasted := totalPlayers - placement
+	if outlasted <= 0 || cfg.EliminationBonusMultiplier <= 0 {
+		return 0
+	}
+
+	switch cfg.EliminationBonusFormula {
+	case schema.BonusPlacementSquared:
+		return cfg.EliminationBonusMultiplier * outlasted * outlasted
+	case schema.BonusFlat:
+		return cfg.EliminationBonusMultiplier
+	default:
+		return cfg.EliminationBonusMultiplier * outlasted
+	}
+}
+
+// awardEliminationBonuses settles every ranked player's bonus once placements are final
+func (h *GameHandler) awardEliminationBonuses(game *schema.Game) {
+	totalPlayers := 0
+	for _, player := range game.Players {
+		if !player.IsSpectator {
+			totalPlayers++
+		}
+	}
+
+	for _, player := range game.Players {
+		if player.IsSpectator {
+			continue
+		}
+		player.Stats.EliminationBonus = eliminationBonus(game.Config, totalPlayers, placement(player))
+	}
+}
+
+// validBonusFormula reports whether the formula is one eliminationBonus knows
+func validBonusFormula(formula schema.BonusFormula) bool {
+	switch formula {
+	case schema.BonusLinear, schema.BonusPlacementSquared, schema.BonusFlat:
+		return true
+	default:
+		return false
+	}
+}
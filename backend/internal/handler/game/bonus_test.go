@@ -0,0 +1,37 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+func TestEliminationBonusFormulas(t *testing.T) {
+	tests := []struct {
+		formula schema.BonusFormula
+		want    map[int]int // placement of 5 players to bonus
+	}{
+		{schema.BonusLinear, map[int]int{1: 8, 2: 6, 4: 2, 5: 0}},
+		{schema.BonusPlacementSquared, map[int]int{1: 32, 2: 18, 4: 2, 5: 0}},
+		{schema.BonusFlat, map[int]int{1: 2, 2: 2, 4: 2, 5: 0}},
+	}
+
+	for _, tt := range tests {
+		cfg := schema.GameConfig{EliminationBonusFormula: tt.formula, EliminationBonusMultiplier: 2}
+		for placement, want := range tt.want {
+			if got := eliminationBonus(cfg, 5, placement); got != want {
+				t.Errorf("%s: placement %d of 5 got %d, want %d", tt.formula, placement, got, want)
+			}
+		}
+
+		// Never negative, whatever the placement or multiplier
+		for _, multiplier := range []int{-3, 0, 2} {
+			cfg.EliminationBonusMultiplier = multiplier
+			for placement := 0; placement <= 8; placement++ {
+				if got := eliminationBonus(cfg, 5, placement); got < 0 {
+					t.Errorf("%s: multiplier %d, placement %d of 5 got %d", tt.formula, multiplier, placement, got)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package game
+
+import (
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+// inBorder reports whether x, y lies in the outer ring of BorderThickness cells
+func inBorder(game *schema.Game, x, y int) bool {
+	t := game.Config.BorderThickness
+	return x < t || y < t || x >= game.Config.MapWidth-t || y >= game.Config.MapHeight-t
+}
+
+// applyBorder turns the border ring into Air walls, so standing on the edge is
+// always fatal instead of a safe spot to exploit the bounds check
+func (h *GameHandler) applyBorder(game *schema.Game) {
+	if game.Config.BorderThickness <= 0 {
+		return
+	}
+	for y := 0; y < game.Config.MapHeight; y++ {
+		for x := 0; x < game.Config.MapWidth; x++ {
+			if inBorder(game, x, y) {
+				game.SetColorAt(x, y, schema.Air)
+			}
+		}
+	}
+}
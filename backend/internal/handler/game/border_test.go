@@ -0,0 +1,62 @@
+package game
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+func TestNobodySpawnsOnTheBorder(t *testing.T) {
+	h, game := newTestGame(t, func(cfg *schema.GameConfig) {
+		cfg.BorderThickness = 2
+	})
+	names := make([]string, 0, 16)
+	for i := 0; i < 16; i++ {
+		names = append(names, fmt.Sprintf("p%d", i))
+	}
+	joinTestPlayers(t, h, game, names...)
+
+	h.startGame(game)
+
+	for name, player := range game.Players {
+		x, y := worldToCell(player.Position, game.Config.CellEpsilon)
+		if inBorder(game, x, y) {
+			t.Errorf("%s spawned on the border at (%d, %d)", name, x, y)
+		}
+	}
+	for i := 0; i < game.Config.MapWidth; i++ {
+		for _, cell := range [][2]int{{i, 0}, {i, 1}, {0, i}, {19, i}} {
+			if color, _ := game.ColorAt(cell[0], cell[1]); color != schema.Air {
+				t.Fatalf("border block (%d, %d) is %v, want Air", cell[0], cell[1], color)
+			}
+		}
+	}
+}
+
+func TestPlayerOnTheBorderIsEliminated(t *testing.T) {
+	h, game := newTestGame(t, func(cfg *schema.GameConfig) {
+		cfg.BorderThickness = 1
+	})
+	joinTestPlayers(t, h, game, "alice", "bob", "carol")
+	h.startGame(game)
+	h.startNewRound(game)
+
+	// Every color is safe, only the wall is fatal
+	allColorsSafe(game)
+	game.Players["alice"].Position = spawnPoint(0, 5)
+	game.Players["bob"].Position = spawnPoint(5, 5)
+	game.Players["carol"].Position = spawnPoint(6, 5)
+	h.handleEliminationCheckPhase(game)
+
+	alice := game.Players["alice"]
+	if !alice.IsEliminated {
+		t.Fatal("alice survived on the border")
+	}
+	if reason := game.Eliminations[0].Reason; reason != schema.EliminatedOnAir {
+		t.Errorf("alice eliminated for %s, want %s", reason, schema.EliminatedOnAir)
+	}
+	if game.Players["bob"].IsEliminated || game.Players["carol"].IsEliminated {
+		t.Error("players inside the border were eliminated")
+	}
+}
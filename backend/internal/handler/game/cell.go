@@ -0,0 +1,18 @@
+package game
+
+import (
+	"math"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+// worldToCell returns the map cell (0-based column and row) a position stands
+// on. A cell owns the half-open range [n-0.5, n+0.5) on each axis, so a
+// position exactly on the boundary between two cells belongs to the higher
+// one. Positions less than epsilon below a boundary are nudged onto it, so
+// float noise like 2.4999999 resolves the same way as 2.5.
+func worldToCell(position schema.Position, epsilon float64) (x, y int) {
+	x = int(math.Floor(position.X + 0.5 + epsilon))
+	y = int(math.Floor(position.Y + 0.5 + epsilon))
+	return x, y
+}
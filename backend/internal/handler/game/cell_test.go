@@ -0,0 +1,37 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+func TestWorldToCellBoundaries(t *testing.T) {
+	tests := []struct {
+		position schema.Position
+		epsilon  float64
+		x, y     int
+	}{
+		{schema.Position{X: 3, Y: 7}, 0, 3, 7},              // Cell centers
+		{schema.Position{X: 2.5, Y: 2.5}, 0, 3, 3},          // A boundary belongs to the higher cell
+		{schema.Position{X: 2.4999, Y: 6.5001}, 0, 2, 7},    // Just either side of a boundary
+		{schema.Position{X: -0.5, Y: 0}, 0, 0, 0},           // The lower edge of the map is in it
+		{schema.Position{X: -0.5001, Y: 19.5}, 0, -1, 20},   // Off the map on both sides
+		{schema.Position{X: 2.4999999, Y: 2.5}, 1e-6, 3, 3}, // Float noise below a boundary is nudged onto it
+		{schema.Position{X: 2.4999, Y: 2.5}, 1e-6, 2, 3},    // Anything further below is not
+		{schema.Position{X: 2.4999999, Y: 0}, 0, 2, 0},      // Without epsilon no nudging
+	}
+	for _, tt := range tests {
+		if x, y := worldToCell(tt.position, tt.epsilon); x != tt.x || y != tt.y {
+			t.Errorf("worldToCell(%v, %g) = (%d, %d), want (%d, %d)", tt.position, tt.epsilon, x, y, tt.x, tt.y)
+		}
+	}
+}
+
+func TestSpawnPointIsInItsCell(t *testing.T) {
+	for _, cell := range [][2]int{{0, 0}, {5, 12}, {19, 19}} {
+		if x, y := worldToCell(spawnPoint(cell[0], cell[1]), 0); x != cell[0] || y != cell[1] {
+			t.Errorf("spawn point of (%d, %d) is in cell (%d, %d)", cell[0], cell[1], x, y)
+		}
+	}
+}
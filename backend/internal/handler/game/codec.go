@@ -0,0 +1,66 @@
+package game
+
+import (
+	"errors"
+	"net/http"
+
+	"golang.org/x/net/websocket"
+
+	"github.com/yorukot/blind-party/pkg/msgpack"
+)
+
+// WebSocket subprotocols selecting the message format
+const (
+	SubprotocolJSON    = "blindparty.json"
+	SubprotocolMsgpack = "blindparty.msgpack"
+)
+
+// maxClientMessageBytes caps the frames read from clients. Their messages are
+// small position updates and pings, the default 32MB only invites abuse.
+const maxClientMessageBytes = 64 << 10
+
+// msgpackCodec sends messages as binary MessagePack frames
+var msgpackCodec = websocket.Codec{
+	Marshal: func(v interface{}) ([]byte, byte, error) {
+		data, err := msgpack.Marshal(v)
+		return data, websocket.BinaryFrame, err
+	},
+	Unmarshal: func(data []byte, payloadType byte, v interface{}) error {
+		return msgpack.Unmarshal(data, v)
+	},
+}
+
+// NegotiateSubprotocol is the WebSocket handshake picking the message format:
+// msgpack if the client offers it, otherwise JSON. Clients that offer no
+// subprotocol get JSON too.
+func NegotiateSubprotocol(config *websocket.Config, req *http.Request) error {
+	// Same origin check as websocket.Handler
+	origin, err := websocket.Origin(config, req)
+	if err != nil {
+		return err
+	}
+	if origin == nil {
+		return errors.New("null origin")
+	}
+	config.Origin = origin
+
+	offered := config.Protocol
+	config.Protocol = nil
+	for _, protocol := range []string{SubprotocolMsgpack, SubprotocolJSON} {
+		for _, candidate := range offered {
+			if candidate == protocol {
+				config.Protocol = []string{protocol}
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+// codecFor returns the codec matching the subprotocol negotiated for the connection
+func codecFor(ws *websocket.Conn) websocket.Codec {
+	if protocols := ws.Config().Protocol; len(protocols) == 1 && protocols[0] == SubprotocolMsgpack {
+		return msgpackCodec
+	}
+	return websocket.JSON
+}
@@ -0,0 +1,105 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/net/websocket"
+
+	"github.com/yorukot/blind-party/internal/schema"
+	"github.com/yorukot/blind-party/pkg/msgpack"
+)
+
+func TestEliminationMessageRoundTripsThroughBothCodecs(t *testing.T) {
+	message := map[string]interface{}{
+		"event": "game_update",
+		"data": map[string]interface{}{
+			"eliminated_players": []string{"alice", "bob"},
+			"elimination_details": []schema.EliminationRecord{
+				{Name: "alice", RoundNumber: 3, Reason: schema.EliminatedWrongColor, Position: schema.Position{X: 4.5, Y: 7.25}, RTTMs: 40, CalledColor: schema.Red},
+				{Name: "bob", RoundNumber: 3, Reason: schema.EliminatedWrongColor, Position: schema.Position{X: 0, Y: 19}, StalenessMs: 1200, CalledColor: schema.Red},
+			},
+			"round_number": 3,
+			"target_color": schema.Red,
+		},
+	}
+
+	var decoded []map[string]interface{}
+	for _, codec := range []websocket.Codec{websocket.JSON, msgpackCodec} {
+		data, _, err := codec.Marshal(message)
+		if err != nil {
+			t.Fatalf("Marshal: %v", err)
+		}
+		var v map[string]interface{}
+		if err := codec.Unmarshal(data, websocket.BinaryFrame, &v); err != nil {
+			t.Fatalf("Unmarshal: %v", err)
+		}
+		decoded = append(decoded, v)
+	}
+
+	// JSON has only floats, msgpack keeps integers as integers
+	if round, ok := decoded[1]["data"].(map[string]interface{})["round_number"].(int8); !ok || round != 3 {
+		t.Errorf("msgpack round_number = %T %v, want integer 3", decoded[1]["data"].(map[string]interface{})["round_number"], round)
+	}
+	if !reflect.DeepEqual(decoded[0], numbersAsFloats(decoded[1])) {
+		t.Errorf("codecs disagree\njson    %v\nmsgpack %v", decoded[0], decoded[1])
+	}
+	details := decoded[1]["data"].(map[string]interface{})["elimination_details"].([]interface{})
+	if got := details[0].(map[string]interface{})["position"]; !reflect.DeepEqual(got, map[string]interface{}{"pos_x": 4.5, "pos_y": 7.25}) {
+		t.Errorf("position = %v", got)
+	}
+}
+
+// numbersAsFloats returns v with every number turned into a float64, the way
+// JSON decodes them
+func numbersAsFloats(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		converted := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			converted[key] = numbersAsFloats(value)
+		}
+		return converted
+	case []interface{}:
+		converted := make([]interface{}, len(v))
+		for i, value := range v {
+			converted[i] = numbersAsFloats(value)
+		}
+		return converted
+	}
+	if number, err := parseFloat(v); err == nil {
+		if _, isString := v.(string); !isString {
+			return number
+		}
+	}
+	return v
+}
+
+func TestMsgpackClientMovesWithIntegers(t *testing.T) {
+	h, game := newTestGame(t, nil)
+	clients := joinTestPlayers(t, h, game, "alice")
+	alice := game.Players["alice"]
+	alice.Position = schema.Position{X: 10, Y: 10}
+
+	// What a msgpack client's {"event":"player_update","seq":1,"player":{"pos_x":11,"pos_y":10}} decodes to
+	data, err := msgpack.Marshal(map[string]interface{}{
+		"event":  "player_update",
+		"seq":    1,
+		"player": map[string]interface{}{"pos_x": 11, "pos_y": 10},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var message map[string]interface{}
+	if err := msgpackCodec.Unmarshal(data, websocket.BinaryFrame, &message); err != nil {
+		t.Fatal(err)
+	}
+	h.handlePlayerUpdate(game, "alice", message)
+
+	if alice.Position != (schema.Position{X: 11, Y: 10}) {
+		t.Errorf("alice at %v, want (11, 10)", alice.Position)
+	}
+	if rejected := withEvent(received(clients["alice"]), "update_rejected"); len(rejected) != 0 {
+		t.Errorf("integer update rejected: %v", rejected)
+	}
+}
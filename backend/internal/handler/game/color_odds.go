@@ -0,0 +1,62 @@
+package game
+
+import (
+	"sort"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+// colorOdds is one color's share of the blocks left on the map
+type colorOdds struct {
+	Color  schema.WoolColor `json:"color"`
+	Name   string           `json:"name"`
+	Blocks int              `json:"blocks"`
+	Share  float64          `json:"share"` // Fraction of the colored blocks, 0 to 1
+}
+
+// broadcastColorOdds tells players how much of each color is left on the map
+// when ShowColorOdds is on. Only colors on the map can be called, so this lets
+// casual players anticipate the likely calls. Callers must hold game.Mu.
+func (h *GameHandler) broadcastColorOdds(game *schema.Game) {
+	if !game.Config.ShowColorOdds {
+		return
+	}
+
+	roundNumber := game.RoundNumber
+	if game.CurrentRound != nil {
+		roundNumber = game.CurrentRound.Number
+	}
+
+	game.Publish(map[string]any{
+		"event": "color_odds",
+		"data": map[string]any{
+			"round_number": roundNumber,
+			"colors":       computeColorOdds(countColorCells(game)),
+		},
+	})
+}
+
+// computeColorOdds turns block counts into shares, most common color first
+func computeColorOdds(counts map[schema.WoolColor]int) []colorOdds {
+	total := 0
+	for _, blocks := range counts {
+		total += blocks
+	}
+
+	odds := make([]colorOdds, 0, len(counts))
+	for color, blocks := range counts {
+		odds = append(odds, colorOdds{
+			Color:  color,
+			Name:   color.String(),
+			Blocks: blocks,
+			Share:  float64(blocks) / float64(total),
+		})
+	}
+	sort.Slice(odds, func(i, j int) bool {
+		if odds[i].Blocks != odds[j].Blocks {
+			return odds[i].Blocks > odds[j].Blocks
+		}
+		return odds[i].Color < odds[j].Color
+	})
+	return odds
+}
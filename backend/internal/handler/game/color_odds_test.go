@@ -0,0 +1,73 @@
+package game
+
+import (
+	"math"
+	"testing"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+// broadcastOdds publishes the color odds and returns each color's share
+func broadcastOdds(t *testing.T, h *GameHandler, game *schema.Game) map[schema.WoolColor]float64 {
+	t.Helper()
+	published(game)
+	h.broadcastColorOdds(game)
+	odds := withEvent(published(game), "color_odds")
+	if len(odds) != 1 {
+		t.Fatalf("got %d color_odds events, want 1", len(odds))
+	}
+	shares := make(map[schema.WoolColor]float64)
+	for _, color := range odds[0]["colors"].([]colorOdds) {
+		shares[color.Color] = color.Share
+	}
+	return shares
+}
+
+func TestColorOddsFollowTheMap(t *testing.T) {
+	h, game := newTestGame(t, func(cfg *schema.GameConfig) { cfg.ShowColorOdds = true })
+	fillMap(game, schema.Red)
+	for x := 0; x < game.Config.MapWidth; x++ {
+		for y := 0; y < 5; y++ {
+			game.SetColorAt(x, y, schema.Blue)
+		}
+		for y := 5; y < 7; y++ {
+			game.SetColorAt(x, y, schema.Green)
+		}
+	}
+
+	assertShares := func(got, want map[schema.WoolColor]float64) {
+		t.Helper()
+		if len(got) != len(want) {
+			t.Errorf("odds for %v, want %v", got, want)
+		}
+		for color, share := range want {
+			if math.Abs(got[color]-share) > 1e-9 {
+				t.Errorf("%s has share %.3f, want %.3f", color, got[color], share)
+			}
+		}
+	}
+
+	assertShares(broadcastOdds(t, h, game), map[schema.WoolColor]float64{
+		schema.Red:   0.65,
+		schema.Blue:  0.25,
+		schema.Green: 0.1,
+	})
+
+	// The unsafe blocks fall away and the odds follow
+	h.removeNonTargetColors(game, &schema.Round{Number: 1, ColorsToShow: []schema.WoolColor{schema.Blue, schema.Green}})
+	assertShares(broadcastOdds(t, h, game), map[schema.WoolColor]float64{
+		schema.Blue:  100.0 / 140,
+		schema.Green: 40.0 / 140,
+	})
+}
+
+func TestColorOddsOffByDefault(t *testing.T) {
+	h, game := newTestGame(t, nil)
+	published(game)
+
+	h.broadcastColorOdds(game)
+
+	if odds := withEvent(published(game), "color_odds"); len(odds) != 0 {
+		t.Errorf("color odds sent with ShowColorOdds off: %v", odds)
+	}
+}
@@ -0,0 +1,67 @@
+package game
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+func TestColorMetadataInStateAndRounds(t *testing.T) {
+	h, game := newTestGame(t, nil)
+	alice := joinTestPlayers(t, h, game, "alice")["alice"]
+
+	initial := withEvent(received(alice), "game_update")
+	if len(initial) != 1 {
+		t.Fatalf("got %d initial game_update messages, want 1", len(initial))
+	}
+	palette, ok := initial[0]["color_palette"].([schema.Air + 1]schema.ColorInfo)
+	if !ok || palette != schema.ColorPalette {
+		t.Errorf("initial state color_palette = %T, want the palette", initial[0]["color_palette"])
+	}
+
+	game.Phase = schema.InGame
+	published(game)
+	h.startNewRound(game)
+	updates := withEvent(published(game), "game_update")
+	if len(updates) == 0 {
+		t.Fatal("round start sent no game_update")
+	}
+	info, ok := updates[0]["target_color_info"].(schema.ColorInfo)
+	if !ok || info != game.CurrentRound.ColorToShow.Info() {
+		t.Errorf("target_color_info = %v, want %v", updates[0]["target_color_info"], game.CurrentRound.ColorToShow.Info())
+	}
+	if _, repeated := updates[0]["color_palette"]; repeated {
+		t.Error("round updates repeat the palette")
+	}
+}
+
+func TestColorPaletteEndpointListsEveryColor(t *testing.T) {
+	rec := httptest.NewRecorder()
+	(&GameHandler{}).GetColorPalette(rec, httptest.NewRequest(http.MethodGet, "/api/colors", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+
+	var body struct {
+		Data []schema.ColorInfo `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.Data) != 17 {
+		t.Fatalf("got %d colors, want 17 including Air", len(body.Data))
+	}
+	seen := make(map[schema.WoolColor]bool)
+	for i, info := range body.Data {
+		if info.ID != schema.WoolColor(i) || seen[info.ID] {
+			t.Errorf("entry %d has index %d", i, info.ID)
+		}
+		seen[info.ID] = true
+		if info != schema.ColorPalette[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, info, schema.ColorPalette[i])
+		}
+	}
+}
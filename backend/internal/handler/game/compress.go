@@ -0,0 +1,41 @@
+package game
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"log"
+)
+
+// compressLargeMessage wraps a message whose JSON is larger than threshold bytes
+// into a game_state_gz event carrying the gzipped, base64 encoded JSON. x/net/websocket
+// has no permessage-deflate, so compression happens at the application level.
+// Smaller messages, or any that fail to compress, are returned unchanged.
+func compressLargeMessage(message interface{}, threshold int) interface{} {
+	if threshold <= 0 {
+		return message
+	}
+
+	payload, err := json.Marshal(message)
+	if err != nil || len(payload) <= threshold {
+		return message
+	}
+
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(payload); err != nil {
+		log.Printf("Error compressing message: %v", err)
+		return message
+	}
+	if err := writer.Close(); err != nil {
+		log.Printf("Error compressing message: %v", err)
+		return message
+	}
+
+	return map[string]interface{}{
+		"event":    "game_state_gz",
+		"encoding": "gzip+base64",
+		"data":     base64.StdEncoding.EncodeToString(buf.Bytes()),
+	}
+}
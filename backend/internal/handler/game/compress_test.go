@@ -0,0 +1,61 @@
+package game
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"testing"
+)
+
+// decompress undoes compressLargeMessage, returning the original JSON
+func decompress(t *testing.T, message interface{}) []byte {
+	t.Helper()
+	wrapped := message.(map[string]interface{})
+	if wrapped["event"] != "game_state_gz" || wrapped["encoding"] != "gzip+base64" {
+		t.Fatalf("got %v, want a game_state_gz message", wrapped["event"])
+	}
+	compressed, err := base64.StdEncoding.DecodeString(wrapped["data"].(string))
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return payload
+}
+
+func TestCompressedStateDecompressesToOriginal(t *testing.T) {
+	h, game := newTestGame(t, nil)
+	joinTestPlayers(t, h, game, "alice", "bob")
+	state := h.createGameStateMessage(game)
+	original, err := json.Marshal(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	compressed := compressLargeMessage(state, 64)
+	payload := decompress(t, compressed)
+	if !bytes.Equal(payload, original) {
+		t.Errorf("decompressed payload differs from the original state")
+	}
+	if sent, _ := json.Marshal(compressed); len(sent) >= len(original) {
+		t.Errorf("compressed message is %d bytes, the original %d", len(sent), len(original))
+	}
+}
+
+func TestSmallMessagesAreSentAsIs(t *testing.T) {
+	message := map[string]interface{}{"event": "pong"}
+	for _, threshold := range []int{0, 1024} {
+		got, ok := compressLargeMessage(message, threshold).(map[string]interface{})
+		if !ok || got["event"] != "pong" {
+			t.Errorf("threshold %d: got %v, want the message unchanged", threshold, got)
+		}
+	}
+}
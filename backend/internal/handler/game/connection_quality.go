@@ -0,0 +1,116 @@
+package game
+
+import (
+	"log"
+	"time"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+// rttSmoothing is the weight of a new RTT sample in the moving average
+const rttSmoothing = 0.2
+
+// pingClients sends a timestamped ping to every client once per PingIntervalMs.
+// Clients echo the timestamp back in a pong so the RTT can be measured.
+func (h *GameHandler) pingClients(game *schema.Game) {
+	interval := time.Duration(game.Config.PingIntervalMs) * time.Millisecond
+	if interval <= 0 || time.Since(game.LastPing) < interval {
+		return
+	}
+	game.LastPing = time.Now()
+
+	ping := map[string]interface{}{
+		"event": "ping",
+		"data": map[string]interface{}{
+			"timestamp": game.LastPing.UnixMilli(),
+		},
+	}
+	for _, client := range game.Clients {
+		select {
+		case client.Send <- ping:
+		default:
+		}
+	}
+}
+
+// handlePong updates the player's smoothed RTT from an echoed ping timestamp
+func (h *GameHandler) handlePong(game *schema.Game, username string, message map[string]interface{}) {
+	data, hasData := message["data"].(map[string]interface{})
+	if !hasData {
+		return
+	}
+	timestamp, err := parseFloat(data["timestamp"])
+	if err != nil {
+		log.Printf("Invalid pong timestamp from user %s: %v", username, err)
+		return
+	}
+
+	rtt := time.Since(time.UnixMilli(int64(timestamp)))
+	if rtt < 0 {
+		return
+	}
+
+	game.Mu.Lock()
+	defer game.Mu.Unlock()
+
+	player, exists := game.Players[username]
+	if !exists {
+		return
+	}
+	if player.SmoothedRTT == 0 {
+		player.SmoothedRTT = rtt
+	} else {
+		player.SmoothedRTT = time.Duration(rttSmoothing*float64(rtt) + (1-rttSmoothing)*float64(player.SmoothedRTT))
+	}
+	player.RTTMs = int(player.SmoothedRTT.Round(time.Millisecond).Milliseconds())
+}
+
+// updateStaleness records how long ago each player last sent a position update
+func (h *GameHandler) updateStaleness(game *schema.Game) {
+	now := time.Now()
+	for _, player := range game.Players {
+		player.StalenessMs = int(now.Sub(player.LastUpdate).Round(time.Millisecond).Milliseconds())
+	}
+}
+
+// lagCompensationFor returns the player's lag compensation window. Players whose
+// updates are staler than StalenessThresholdMs get the window extended by the
+// excess, up to MaxLagCompensationMs.
+func (h *GameHandler) lagCompensationFor(game *schema.Game, player *schema.Player) time.Duration {
+	window := game.Config.LagCompensationMs
+	if excess := player.StalenessMs - game.Config.StalenessThresholdMs; excess > 0 {
+		window += excess
+		if window > game.Config.MaxLagCompensationMs {
+			window = game.Config.MaxLagCompensationMs
+		}
+	}
+	return time.Duration(window) * time.Millisecond
+}
+
+// rushGracePeriod returns the longest lag compensation window among alive players,
+// the time the rush is held open after the countdown so late updates can land
+func (h *GameHandler) rushGracePeriod(game *schema.Game) time.Duration {
+	var grace time.Duration
+	for _, player := range game.Players {
+		if player.IsEliminated || player.IsSpectator {
+			continue
+		}
+		if window := h.lagCompensationFor(game, player); window > grace {
+			grace = window
+		}
+	}
+	return grace
+}
+
+// newEliminationRecord snapshots the player's position and connection quality
+func newEliminationRecord(game *schema.Game, player *schema.Player, reason schema.EliminationReason) schema.EliminationRecord {
+	return schema.EliminationRecord{
+		Name:        player.Name,
+		RoundNumber: game.CurrentRound.Number,
+		Reason:      reason,
+		Position:    judgedPosition(game.CurrentRound, player),
+		RTTMs:       player.RTTMs,
+		StalenessMs: player.StalenessMs,
+		CalledColor: game.CurrentRound.ColorToShow,
+	}
+}
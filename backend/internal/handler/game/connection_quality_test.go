@@ -0,0 +1,76 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+// pong echoes a ping sent delay ago
+func pong(delay time.Duration) map[string]interface{} {
+	return map[string]interface{}{
+		"event": "pong",
+		"data":  map[string]interface{}{"timestamp": float64(time.Now().Add(-delay).UnixMilli())},
+	}
+}
+
+func TestSmoothedRTTConvergesAndIsBroadcast(t *testing.T) {
+	h, game := newTestGame(t, nil)
+	joinTestPlayers(t, h, game, "alice")
+	alice := game.Players["alice"]
+
+	h.handlePong(game, "alice", pong(600*time.Millisecond))
+	if alice.RTTMs < 590 || alice.RTTMs > 650 {
+		t.Fatalf("first sample: rtt = %dms, want about 600", alice.RTTMs)
+	}
+	for i := 0; i < 30; i++ {
+		h.handlePong(game, "alice", pong(200*time.Millisecond))
+	}
+	if alice.RTTMs < 195 || alice.RTTMs > 230 {
+		t.Errorf("after 30 samples of 200ms: rtt = %dms", alice.RTTMs)
+	}
+
+	h.handlePong(game, "alice", map[string]interface{}{"data": map[string]interface{}{"timestamp": "soon"}})
+	h.handlePong(game, "alice", pong(-time.Minute))
+	if alice.RTTMs < 195 || alice.RTTMs > 230 {
+		t.Errorf("invalid pongs changed the rtt to %dms", alice.RTTMs)
+	}
+
+	state := h.createGameStateMessage(game)["data"].(map[string]interface{})
+	players := state["players"].([]schema.Player)
+	if len(players) != 1 || players[0].RTTMs != alice.RTTMs {
+		t.Errorf("broadcast players = %+v, want rtt_ms %d", players, alice.RTTMs)
+	}
+
+	game.CurrentRound = &schema.Round{Number: 1, ColorToShow: schema.Red}
+	if record := newEliminationRecord(game, alice, schema.EliminatedWrongColor); record.RTTMs != alice.RTTMs {
+		t.Errorf("elimination record rtt = %d, want %d", record.RTTMs, alice.RTTMs)
+	}
+}
+
+func TestStalePlayersGetLongerLagCompensation(t *testing.T) {
+	h, game := newTestGame(t, func(cfg *schema.GameConfig) {
+		cfg.LagCompensationMs = 100
+		cfg.StalenessThresholdMs = 300
+		cfg.MaxLagCompensationMs = 500
+	})
+	joinTestPlayers(t, h, game, "alice")
+	alice := game.Players["alice"]
+
+	for _, tt := range []struct {
+		staleness time.Duration
+		want      time.Duration
+	}{
+		{0, 100 * time.Millisecond},
+		{300 * time.Millisecond, 100 * time.Millisecond},
+		{450 * time.Millisecond, 250 * time.Millisecond},
+		{5 * time.Second, 500 * time.Millisecond},
+	} {
+		alice.LastUpdate = time.Now().Add(-tt.staleness)
+		h.updateStaleness(game)
+		if got := h.lagCompensationFor(game, alice); got < tt.want || got > tt.want+10*time.Millisecond {
+			t.Errorf("%v stale: window = %v, want %v", tt.staleness, got, tt.want)
+		}
+	}
+}
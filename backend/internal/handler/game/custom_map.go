@@ -0,0 +1,118 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/yorukot/blind-party/internal/config"
+	"github.com/yorukot/blind-party/internal/schema"
+	"github.com/yorukot/blind-party/pkg/response"
+)
+
+// uploadMapRequest is the body of UploadMap
+type uploadMapRequest struct {
+	UserID string  `json:"user_id"`
+	Map    [][]int `json:"map"` // Rows of color IDs, map_height rows of map_width cells
+}
+
+// uploadMapResponse is the response of UploadMap
+type uploadMapResponse struct {
+	GameID    string `json:"game_id"`
+	Spawnable int    `json:"spawnable"`
+}
+
+// UploadMap lets the host replace the map with a hand-designed one before the
+// game starts. The custom map is played in the first round, later rounds
+// generate their maps as usual.
+func (h *GameHandler) UploadMap(w http.ResponseWriter, r *http.Request) {
+	gameID := chi.URLParam(r, "gameID")
+	if gameID == "" {
+		response.Fail(w, http.StatusBadRequest, "MISSING_GAME_ID", "Game ID is required")
+		return
+	}
+
+	game, exists := h.getGame(gameID)
+	if !exists {
+		response.Fail(w, http.StatusNotFound, "GAME_NOT_FOUND", "Game not found")
+		return
+	}
+
+	var req uploadMapRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Fail(w, http.StatusBadRequest, "INVALID_REQUEST_BODY", "Request body must be valid JSON")
+		return
+	}
+
+	game.Mu.Lock()
+	defer game.Mu.Unlock()
+
+	if game.HostID == "" || req.UserID != game.HostID {
+		response.Fail(w, http.StatusForbidden, "HOST_ONLY", "Only the host can upload a map")
+		return
+	}
+	if game.Phase != schema.PreGame {
+		response.Fail(w, http.StatusConflict, "GAME_ALREADY_STARTED", "The map can only be uploaded before the game starts")
+		return
+	}
+
+	spawnable, problem := validateCustomMap(game, req.Map, config.Env().MaxPlayers)
+	if problem != "" {
+		response.FailValidation(w, map[string]string{"map": problem})
+		return
+	}
+
+	for y, row := range req.Map {
+		for x, color := range row {
+			game.SetColorAt(x, y, schema.WoolColor(color))
+		}
+	}
+	h.applyBorder(game)
+	game.CustomMap = true
+	h.syncMapArray(game)
+	log.Printf("Host %s uploaded a custom map for game %s with %d spawnable blocks", req.UserID, game.ID, spawnable)
+
+	game.Publish(map[string]interface{}{
+		"event": "map_changed",
+		"data": map[string]interface{}{
+			"game_id": game.ID,
+			"map":     game.MapArray,
+			"custom":  true,
+		},
+	})
+
+	response.OK(w, uploadMapResponse{GameID: game.ID, Spawnable: spawnable})
+}
+
+// validateCustomMap checks an uploaded map against the game's map size and
+// palette. It returns the number of blocks players can spawn on, or a problem
+// describing why the map was rejected.
+func validateCustomMap(game *schema.Game, grid [][]int, maxPlayers int) (int, string) {
+	if len(grid) != game.Config.MapHeight {
+		return 0, fmt.Sprintf("must have %d rows, got %d", game.Config.MapHeight, len(grid))
+	}
+
+	spawnable := 0
+	for y, row := range grid {
+		if len(row) != game.Config.MapWidth {
+			return 0, fmt.Sprintf("row %d must have %d cells, got %d", y, game.Config.MapWidth, len(row))
+		}
+		for x, color := range row {
+			if color < 0 || color > int(schema.Air) {
+				return 0, fmt.Sprintf("cell (%d, %d) has invalid color %d, must be between 0 and %d", x, y, color, schema.Air)
+			}
+			if color != int(schema.Air) && !inBorder(game, x, y) {
+				spawnable++
+			}
+		}
+	}
+
+	// Every player needs a colored block to spawn on
+	if spawnable < maxPlayers {
+		return spawnable, fmt.Sprintf("has %d spawnable (non-Air) blocks but up to %d players can join", spawnable, maxPlayers)
+	}
+	return spawnable, ""
+}
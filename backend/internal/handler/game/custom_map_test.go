@@ -0,0 +1,153 @@
+package game
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/yorukot/blind-party/internal/schema"
+	"github.com/yorukot/blind-party/pkg/response"
+)
+
+func uploadMap(t *testing.T, h *GameHandler, gameID string, body map[string]any) *httptest.ResponseRecorder {
+	t.Helper()
+	return serveRoute(h.UploadMap, http.MethodPost, "/api/game/{gameID}/map", "/api/game/"+gameID+"/map", jsonBody(t, body))
+}
+
+// diagonalMap returns a width x height map of diagonal color stripes
+func diagonalMap(width, height int) [][]int {
+	grid := make([][]int, height)
+	for y := range grid {
+		grid[y] = make([]int, width)
+		for x := range grid[y] {
+			grid[y][x] = (x + y) % int(schema.Air)
+		}
+	}
+	return grid
+}
+
+func TestUploadedMapIsPlayedFirst(t *testing.T) {
+	h, game := newTestGame(t, nil)
+	game.HostID = "id-alice"
+	h.storeGame(game)
+
+	grid := diagonalMap(game.Config.MapWidth, game.Config.MapHeight)
+	rec := uploadMap(t, h, game.ID, map[string]any{"user_id": "id-alice", "map": grid})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	if changes := withEvent(published(game), "map_changed"); len(changes) != 1 || changes[0]["custom"] != true {
+		t.Errorf("got map_changed events %v, want one custom", changes)
+	}
+
+	joinTestPlayers(t, h, game, "alice", "bob")
+	h.startGame(game)
+	h.startNewRound(game)
+	for y, row := range grid {
+		for x, want := range row {
+			if color, _ := game.ColorAt(x, y); color != schema.WoolColor(want) {
+				t.Fatalf("round 1 has %s at (%d, %d), the upload had %s", color, x, y, schema.WoolColor(want))
+			}
+		}
+	}
+}
+
+func TestUploadMapRejected(t *testing.T) {
+	tests := []struct {
+		name  string
+		grid  func(width, height int) [][]int
+		field string
+	}{
+		{"too few rows", func(width, height int) [][]int { return diagonalMap(width, height-1) }, "rows"},
+		{"short row", func(width, height int) [][]int {
+			grid := diagonalMap(width, height)
+			grid[3] = grid[3][:width-1]
+			return grid
+		}, "row 3"},
+		{"bad color", func(width, height int) [][]int {
+			grid := diagonalMap(width, height)
+			grid[2][5] = int(schema.Air) + 1
+			return grid
+		}, "invalid color"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, game := newTestGame(t, nil)
+			game.HostID = "id-alice"
+			h.storeGame(game)
+			before := game.Map
+
+			rec := uploadMap(t, h, game.ID, map[string]any{
+				"user_id": "id-alice",
+				"map":     tt.grid(game.Config.MapWidth, game.Config.MapHeight),
+			})
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+			}
+			var body response.Response
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			if body.Error == nil || !strings.Contains(body.Error.Fields["map"], tt.field) {
+				t.Errorf("error %+v does not mention %q", body.Error, tt.field)
+			}
+			if game.CustomMap || game.Map != before {
+				t.Error("the rejected map was applied")
+			}
+		})
+	}
+}
+
+func TestUploadMapHostOnly(t *testing.T) {
+	h, game := newTestGame(t, nil)
+	game.HostID = "id-alice"
+	h.storeGame(game)
+
+	grid := diagonalMap(game.Config.MapWidth, game.Config.MapHeight)
+	if rec := uploadMap(t, h, game.ID, map[string]any{"user_id": "id-bob", "map": grid}); rec.Code != http.StatusForbidden {
+		t.Errorf("guest: status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+}
+
+func TestUploadMapRejectsIDsCopiedFromTheState(t *testing.T) {
+	h, game := newTestGame(t, nil)
+	game.HostID = "id-alice"
+	h.storeGame(game)
+	joinTestPlayers(t, h, game, "alice", "bob")
+
+	// Whatever a guest can read from the state, tried as the host's user_id
+	var state any
+	if err := json.Unmarshal(gameState(t, h, game.ID), &state); err != nil {
+		t.Fatal(err)
+	}
+	var values []string
+	var collect func(v any)
+	collect = func(v any) {
+		switch v := v.(type) {
+		case string:
+			values = append(values, v)
+		case map[string]any:
+			for _, field := range v {
+				collect(field)
+			}
+		case []any:
+			for _, item := range v {
+				collect(item)
+			}
+		}
+	}
+	collect(state)
+
+	grid := diagonalMap(game.Config.MapWidth, game.Config.MapHeight)
+	for _, userID := range values {
+		if rec := uploadMap(t, h, game.ID, map[string]any{"user_id": userID, "map": grid}); rec.Code != http.StatusForbidden {
+			t.Errorf("user_id %q from the state: status = %d, want %d", userID, rec.Code, http.StatusForbidden)
+		}
+	}
+	if game.CustomMap {
+		t.Error("a guest replaced the map")
+	}
+}
@@ -0,0 +1,50 @@
+package game
+
+import (
+	"sort"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+// colorDeaths is one called color's toll over the game
+type colorDeaths struct {
+	Color        schema.WoolColor `json:"color"`
+	ColorInfo    schema.ColorInfo `json:"color_info"`
+	Eliminations int              `json:"eliminations"` // Players eliminated off a safe block in rounds the color was called
+	RoundsCalled int              `json:"rounds_called"`
+}
+
+// deadliestColors aggregates the game's eliminations by the color called in
+// their round, deadliest first. Only players caught off a safe block count,
+// on the wrong color or on Air, not forfeits or party mode's slowest player.
+// Colors called without eliminating anyone are listed with zero.
+func deadliestColors(game *schema.Game) []colorDeaths {
+	byColor := make(map[schema.WoolColor]*colorDeaths)
+	entry := func(color schema.WoolColor) *colorDeaths {
+		if byColor[color] == nil {
+			byColor[color] = &colorDeaths{Color: color, ColorInfo: color.Info()}
+		}
+		return byColor[color]
+	}
+
+	for _, round := range game.Rounds {
+		entry(round.ColorToShow).RoundsCalled++
+	}
+	for _, record := range game.Eliminations {
+		if record.Reason == schema.EliminatedWrongColor || record.Reason == schema.EliminatedOnAir {
+			entry(record.CalledColor).Eliminations++
+		}
+	}
+
+	deadliest := make([]colorDeaths, 0, len(byColor))
+	for _, deaths := range byColor {
+		deadliest = append(deadliest, *deaths)
+	}
+	sort.Slice(deadliest, func(i, j int) bool {
+		if deadliest[i].Eliminations != deadliest[j].Eliminations {
+			return deadliest[i].Eliminations > deadliest[j].Eliminations
+		}
+		return deadliest[i].Color < deadliest[j].Color
+	})
+	return deadliest
+}
@@ -0,0 +1,69 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+func TestDeadliestColorsAddUpTheRounds(t *testing.T) {
+	h, game, _ := startTestGame(t, nil, "p1", "p2", "p3", "p4", "p5", "p6", "p7", "p8")
+
+	wrong := [][]string{{"p8", "p7"}, {"p6"}, nil, {"p5", "p4"}}
+	want := make(map[schema.WoolColor]colorDeaths)
+	for number, names := range wrong {
+		judgeRound(t, h, game, names...)
+		if game.Phase != schema.InGame {
+			t.Fatalf("game ended in round %d", number+1)
+		}
+		called := game.Rounds[len(game.Rounds)-1].ColorToShow
+		deaths := want[called]
+		deaths.Eliminations += len(names)
+		deaths.RoundsCalled++
+		want[called] = deaths
+	}
+
+	deadliest := deadliestColors(game)
+	if len(deadliest) != len(want) {
+		t.Fatalf("%d colors listed, want the %d called", len(deadliest), len(want))
+	}
+	total := 0
+	for i, deaths := range deadliest {
+		if w := want[deaths.Color]; deaths.Eliminations != w.Eliminations || deaths.RoundsCalled != w.RoundsCalled {
+			t.Errorf("%s eliminated %d in %d rounds, want %d in %d", deaths.Color, deaths.Eliminations, deaths.RoundsCalled, w.Eliminations, w.RoundsCalled)
+		}
+		if i > 0 && deadliest[i-1].Eliminations < deaths.Eliminations {
+			t.Errorf("%s listed after the less deadly %s", deaths.Color, deadliest[i-1].Color)
+		}
+		total += deaths.Eliminations
+	}
+	if total != 5 || total != len(game.Eliminations) {
+		t.Errorf("%d eliminations attributed to colors, %d recorded, want 5", total, len(game.Eliminations))
+	}
+	for _, record := range game.Eliminations {
+		if called := game.Rounds[record.RoundNumber-1].ColorToShow; record.CalledColor != called {
+			t.Errorf("%s's elimination records %s, round %d called %s", record.Name, record.CalledColor, record.RoundNumber, called)
+		}
+	}
+}
+
+func TestForfeitsDontCountAgainstTheColor(t *testing.T) {
+	h, game, _ := startTestGame(t, nil, "p1", "p2", "p3", "p4")
+	safe, _ := startTestRound(t, h, game)
+	for _, player := range game.Players {
+		player.Position = safe
+	}
+	h.handleForfeit(game, "p4")
+	if len(game.Eliminations) != 1 {
+		t.Fatalf("%d eliminations recorded for the forfeit, want 1", len(game.Eliminations))
+	}
+	h.handleEliminationCheckPhase(game)
+
+	deadliest := deadliestColors(game)
+	if len(deadliest) != 1 {
+		t.Fatalf("%d colors listed after one round, want 1", len(deadliest))
+	}
+	if deadliest[0].Eliminations != 0 || deadliest[0].RoundsCalled != 1 {
+		t.Errorf("%s eliminated %d in %d rounds, want 0 in 1", deadliest[0].Color, deadliest[0].Eliminations, deadliest[0].RoundsCalled)
+	}
+}
@@ -0,0 +1,51 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+// sameName reports whether two names only differ in case or surrounding spaces,
+// so they would be confused in the roster
+func sameName(a, b string) bool {
+	return strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b))
+}
+
+// displayNameFor picks the display name of a player joining as name. A name
+// no other player's, or their display name, looks like is shown as is.
+// Otherwise it is numbered, e.g. "Alice (2)", or with UniqueNames rejected,
+// reporting false.
+func displayNameFor(game *schema.Game, name string) (string, bool) {
+	lookalike := false
+	for _, player := range game.Players {
+		if player.Name != name && (sameName(player.Name, name) || sameName(player.DisplayName, name)) {
+			lookalike = true
+			break
+		}
+	}
+	if !lookalike {
+		return name, true
+	}
+	if game.Config.UniqueNames {
+		return "", false
+	}
+
+	for n := 2; ; n++ {
+		candidate := fmt.Sprintf("%s (%d)", name, n)
+		if !displayNameTaken(game, candidate) {
+			return candidate, true
+		}
+	}
+}
+
+// displayNameTaken reports whether a player in the game is already shown as displayName
+func displayNameTaken(game *schema.Game, displayName string) bool {
+	for _, player := range game.Players {
+		if sameName(player.DisplayName, displayName) || sameName(player.Name, displayName) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+func TestLookalikeNamesAreNumbered(t *testing.T) {
+	h, game := newTestGame(t, nil)
+	joinTestPlayers(t, h, game, "Alice", "alice", "ALICE", "Bob", "Alice (2)")
+
+	want := map[string]string{
+		"Alice":     "Alice",
+		"alice":     "alice (2)",
+		"ALICE":     "ALICE (3)",
+		"Bob":       "Bob",
+		"Alice (2)": "Alice (2) (2)", // Looks like alice's display name
+	}
+	for name, displayName := range want {
+		player, joined := game.Players[name]
+		if !joined {
+			t.Errorf("%s was rejected", name)
+			continue
+		}
+		if player.DisplayName != displayName {
+			t.Errorf("%s is shown as %q, want %q", name, player.DisplayName, displayName)
+		}
+	}
+}
+
+func TestUniqueNamesRejectsLookalikes(t *testing.T) {
+	h, game := newTestGame(t, func(cfg *schema.GameConfig) { cfg.UniqueNames = true })
+	joinTestPlayers(t, h, game, "Alice")
+
+	for _, name := range []string{"alice", " ALICE "} {
+		client := newTestClient(name, "id-"+name)
+		h.handleClientRegister(game, client)
+		if _, joined := game.Players[name]; joined || client.CloseCode != closeDuplicateName.Code {
+			t.Errorf("%q joined %v, closed with %d, want %d", name, joined, client.CloseCode, closeDuplicateName.Code)
+		}
+	}
+	if game.PlayerCount != 1 || game.Players["Alice"].DisplayName != "Alice" {
+		t.Errorf("%d players, Alice shown as %q", game.PlayerCount, game.Players["Alice"].DisplayName)
+	}
+
+	// Other names are still welcome
+	bob := newTestClient("Bob", "id-Bob")
+	h.handleClientRegister(game, bob)
+	if bob.CloseCode != 0 || game.Players["Bob"] == nil {
+		t.Errorf("Bob closed with %d", bob.CloseCode)
+	}
+}
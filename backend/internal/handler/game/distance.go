@@ -0,0 +1,34 @@
+package game
+
+import (
+	"math"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+// recordDistance adds the move to newPosition to the player's total distance
+// and to the distance traveled in the current round
+func recordDistance(game *schema.Game, player *schema.Player, newPosition schema.Position) {
+	distance := math.Hypot(newPosition.X-player.Position.X, newPosition.Y-player.Position.Y)
+	player.Stats.TotalDistance += distance
+
+	if game.CurrentRound == nil {
+		return
+	}
+	if player.DistanceRound != game.CurrentRound.Number {
+		player.DistanceRound = game.CurrentRound.Number
+		player.RoundDistance = 0
+	}
+	player.RoundDistance += distance
+}
+
+// distancePoints returns the points for the blocks the player traveled in the
+// round, DistancePointsPer each and at most DistancePointsRoundCap, so small
+// back and forth moves can't add up to more than a round's worth
+func distancePoints(cfg schema.GameConfig, player *schema.Player, roundNumber int) int {
+	if cfg.DistancePointsPer <= 0 || player.DistanceRound != roundNumber {
+		return 0
+	}
+	points := int(player.RoundDistance * cfg.DistancePointsPer)
+	return min(points, cfg.DistancePointsRoundCap)
+}
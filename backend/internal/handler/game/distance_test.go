@@ -0,0 +1,80 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+// walk moves the player a block along x and back, steps times
+func walk(game *schema.Game, player *schema.Player, steps int) {
+	for i := 0; i < steps; i++ {
+		next := player.Position
+		if i%2 == 0 {
+			next.X++
+		} else {
+			next.X--
+		}
+		recordDistance(game, player, next)
+		player.Position = next
+	}
+}
+
+func TestDistancePointsUpToTheCap(t *testing.T) {
+	h, game, _ := startTestGame(t, func(cfg *schema.GameConfig) {
+		cfg.DistancePointsPer = 1
+		cfg.DistancePointsRoundCap = 5
+		cfg.CatchupBonus = 0
+	}, "alice", "bob", "carol")
+	alice, bob, carol := game.Players["alice"], game.Players["bob"], game.Players["carol"]
+
+	h.startNewRound(game)
+	walk(game, alice, 3)
+	walk(game, bob, 40) // Jitter farming
+	h.calculateRoundScores(game, game.CurrentRound)
+	for player, want := range map[*schema.Player]int{alice: 3, bob: 5, carol: 0} {
+		if got := player.Stats.DistancePoints; got != want {
+			t.Errorf("%s earned %d distance points, want %d", player.Name, got, want)
+		}
+	}
+	if alice.Stats.TotalDistance != 3 || bob.Stats.TotalDistance != 40 {
+		t.Errorf("total distances %.1f and %.1f, want 3 and 40", alice.Stats.TotalDistance, bob.Stats.TotalDistance)
+	}
+
+	// The cap is per round, the next one starts from nothing
+	game.CurrentRound = nil
+	h.startNewRound(game)
+	walk(game, bob, 2)
+	h.calculateRoundScores(game, game.CurrentRound)
+	if bob.Stats.DistancePoints != 7 || alice.Stats.DistancePoints != 3 {
+		t.Errorf("after round 2 bob has %d distance points and alice %d, want 7 and 3", bob.Stats.DistancePoints, alice.Stats.DistancePoints)
+	}
+	if totalScore(bob) != bob.Stats.SurvivalPoints+7 {
+		t.Errorf("bob's total %d leaves out their distance points", totalScore(bob))
+	}
+}
+
+func TestOnlyInGameMovesCountAsDistance(t *testing.T) {
+	h, game := newTestGame(t, nil)
+	joinTestPlayers(t, h, game, "alice")
+	alice := game.Players["alice"]
+	alice.Position = schema.Position{X: 5, Y: 5}
+
+	h.handlePlayerUpdate(game, "alice", playerUpdate(7, 5, 0))
+	if alice.Position.X != 7 || alice.Stats.TotalDistance != 0 {
+		t.Fatalf("lobby move to %.1f counted %.1f blocks", alice.Position.X, alice.Stats.TotalDistance)
+	}
+
+	h.initializeAllPlayerStats(game)
+	game.Phase = schema.InGame
+	h.startNewRound(game)
+	alice.Position = schema.Position{X: 5, Y: 5}
+	alice.LastValidPosition = alice.Position
+	alice.LastMoveTime = time.Now().Add(-time.Second)
+	positionHistory(game, "alice").Reset() // The lobby move would look like a teleport
+	h.handlePlayerUpdate(game, "alice", playerUpdate(6, 5, 0))
+	if alice.Position.X != 6 || alice.Stats.TotalDistance != 1 || alice.RoundDistance != 1 {
+		t.Errorf("in game move to %.1f counted %.1f blocks, %.1f this round", alice.Position.X, alice.Stats.TotalDistance, alice.RoundDistance)
+	}
+}
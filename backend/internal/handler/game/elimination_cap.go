@@ -0,0 +1,56 @@
+package game
+
+import (
+	"log"
+	"sort"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+// caughtPlayer is a player found off a safe block in the elimination check
+type caughtPlayer struct {
+	player *schema.Player
+	reason schema.EliminationReason
+}
+
+// orderForEliminationCap puts the caught players in the order they are
+// eliminated in when MaxEliminationsPerRound may spare some of them: slowest
+// responder first, or shuffled with EliminationCapRandom
+func (h *GameHandler) orderForEliminationCap(game *schema.Game, caught []caughtPlayer) {
+	if game.Config.MaxEliminationsPerRound <= 0 {
+		return
+	}
+
+	round := game.CurrentRound
+	sort.Slice(caught, func(i, j int) bool {
+		return respondedSlower(round, caught[i].player, caught[j].player)
+	})
+	if game.Config.EliminationCapMode == schema.EliminationCapRandom {
+		for i := len(caught) - 1; i > 0; i-- {
+			j := h.randIntn(i + 1)
+			caught[i], caught[j] = caught[j], caught[i]
+		}
+	}
+}
+
+// spareOverEliminationCap keeps a caught player in the game once eliminated
+// players already reached the round's MaxEliminationsPerRound, privately
+// warning them like a warmup miss. It reports whether the player was spared.
+func (h *GameHandler) spareOverEliminationCap(game *schema.Game, player *schema.Player, reason schema.EliminationReason, eliminated int) bool {
+	if game.Config.MaxEliminationsPerRound <= 0 || eliminated < game.Config.MaxEliminationsPerRound {
+		return false
+	}
+
+	player.SparedIn = game.CurrentRound.Number
+	log.Printf("Player %s spared (%s) in round %d of game %s, %d eliminations reached the cap",
+		player.Name, reason, game.CurrentRound.Number, game.ID, eliminated)
+	sendToClient(game, player.Name, map[string]any{
+		"event": "would_be_eliminated",
+		"data": map[string]any{
+			"round_number": game.CurrentRound.Number,
+			"reason":       reason,
+			"spared_by":    "elimination_cap",
+		},
+	})
+	return true
+}
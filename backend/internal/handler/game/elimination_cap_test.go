@@ -0,0 +1,73 @@
+package game
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+func TestEliminationCapSparesTheRest(t *testing.T) {
+	for _, mode := range []schema.EliminationCapMode{schema.EliminationCapSlowest, schema.EliminationCapRandom} {
+		t.Run(string(mode), func(t *testing.T) {
+			names := []string{"p1", "p2", "p3", "p4", "p5", "p6", "p7"}
+			h, game, clients := startTestGame(t, func(cfg *schema.GameConfig) {
+				cfg.MaxEliminationsPerRound = 2
+				cfg.EliminationCapMode = mode
+			}, names...)
+			safe, unsafe := startTestRound(t, h, game)
+			round := game.CurrentRound
+
+			// Everyone but p1 is caught, p7 took the longest to settle and p6 the second longest
+			for i, name := range names {
+				player := game.Players[name]
+				player.Position = unsafe
+				player.SettledAt = round.StartTime.Add(time.Duration(i+1) * time.Second)
+			}
+			game.Players["p1"].Position = safe
+
+			h.handleEliminationCheckPhase(game)
+
+			var eliminated, spared []string
+			for _, name := range names[1:] {
+				player := game.Players[name]
+				warnings := withEvent(received(clients[name]), "would_be_eliminated")
+				switch {
+				case player.IsEliminated:
+					eliminated = append(eliminated, name)
+				case player.SparedIn == round.Number && len(warnings) == 1 && warnings[0]["spared_by"] == "elimination_cap":
+					spared = append(spared, name)
+				default:
+					t.Errorf("%s was neither eliminated nor spared with a warning", name)
+				}
+			}
+			if len(eliminated) != 2 || len(spared) != 4 {
+				t.Fatalf("eliminated %v and spared %v, want 2 and 4", eliminated, spared)
+			}
+			if mode == schema.EliminationCapSlowest && fmt.Sprint(eliminated) != "[p6 p7]" {
+				t.Errorf("eliminated %v, want the slowest p6 and p7", eliminated)
+			}
+			if game.AliveCount != 5 || game.Phase != schema.InGame {
+				t.Errorf("%d alive in %s, want 5 playing on", game.AliveCount, game.Phase)
+			}
+
+			// Spared players score nothing for the round
+			for _, name := range spared {
+				if points := game.Players[name].Stats.SurvivalPoints; points != 0 {
+					t.Errorf("spared %s scored %d", name, points)
+				}
+			}
+		})
+	}
+}
+
+func TestNoEliminationCapByDefault(t *testing.T) {
+	names := []string{"p1", "p2", "p3", "p4", "p5"}
+	h, game, _ := startTestGame(t, nil, names...)
+
+	judgeRound(t, h, game, names[1:]...)
+	if game.AliveCount != 1 {
+		t.Errorf("%d alive, want only p1", game.AliveCount)
+	}
+}
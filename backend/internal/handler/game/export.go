@@ -0,0 +1,180 @@
+package game
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/yorukot/blind-party/internal/schema"
+	"github.com/yorukot/blind-party/pkg/response"
+)
+
+// utf8BOM makes Excel open the CSV as UTF-8
+const utf8BOM = "\xEF\xBB\xBF"
+
+// ExportPlayersCSV downloads one row per player with their final placement and stats
+func (h *GameHandler) ExportPlayersCSV(w http.ResponseWriter, r *http.Request) {
+	game, ok := h.exportableGame(w, r)
+	if !ok {
+		return
+	}
+
+	// Snapshot the players under the lock, their stats don't change in settlement
+	game.Mu.RLock()
+	players := make([]*schema.Player, 0, len(game.Players))
+	for _, player := range game.Players {
+		players = append(players, player)
+	}
+	eliminations := make(map[string]schema.EliminationRecord, len(game.Eliminations))
+	for _, record := range game.Eliminations {
+		eliminations[record.Name] = record
+	}
+	game.Mu.RUnlock()
+
+	// Ranked players first, spectators last
+	sort.SliceStable(players, func(i, j int) bool {
+		if players[i].IsSpectator != players[j].IsSpectator {
+			return !players[i].IsSpectator
+		}
+		return placement(players[i]) < placement(players[j])
+	})
+
+	writer := startCSV(w, r, game, "players")
+	writer.Write([]string{
+		"name", "user_id", "placement", "score", "survival_points", "speed_bonuses", "streak_bonuses",
+		"rounds_survived", "longest_streak", "average_response_time", "joined_round", "eliminated_in_round",
+		"elimination_reason", "elimination_bonus", "rtt_ms", "is_spectator",
+	})
+	for _, player := range players {
+		rank, eliminatedRound, reason := "", "", ""
+		if !player.IsSpectator {
+			rank = strconv.Itoa(placement(player))
+		}
+		if record, eliminated := eliminations[player.Name]; eliminated {
+			eliminatedRound = strconv.Itoa(record.RoundNumber)
+			reason = string(record.Reason)
+		}
+
+		writer.Write([]string{
+			player.Name,
+			player.UserID,
+			rank,
+			strconv.Itoa(finalScore(player)),
+			strconv.Itoa(player.Stats.SurvivalPoints),
+			strconv.Itoa(player.Stats.SpeedBonuses),
+			strconv.Itoa(player.Stats.HookBonuses["streak"]),
+			strconv.Itoa(player.Stats.RoundsSurvived),
+			strconv.Itoa(player.Stats.LongestStreak),
+			strconv.FormatFloat(player.Stats.AverageResponseTime, 'f', 3, 64),
+			strconv.Itoa(player.JoinedRound),
+			eliminatedRound,
+			reason,
+			strconv.Itoa(player.Stats.EliminationBonus),
+			strconv.Itoa(player.RTTMs),
+			strconv.FormatBool(player.IsSpectator),
+		})
+	}
+	writer.Flush()
+}
+
+// ExportRoundsCSV downloads one row per round with the called color and eliminations
+func (h *GameHandler) ExportRoundsCSV(w http.ResponseWriter, r *http.Request) {
+	game, ok := h.exportableGame(w, r)
+	if !ok {
+		return
+	}
+
+	game.Mu.RLock()
+	rounds := make([]*schema.Round, len(game.Rounds))
+	copy(rounds, game.Rounds)
+	game.Mu.RUnlock()
+
+	writer := startCSV(w, r, game, "rounds")
+	writer.Write([]string{"round_number", "called_color", "rush_duration", "eliminated_count", "elimination_reasons"})
+	for _, round := range rounds {
+		writer.Write([]string{
+			strconv.Itoa(round.Number),
+			colorNames(round.ColorsToShow),
+			strconv.FormatFloat(round.RushDuration, 'f', 2, 64),
+			strconv.Itoa(round.EliminatedCount),
+			summarizeReasons(round.EliminationReasons),
+		})
+	}
+	writer.Flush()
+}
+
+// exportableGame looks up the game from the URL, responding with an error if it
+// doesn't exist or hasn't reached settlement
+func (h *GameHandler) exportableGame(w http.ResponseWriter, r *http.Request) (*schema.Game, bool) {
+	gameID := chi.URLParam(r, "gameID")
+	if gameID == "" {
+		response.Fail(w, http.StatusBadRequest, "MISSING_GAME_ID", "Game ID is required")
+		return nil, false
+	}
+
+	game, exists := h.getGame(gameID)
+	if !exists {
+		response.Fail(w, http.StatusNotFound, "GAME_NOT_FOUND", "Game not found")
+		return nil, false
+	}
+
+	game.Mu.RLock()
+	phase := game.Phase
+	game.Mu.RUnlock()
+	if phase != schema.Settlement {
+		response.Fail(w, http.StatusConflict, "GAME_NOT_FINISHED", "Exports are available once the game has ended")
+		return nil, false
+	}
+
+	return game, true
+}
+
+// startCSV writes the download headers, and the BOM when ?excel=true, and
+// returns a writer streaming rows to the response
+func startCSV(w http.ResponseWriter, r *http.Request, game *schema.Game, kind string) *csv.Writer {
+	date := game.CreatedAt
+	if game.EndedAt != nil {
+		date = *game.EndedAt
+	}
+	filename := fmt.Sprintf("game-%s-%s-%s.csv", game.ID, kind, date.Format("2006-01-02"))
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", "attachment; filename=\""+filename+"\"")
+	w.WriteHeader(http.StatusOK)
+
+	if excel, _ := strconv.ParseBool(r.URL.Query().Get("excel")); excel {
+		w.Write([]byte(utf8BOM))
+	}
+	return csv.NewWriter(w)
+}
+
+// placement returns the player's final rank, 1 being the winner
+func placement(player *schema.Player) int {
+	// FinalPosition is the number of players still alive after the elimination,
+	// or ranked ahead of a survivor, so 0 for the winners
+	return player.Stats.FinalPosition + 1
+}
+
+// colorNames formats the safe colors of a round as "Red;Blue"
+func colorNames(colors []schema.WoolColor) string {
+	names := make([]string, len(colors))
+	for i, color := range colors {
+		names[i] = color.String()
+	}
+	return strings.Join(names, ";")
+}
+
+// summarizeReasons formats elimination reason counts as "air:2;wrong_color:1"
+func summarizeReasons(reasons map[schema.EliminationReason]int) string {
+	parts := make([]string, 0, len(reasons))
+	for reason, count := range reasons {
+		parts = append(parts, fmt.Sprintf("%s:%d", reason, count))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ";")
+}
@@ -0,0 +1,115 @@
+package game
+
+import (
+	"encoding/csv"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+// settledTestGame plays out a short game by hand: bob is eliminated in round
+// 2 and alice wins, with some points for each
+func settledTestGame(t *testing.T) (*GameHandler, *schema.Game) {
+	t.Helper()
+	h, game := newTestGame(t, nil)
+	joinTestPlayers(t, h, game, "alice", "bob")
+	h.startGame(game)
+
+	alice, bob := game.Players["alice"], game.Players["bob"]
+	alice.Stats.SurvivalPoints, bob.Stats.SurvivalPoints = 30, 10
+	alice.Stats.SpeedBonuses = 4
+	alice.Stats.HookBonuses = map[string]int{"streak": 25}
+	alice.Stats.LongestStreak, bob.Stats.LongestStreak = 3, 1
+	alice.Stats.AverageResponseTime = 0.75
+
+	game.RoundNumber = 3
+	bob.IsEliminated = true
+	bob.Stats.RoundsSurvived = 1
+	bob.Stats.FinalPosition = 1
+	game.Eliminations = append(game.Eliminations, schema.EliminationRecord{Name: "bob", RoundNumber: 2, Reason: schema.EliminatedWrongColor})
+	game.AliveCount = 1
+
+	h.finishGame(game, time.Now(), []*schema.Player{alice}, VictorySolo)
+	h.storeGame(game)
+	return h, game
+}
+
+// exportCSV downloads file, e.g. "players.csv", of the game from handler and
+// returns its rows by column
+func exportCSV(t *testing.T, handler http.HandlerFunc, gameID, file, query string) []map[string]string {
+	t.Helper()
+	path := "/api/game/" + gameID + "/export/" + file
+	rec := serveRoute(handler, http.MethodGet, "/api/game/{gameID}/export/"+file, path+query, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET %s = %d: %s", path, rec.Code, rec.Body)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(strings.TrimPrefix(rec.Body.String(), utf8BOM))).ReadAll()
+	if err != nil {
+		t.Fatalf("parse CSV: %v", err)
+	}
+	rows := make([]map[string]string, 0, len(records)-1)
+	for _, record := range records[1:] {
+		row := make(map[string]string, len(record))
+		for i, value := range record {
+			row[records[0][i]] = value
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func TestExportPlayersMatchesFinalStats(t *testing.T) {
+	h, game := settledTestGame(t)
+	rows := exportCSV(t, h.ExportPlayersCSV, game.ID, "players.csv", "?excel=true")
+
+	if len(rows) != 2 || rows[0]["name"] != "alice" || rows[1]["name"] != "bob" {
+		t.Fatalf("rows = %v, want alice then bob", rows)
+	}
+	for _, row := range rows {
+		player := game.Players[row["name"]]
+		want := map[string]string{
+			"user_id":               player.UserID,
+			"placement":             strconv.Itoa(placement(player)),
+			"score":                 strconv.Itoa(finalScore(player)),
+			"survival_points":       strconv.Itoa(player.Stats.SurvivalPoints),
+			"speed_bonuses":         strconv.Itoa(player.Stats.SpeedBonuses),
+			"streak_bonuses":        strconv.Itoa(player.Stats.HookBonuses["streak"]),
+			"rounds_survived":       strconv.Itoa(player.Stats.RoundsSurvived),
+			"longest_streak":        strconv.Itoa(player.Stats.LongestStreak),
+			"elimination_bonus":     strconv.Itoa(player.Stats.EliminationBonus),
+			"average_response_time": strconv.FormatFloat(player.Stats.AverageResponseTime, 'f', 3, 64),
+		}
+		for column, value := range want {
+			if row[column] != value {
+				t.Errorf("%s %s = %q, want %q", row["name"], column, row[column], value)
+			}
+		}
+	}
+
+	alice := rows[0]
+	if alice["user_id"] != "id-alice" || alice["placement"] != "1" {
+		t.Errorf("alice = %v", alice)
+	}
+	if want := 30 + 4 + 25 + game.Config.FinalWinnerBonus + game.Players["alice"].Stats.EliminationBonus; alice["score"] != strconv.Itoa(want) {
+		t.Errorf("alice score = %s, want %d", alice["score"], want)
+	}
+	if bob := rows[1]; bob["eliminated_in_round"] != "2" || bob["elimination_reason"] != "wrong_color" {
+		t.Errorf("bob = %v", bob)
+	}
+}
+
+func TestExportBeforeSettlementConflicts(t *testing.T) {
+	h, game := newTestGame(t, nil)
+	h.storeGame(game)
+
+	rec := serveRoute(h.ExportPlayersCSV, http.MethodGet, "/api/game/{gameID}/export/players.csv", "/api/game/"+game.ID+"/export/players.csv", nil)
+
+	if rec.Code != http.StatusConflict {
+		t.Errorf("status = %d, want 409", rec.Code)
+	}
+}
@@ -0,0 +1,42 @@
+package game
+
+import (
+	"sort"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+// isFinalTwo reports whether the round about to start is a photo finish:
+// FinalTwoSlowMo is set and exactly two players are left standing
+func (h *GameHandler) isFinalTwo(game *schema.Game) bool {
+	if game.Config.FinalTwoSlowMo <= 0 {
+		return false
+	}
+	return len(finalists(game)) == 2
+}
+
+// finalists returns the players still standing, by name
+func finalists(game *schema.Game) []string {
+	names := make([]string, 0, 2)
+	for _, player := range game.Players {
+		if !player.IsEliminated && !player.IsSpectator {
+			names = append(names, player.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// broadcastFinalShowdown tells clients the round that just started is the
+// slowed-down showdown between the last two players
+func (h *GameHandler) broadcastFinalShowdown(game *schema.Game) {
+	game.Publish(map[string]any{
+		"event": "final_showdown",
+		"data": map[string]any{
+			"round_number":   game.CurrentRound.Number,
+			"finalists":      finalists(game),
+			"rush_duration":  game.CurrentRound.RushDuration,
+			"slow_mo_factor": game.Config.FinalTwoSlowMo,
+		},
+	})
+}
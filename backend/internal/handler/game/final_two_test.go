@@ -0,0 +1,50 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+func TestFinalTwoExtendsRushDuration(t *testing.T) {
+	h, game := newTestGame(t, func(cfg *schema.GameConfig) {
+		cfg.FinalTwoSlowMo = 3
+	})
+	joinTestPlayers(t, h, game, "alice", "bob", "carol")
+	game.RoundNumber = 4
+
+	h.startNewRound(game)
+	if want := h.calculateRoundDuration(game.Config, 5); game.CurrentRound.RushDuration != want {
+		t.Fatalf("three players: rush duration = %.2f, want %.2f", game.CurrentRound.RushDuration, want)
+	}
+
+	game.Players["carol"].IsEliminated = true
+	published(game)
+	h.startNewRound(game)
+
+	if want := h.calculateRoundDuration(game.Config, 6) * 3; game.CurrentRound.RushDuration != want {
+		t.Errorf("final two: rush duration = %.2f, want %.2f", game.CurrentRound.RushDuration, want)
+	}
+	showdowns := withEvent(published(game), "final_showdown")
+	if len(showdowns) != 1 {
+		t.Fatalf("got %d final_showdown events, want 1", len(showdowns))
+	}
+	if got := showdowns[0]["finalists"]; len(got.([]string)) != 2 {
+		t.Errorf("finalists = %v", got)
+	}
+}
+
+func TestWatchdogBoundCoversSlowMo(t *testing.T) {
+	_, game := newTestGame(t, func(cfg *schema.GameConfig) {
+		cfg.FinalTwoSlowMo = 2
+		cfg.MaxPhaseSeconds = 30
+	})
+	if _, ok := validateGameConfig(game, 16)["max_phase_seconds"]; !ok {
+		t.Error("a 30s watchdog was accepted with a 40s slowed-down first round")
+	}
+
+	game.Config.MaxPhaseSeconds = 45
+	if problem, ok := validateGameConfig(game, 16)["max_phase_seconds"]; ok {
+		t.Errorf("a 45s watchdog was rejected: %s", problem)
+	}
+}
@@ -0,0 +1,67 @@
+package game
+
+import (
+	"log"
+	"time"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+// responseTime is how long after the color call the player settled on the block
+// they were judged on, 0 if they were already standing on it
+func responseTime(round *schema.Round, player *schema.Player) time.Duration {
+	if player.SettledAt.Before(round.StartTime) {
+		return 0
+	}
+	return player.SettledAt.Sub(round.StartTime)
+}
+
+// recordResponseTime folds the player's response time this round into their average
+func recordResponseTime(round *schema.Round, player *schema.Player) {
+	seconds := responseTime(round, player).Seconds()
+	player.Stats.ResponseRounds++
+	player.Stats.AverageResponseTime += (seconds - player.Stats.AverageResponseTime) / float64(player.Stats.ResponseRounds)
+}
+
+// respondedSlower reports whether a took longer than b to settle on their block
+// this round. Ties go to the later joiner, then by name, so the order doesn't
+// depend on map order.
+func respondedSlower(round *schema.Round, a, b *schema.Player) bool {
+	timeA, timeB := responseTime(round, a), responseTime(round, b)
+	switch {
+	case timeA != timeB:
+		return timeA > timeB
+	case a.JoinedRound != b.JoinedRound:
+		return a.JoinedRound > b.JoinedRound
+	default:
+		return a.Name > b.Name
+	}
+}
+
+// slowestResponder picks the player who took the longest to settle on a safe
+// block this round, see respondedSlower for ties
+func slowestResponder(round *schema.Round, players []*schema.Player) *schema.Player {
+	var slowest *schema.Player
+	for _, player := range players {
+		if slowest == nil || respondedSlower(round, player, slowest) {
+			slowest = player
+		}
+	}
+	return slowest
+}
+
+// eliminateSlowest eliminates the slowest of the players who were safe in a
+// round nobody failed, when the game runs with ForceEliminationEachRound. It
+// needs at least two of them so the round can't wipe out the last player, and
+// returns the eliminated player or nil.
+func (h *GameHandler) eliminateSlowest(game *schema.Game, safe []*schema.Player) *schema.Player {
+	if !game.Config.ForceEliminationEachRound || game.CurrentRound.Warmup || len(safe) < 2 {
+		return nil
+	}
+
+	slowest := slowestResponder(game.CurrentRound, safe)
+	log.Printf("Everyone survived round %d of game %s, eliminating the slowest responder %s (%.2fs)",
+		game.CurrentRound.Number, game.ID, slowest.Name, responseTime(game.CurrentRound, slowest).Seconds())
+	h.eliminatePlayer(game, slowest, schema.EliminatedSlowest)
+	return slowest
+}
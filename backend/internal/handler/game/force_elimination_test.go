@@ -0,0 +1,43 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+func TestSlowestIsEliminatedWhenEveryoneIsSafe(t *testing.T) {
+	h, game, _ := startTestGame(t, func(cfg *schema.GameConfig) { cfg.ForceEliminationEachRound = true },
+		"alice", "bob", "carol")
+	safe, _ := startTestRound(t, h, game)
+	round := game.CurrentRound
+
+	settled := map[string]time.Duration{"alice": time.Second, "bob": 3 * time.Second, "carol": 2 * time.Second}
+	for name, after := range settled {
+		game.Players[name].Position = safe
+		game.Players[name].SettledAt = round.StartTime.Add(after)
+	}
+
+	h.handleEliminationCheckPhase(game)
+
+	for name, player := range game.Players {
+		if eliminated := name == "bob"; player.IsEliminated != eliminated {
+			t.Errorf("%s eliminated %v, want %v", name, player.IsEliminated, eliminated)
+		}
+	}
+	if len(game.Eliminations) != 1 || game.Eliminations[0].Reason != schema.EliminatedSlowest {
+		t.Errorf("eliminations = %+v, want bob as the slowest", game.Eliminations)
+	}
+}
+
+func TestNoForcedEliminationWhenSomeoneFailed(t *testing.T) {
+	h, game, _ := startTestGame(t, func(cfg *schema.GameConfig) { cfg.ForceEliminationEachRound = true },
+		"alice", "bob", "carol")
+
+	judgeRound(t, h, game, "carol")
+
+	if len(game.Eliminations) != 1 || game.Eliminations[0].Name != "carol" {
+		t.Errorf("eliminations = %+v, want only carol", game.Eliminations)
+	}
+}
@@ -0,0 +1,71 @@
+package game
+
+import (
+	"log"
+	"time"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+// handleForfeit eliminates a player who chose to leave the game. They stay
+// connected and keep receiving updates as a spectator. If only one player is
+// left standing afterwards, the game ends right away.
+func (h *GameHandler) handleForfeit(game *schema.Game, username string) {
+	game.Mu.Lock()
+	defer game.Mu.Unlock()
+
+	player, exists := game.Players[username]
+	if !exists {
+		log.Printf("Forfeit from unknown user %s", username)
+		return
+	}
+
+	if reason := forfeitRejection(game, player); reason != "" {
+		log.Printf("Rejecting forfeit from user %s: %s", username, reason)
+		sendToClient(game, username, map[string]interface{}{
+			"event": "forfeit_rejected",
+			"data": map[string]interface{}{
+				"reason": reason,
+			},
+		})
+		return
+	}
+
+	// A downed player forfeiting gives up their revive as well
+	player.IsDowned = false
+	h.eliminatePlayer(game, player, schema.EliminatedForfeit)
+	h.sendSpectatorSwitches(game)
+	log.Printf("Player %s forfeited in round %d of game %s", player.Name, game.CurrentRound.Number, game.ID)
+
+	game.Publish(map[string]interface{}{
+		"event": "player_forfeited",
+		"data": map[string]interface{}{
+			"name":         player.Name,
+			"round_number": game.CurrentRound.Number,
+			"placement":    placement(player),
+			"alive_count":  game.AliveCount,
+		},
+	})
+
+	if game.AliveCount <= 1 {
+		now := time.Now()
+		game.CurrentRound.EndTime = &now
+		h.endGame(game, now, nil)
+	}
+}
+
+// forfeitRejection returns why the player can't forfeit right now, empty if they can
+func forfeitRejection(game *schema.Game, player *schema.Player) string {
+	switch {
+	case game.Phase != schema.InGame:
+		return "game_not_in_progress"
+	case game.CurrentRound == nil:
+		return "no_active_round"
+	case player.IsSpectator:
+		return "spectator"
+	case player.IsEliminated:
+		return "already_eliminated"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,59 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+func TestForfeitInTwoPlayerGameEndsIt(t *testing.T) {
+	h, game := newTestGame(t, nil)
+	clients := joinTestPlayers(t, h, game, "alice", "bob")
+	h.startGame(game)
+	h.startNewRound(game)
+	published(game)
+	received(clients["alice"])
+
+	h.handleForfeit(game, "alice")
+
+	alice := game.Players["alice"]
+	if !alice.IsEliminated || placement(alice) != 2 {
+		t.Errorf("alice eliminated %v, placed %d, want eliminated in 2nd", alice.IsEliminated, placement(alice))
+	}
+	if game.Phase != schema.Settlement {
+		t.Fatalf("phase %s, want settlement", game.Phase)
+	}
+
+	messages := published(game)
+	if forfeits := withEvent(messages, "player_forfeited"); len(forfeits) != 1 || forfeits[0]["alive_count"] != 1 {
+		t.Errorf("player_forfeited = %v", forfeits)
+	}
+	announcements := withEvent(messages, "winner_announced")
+	if len(announcements) != 1 {
+		t.Fatalf("got %d winner_announced events, want 1", len(announcements))
+	}
+	winners := announcements[0]["winners"].([]map[string]any)
+	if len(winners) != 1 || winners[0]["name"] != "bob" || announcements[0]["victory_type"] != VictorySolo {
+		t.Errorf("winners %v by %v, want bob solo", winners, announcements[0]["victory_type"])
+	}
+
+	// Alice keeps watching as a spectator
+	if switches := withEvent(received(clients["alice"]), "switch_to_spectator"); len(switches) != 1 {
+		t.Errorf("alice got %d switch_to_spectator messages, want 1", len(switches))
+	}
+}
+
+func TestForfeitRejectedOutsideRounds(t *testing.T) {
+	h, game := newTestGame(t, nil)
+	clients := joinTestPlayers(t, h, game, "alice", "bob")
+
+	h.handleForfeit(game, "alice")
+
+	rejections := withEvent(received(clients["alice"]), "forfeit_rejected")
+	if len(rejections) != 1 || rejections[0]["reason"] != "game_not_in_progress" {
+		t.Errorf("forfeit_rejected = %v", rejections)
+	}
+	if game.Players["alice"].IsEliminated {
+		t.Error("alice was eliminated in the lobby")
+	}
+}
@@ -4,22 +4,47 @@ import (
 	"log"
 	"time"
 
+	"go.uber.org/zap"
+
+	"github.com/yorukot/blind-party/internal/config"
 	"github.com/yorukot/blind-party/internal/schema"
 )
 
 func (h *GameHandler) GameLifeCycle(game *schema.Game) {
 	defer func() {
+		// Later stop requests must not wait on a lifecycle that is gone
+		game.Stop()
 		if game.Ticker != nil {
 			game.Ticker.Stop()
 		}
+		if game.Replay != nil {
+			game.Replay.Close()
+		}
+
+		// Nothing is broadcast anymore, don't leave clients or observers waiting
+		game.Mu.Lock()
+		for username, client := range game.Clients {
+			closeClient(client, closeGameClosed)
+			delete(game.Clients, username)
+		}
+		closeObservers(game, nil, closeGameClosed)
+		game.Mu.Unlock()
+
+		// Connections still registering or unregistering, or handlers
+		// publishing, would otherwise wait on the loop forever
+		game.MarkDone()
 		log.Printf("Game %s lifecycle ended", game.ID)
 	}()
+	defer h.recoverGame(game)
 
 	log.Printf("Starting game lifecycle for game %s", game.ID)
+	game.MarkReady()
 
 	// Main game loop
 	for {
-		log.Printf("Game %s main loop tick", game.ID)
+		if config.Env().Debug {
+			log.Printf("Game %s main loop tick", game.ID)
+		}
 		select {
 		case <-game.StopTicker:
 			log.Printf("Game %s received stop signal", game.ID)
@@ -31,8 +56,9 @@ func (h *GameHandler) GameLifeCycle(game *schema.Game) {
 		case client := <-game.Unregister:
 			h.handleClientUnregister(game, client)
 
-		case message := <-game.Broadcast:
-			h.broadcastToClients(game, message)
+		case queued := <-game.Broadcast:
+			h.recordReplay(game, queued.Message)
+			h.broadcastToClients(game, queued)
 
 		default:
 			// Handle game state progression
@@ -42,22 +68,110 @@ func (h *GameHandler) GameLifeCycle(game *schema.Game) {
 	}
 }
 
+// recoverGame stops a panic in a game's lifecycle from taking down the server.
+// The game is ended, its clients are told why and disconnected, and it is
+// removed from memory. Other games keep running.
+func (h *GameHandler) recoverGame(game *schema.Game) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	zap.L().Error("Game lifecycle panicked",
+		zap.String("game_id", game.ID),
+		zap.Any("panic", r),
+		zap.Stack("stack"),
+	)
+
+	game.Mu.Lock()
+	defer game.Mu.Unlock()
+
+	now := time.Now()
+	game.Phase = schema.Settlement
+	game.EndedAt = &now
+
+	// The lifecycle no longer drains Broadcast, so deliver directly
+	message := map[string]interface{}{
+		"event": "game_error",
+		"data": map[string]interface{}{
+			"game_id": game.ID,
+			"message": "The game encountered an unexpected error and has ended",
+		},
+	}
+	h.recordReplay(game, message)
+	for username, client := range game.Clients {
+		select {
+		case client.Send <- message:
+		default:
+		}
+		closeClient(client, closeGameError)
+		delete(game.Clients, username)
+	}
+	closeObservers(game, message, closeGameError)
+
+	h.removeGame(game.ID)
+}
+
 // handleClientRegister processes new WebSocket client connections
 func (h *GameHandler) handleClientRegister(game *schema.Game, client *schema.WebSocketClient) {
 	game.Mu.Lock()
 	defer game.Mu.Unlock()
 
-	game.Clients[client.Username] = client
+	// A name can only be taken over by the user holding it. Without a user_id
+	// there's no telling who is reconnecting, so such a player is never replaced.
+	if player, exists := game.Players[client.Username]; exists && (player.UserID == "" || player.UserID != client.UserID) {
+		log.Printf("Client %s rejected from game %s: the name belongs to another connection", client.Username, game.ID)
+		closeClient(client, closeUsernameTaken)
+		return
+	}
+
+	// One session per user: a new connection replaces the old one and takes
+	// over the existing player
+	if previous, connected := game.Clients[client.Username]; connected {
+		closeClient(previous, closeReplaced)
+		game.Clients[client.Username] = client
+		log.Printf("Client %s replaced an existing connection in game %s", client.Username, game.ID)
+		recordConnection(game, client, schema.ConnectionConnect)
+
+		// The new connection starts its own update sequence
+		if player, exists := game.Players[client.Username]; exists {
+			player.LastSeq = 0
+			h.refreshRejoiningPlayer(game, player, client)
+		}
+		h.sendInitialState(game, client)
+		return
+	}
+
+	// Avatars can be required to be unique within a room
+	if game.Config.UniqueAvatars && avatarTaken(game, client.Avatar, "") {
+		log.Printf("Client %s rejected from game %s: avatar %s is taken", client.Username, game.ID, client.Avatar)
+		closeClient(client, closeAvatarTaken)
+		return
+	}
 
-	// Determine joined round number
+	// Names only differing in case or spacing are rejected or told apart
+	displayName, ok := displayNameFor(game, client.Username)
+	if !ok {
+		log.Printf("Client %s rejected from game %s: the name looks like another player's", client.Username, game.ID)
+		closeClient(client, closeDuplicateName)
+		return
+	}
+
+	// Determine joined round number. Between rounds the player joins the next one.
 	joinedRound := 0
-	if game.CurrentRound != nil {
-		joinedRound = game.CurrentRound.Number
+	if game.Phase != schema.PreGame {
+		joinedRound = game.RoundNumber + 1
+		if game.CurrentRound != nil {
+			joinedRound = game.CurrentRound.Number
+		}
 	}
 
 	// Create a new player object for this client
 	player := &schema.Player{
 		Name:              client.Username,
+		DisplayName:       displayName,
+		Avatar:            client.Avatar,
+		UserID:            client.UserID,
 		Position:          schema.Position{X: 10.0, Y: 10.0}, // Default center position
 		IsSpectator:       false,
 		IsEliminated:      false,
@@ -72,16 +186,112 @@ func (h *GameHandler) handleClientRegister(game *schema.Game, client *schema.Web
 		},
 	}
 
+	// Players joining after the game started play only within the late join window
+	if game.Phase != schema.PreGame {
+		h.admitLateJoiner(game, player)
+	}
+
+	// Spectators don't take a player slot but are capped on their own
+	if player.IsSpectator && spectatorsFull(game) {
+		log.Printf("Client %s rejected from game %s: spectator limit of %d reached", client.Username, game.ID, game.Config.MaxSpectators)
+		closeClient(client, closeSpectatorsFull)
+		return
+	}
+
 	// Add player to the game
+	game.Clients[client.Username] = client
 	game.Players[client.Username] = player
-	game.PlayerCount++
-	game.AliveCount++
+	game.InitialState = nil
+	if player.IsSpectator {
+		game.SpectatorCount++
+	} else {
+		game.PlayerCount++
+		game.AliveCount++
+	}
 
-	log.Printf("Client %s registered to game %s (Player count: %d)", client.Username, game.ID, game.PlayerCount)
+	log.Printf("Client %s registered to game %s (Player count: %d, spectators: %d)", client.Username, game.ID, game.PlayerCount, game.SpectatorCount)
+	recordConnection(game, client, schema.ConnectionConnect)
 
 	// Send current game state to newly connected client
-	gameState := h.createGameStateMessage(game)
-	game.Broadcast <- gameState
+	h.sendInitialState(game, client)
+	h.notifyRosterChange(game, client.UserID)
+}
+
+// refreshRejoiningPlayer updates a player whose user connected again. In the
+// lobby the rejoin may bring a new avatar, kept unless unique avatars are
+// required and another player has it.
+func (h *GameHandler) refreshRejoiningPlayer(game *schema.Game, player *schema.Player, client *schema.WebSocketClient) {
+	player.LastUpdate = time.Now()
+
+	if game.Phase != schema.PreGame || client.Avatar == "" || client.Avatar == player.Avatar {
+		return
+	}
+	if game.Config.UniqueAvatars && avatarTaken(game, client.Avatar, player.Name) {
+		log.Printf("Keeping avatar %s of rejoining player %s in game %s: %s is taken", player.Avatar, player.Name, game.ID, client.Avatar)
+		return
+	}
+	player.Avatar = client.Avatar
+	h.notifyRosterChange(game, player.UserID)
+}
+
+// avatarTaken reports whether a player in the game other than except already uses the avatar
+func avatarTaken(game *schema.Game, avatar, except string) bool {
+	if avatar == "" {
+		return false
+	}
+	for _, player := range game.Players {
+		if player.Avatar == avatar && player.Name != except {
+			return true
+		}
+	}
+	return false
+}
+
+// spectatorsFull reports whether the game already holds MaxSpectators spectators
+func spectatorsFull(game *schema.Game) bool {
+	return game.Config.MaxSpectators > 0 && game.SpectatorCount >= game.Config.MaxSpectators
+}
+
+// sendInitialState sends the current game state to a newly connected client,
+// with the color palette embedded once so it can render color-blind patterns.
+// A client connecting once the game is over gets the final results instead.
+func (h *GameHandler) sendInitialState(game *schema.Game, client *schema.WebSocketClient) {
+	if game.Phase == schema.Settlement && game.FinalResults != nil {
+		select {
+		case client.Send <- map[string]interface{}{
+			"event": "final_results",
+			"data":  game.FinalResults,
+		}:
+		default:
+			game.SendBufferFull++
+		}
+		return
+	}
+
+	// A reconnect storm reuses one state instead of building and compressing
+	// it for every client. The cache is dropped whenever a message goes out or
+	// the roster changes, positions are at most a tick old.
+	if game.InitialState == nil {
+		gameState := h.createGameStateMessage(game)
+		game.InitialState = &schema.InitialStateCache{
+			Plain: map[string]interface{}{
+				"event": gameState["event"],
+				"data":  withColorPalette(gameState["data"].(map[string]interface{})),
+			},
+		}
+	}
+	initialState := game.InitialState.Plain
+	if client.Gzip {
+		if game.InitialState.Gzipped == nil {
+			game.InitialState.Gzipped = compressLargeMessage(initialState, config.Env().WSCompressThresholdBytes)
+		}
+		initialState = game.InitialState.Gzipped
+	}
+	select {
+	case client.Send <- initialState:
+	default:
+		game.SendBufferFull++
+	}
 }
 
 // handleClientUnregister processes WebSocket client disconnections
@@ -89,7 +299,8 @@ func (h *GameHandler) handleClientUnregister(game *schema.Game, client *schema.W
 	game.Mu.Lock()
 	defer game.Mu.Unlock()
 
-	if _, exists := game.Clients[client.Username]; exists {
+	// Only the current connection of a user removes them, not one it replaced
+	if current, exists := game.Clients[client.Username]; exists && current == client {
 		// Remove client
 		delete(game.Clients, client.Username)
 		close(client.Send)
@@ -97,45 +308,84 @@ func (h *GameHandler) handleClientUnregister(game *schema.Game, client *schema.W
 		// Remove player if it exists
 		if player, playerExists := game.Players[client.Username]; playerExists {
 			delete(game.Players, client.Username)
-			game.PlayerCount--
-			// Only decrement alive count if player wasn't eliminated
-			if !player.IsEliminated {
-				game.AliveCount--
+			delete(game.PlayerPositionHistory, client.Username)
+			game.PlayersList = removePlayer(game.PlayersList, player)
+			if player.IsSpectator {
+				game.SpectatorCount--
+			} else {
+				game.PlayerCount--
+				// Only decrement alive count if player wasn't eliminated
+				if !player.IsEliminated {
+					game.AliveCount--
+				}
 			}
 		}
 
-		log.Printf("Client %s unregistered from game %s (Player count: %d)", client.Username, game.ID, game.PlayerCount)
+		log.Printf("Client %s unregistered from game %s (Player count: %d, spectators: %d)", client.Username, game.ID, game.PlayerCount, game.SpectatorCount)
+		switch {
+		case client.Kicked:
+			recordConnection(game, client, schema.ConnectionKick)
+		case client.TimedOut:
+			recordConnection(game, client, schema.ConnectionTimeout)
+		default:
+			recordConnection(game, client, schema.ConnectionDisconnect)
+		}
 
-		// Check if no players remain and stop the game
-		if game.PlayerCount == 0 {
+		// Check if nobody remains and stop the game
+		if game.PlayerCount == 0 && game.SpectatorCount == 0 {
 			log.Printf("No players remaining, stopping game %s", game.ID)
-			go func() {
-				game.StopTicker <- true
-			}()
+			game.Stop()
 			return // Don't broadcast since game is stopping
 		}
 
-		// Broadcast updated game state to remaining clients via the broadcast channel
-		updatedGameState := h.createGameStateMessage(game)
-		game.Broadcast <- updatedGameState
+		// Let the remaining clients know the roster changed
+		h.notifyRosterChange(game, client.UserID)
 	}
 }
 
 // broadcastToClients sends a message to all connected clients
-func (h *GameHandler) broadcastToClients(game *schema.Game, message interface{}) {
-	game.Mu.RLock()
-	defer game.Mu.RUnlock()
+func (h *GameHandler) broadcastToClients(game *schema.Game, queued schema.QueuedMessage) {
+	// Unresponsive clients are removed, a write that needs the full lock
+	game.Mu.Lock()
+	defer game.Mu.Unlock()
+
+	message := queued.Message
+	game.InitialState = nil // Whatever changed, clients connecting from now on need the new state
 
+	// Collect them first so the map isn't changed while ranging over it
+	var unresponsive []string
 	for userID, client := range game.Clients {
+		game.SendMetrics.RecordDepth(len(client.Send))
 		select {
 		case client.Send <- message:
 		default:
-			// Client's send channel is full, close it
-			close(client.Send)
-			delete(game.Clients, userID)
-			log.Printf("Removed unresponsive client %s from game %s", userID, game.ID)
+			game.SendBufferFull++
+			unresponsive = append(unresponsive, userID)
 		}
 	}
+
+	for _, userID := range unresponsive {
+		// Client's send channel is full, close it
+		closeClient(game.Clients[userID], closeUnresponsive)
+		delete(game.Clients, userID)
+		log.Printf("Removed unresponsive client %s from game %s", userID, game.ID)
+	}
+
+	// Observers see every broadcast too, a stalled one is dropped right away
+	for client := range game.Observers {
+		game.SendMetrics.RecordDepth(len(client.Send))
+		select {
+		case client.Send <- message:
+		default:
+			game.SendBufferFull++
+			closeClient(client, closeUnresponsive)
+			delete(game.Observers, client)
+			log.Printf("Removed unresponsive observer from game %s", game.ID)
+		}
+	}
+
+	// Latency up to the hand-off to the clients' send buffers
+	game.SendMetrics.RecordLatency(time.Since(queued.EnqueuedAt))
 }
 
 // createGameStateMessage creates a complete game state message for clients
@@ -146,37 +396,42 @@ func (h *GameHandler) createGameStateMessage(game *schema.Game) map[string]inter
 		game.PlayersList = append(game.PlayersList, player)
 	}
 
-	// Convert map data to array format for JSON
-	game.MapArray = make([][]int, 20)
-	for i := range game.MapArray {
-		game.MapArray[i] = make([]int, 20)
-		for j := range game.MapArray[i] {
-			game.MapArray[i][j] = int(game.Map[i][j])
-		}
-	}
+	// Both keys carry the same copy of the round
+	round := roundForClients(game)
 
 	// Create a safe game state without channels
 	return map[string]interface{}{
 		"event": "game_update",
 		"data": map[string]interface{}{
-			"game_id":       game.ID,
-			"created_at":    game.CreatedAt,
-			"started_at":    game.StartedAt,
-			"ended_at":      game.EndedAt,
-			"phase":         game.Phase,
-			"current_round": game.CurrentRound,
-			"map":           game.MapArray,
-			"round":         game.CurrentRound,
-			"round_number":  game.RoundNumber,
-			"players":       game.PlayersList,
-			"player_count":  game.PlayerCount,
-			"countdown_seconds":     game.Countdown,
-			"alive_count":   game.AliveCount,
-			"config":        game.Config,
+			"game_id":           game.ID,
+			"created_at":        game.CreatedAt,
+			"started_at":        game.StartedAt,
+			"ended_at":          game.EndedAt,
+			"phase":             game.Phase,
+			"current_round":     round,
+			"map":               game.MapArray,
+			"round":             round,
+			"round_number":      game.RoundNumber,
+			"players":           playersSnapshot(game.PlayersList),
+			"player_count":      game.PlayerCount,
+			"spectator_count":   game.SpectatorCount,
+			"countdown_seconds": countdownSnapshot(game),
+			"alive_count":       game.AliveCount,
+			"config":            game.Config,
 		},
 	}
 }
 
+// withColorPalette returns a copy of the game state data with the color palette added
+func withColorPalette(data map[string]interface{}) map[string]interface{} {
+	withPalette := make(map[string]interface{}, len(data)+1)
+	for key, value := range data {
+		withPalette[key] = value
+	}
+	withPalette["color_palette"] = schema.ColorPalette
+	return withPalette
+}
+
 // +=====================================================+
 // | 				GAME TICK LOGIC						 |
 // +=====================================================+
@@ -185,16 +440,33 @@ func (h *GameHandler) createGameStateMessage(game *schema.Game) map[string]inter
 func (h *GameHandler) processGameState(game *schema.Game) {
 	game.Mu.Lock()
 	defer game.Mu.Unlock()
+	if h.tickHook != nil {
+		h.tickHook(game)
+	}
+	h.updateStaleness(game)
+	h.pingClients(game)
+
+	// A paused game makes no progress, ResumeGame shifts the clock past the pause
+	if game.PausedAt != nil {
+		return
+	}
+
 	switch game.Phase {
 	case schema.PreGame:
 		h.handlePreGamePhase(game)
 	case schema.InGame:
 		h.handleInGamePhase(game)
-		log.Print("Processed InGame phase")
 	case schema.Settlement:
 		// h.handleSettlementPhase(game)
 	}
+	// Eliminations are normally announced where they're judged, catch any left over
+	h.sendSpectatorSwitches(game)
 	game.LastTick = time.Now()
-	log.Printf("Game %s state processed (Phase: %s)", game.ID, game.Phase)
-	game.Broadcast <- h.createGameStateMessage(game)
+	if config.Env().Debug {
+		log.Printf("Game %s state processed (Phase: %s)", game.ID, game.Phase)
+	}
+	if game.Phase == schema.PreGame && !lobbyStateDue(game) {
+		return
+	}
+	game.Publish(h.createGameStateMessage(game))
 }
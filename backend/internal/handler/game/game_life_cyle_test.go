@@ -0,0 +1,659 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/net/websocket"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+func TestSecondConnectionOfSameUserReplacesFirst(t *testing.T) {
+	h, game := newTestGame(t, nil)
+	first := joinTestPlayers(t, h, game, "alice")["alice"]
+
+	second := newTestClient("alice", "id-alice")
+	h.handleClientRegister(game, second)
+
+	if game.Clients["alice"] != second {
+		t.Fatal("second connection did not take over")
+	}
+	if first.CloseCode != closeReplaced.Code {
+		t.Errorf("first connection closed with %d, want %d", first.CloseCode, closeReplaced.Code)
+	}
+	received(first)
+	if _, open := <-first.Send; open {
+		t.Error("first connection's send channel is still open")
+	}
+	if game.PlayerCount != 1 {
+		t.Errorf("PlayerCount = %d, want 1", game.PlayerCount)
+	}
+}
+
+func TestSameNameWithoutMatchingIdentityIsRejected(t *testing.T) {
+	tests := []struct {
+		name         string
+		holderID     string
+		connectingID string
+	}{
+		{"different user", "id-alice", "id-mallory"},
+		{"no user_id against a claimed name", "id-alice", ""},
+		{"holder without user_id", "", ""},
+		{"user_id against an unclaimed name", "", "id-mallory"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, game := newTestGame(t, nil)
+			holder := newTestClient("alice", tt.holderID)
+			h.handleClientRegister(game, holder)
+
+			intruder := newTestClient("alice", tt.connectingID)
+			h.handleClientRegister(game, intruder)
+
+			if game.Clients["alice"] != holder {
+				t.Error("the player was taken over")
+			}
+			if intruder.CloseCode != closeUsernameTaken.Code {
+				t.Errorf("intruder closed with %d, want %d", intruder.CloseCode, closeUsernameTaken.Code)
+			}
+			if holder.CloseCode != 0 {
+				t.Errorf("holder closed with %d", holder.CloseCode)
+			}
+		})
+	}
+}
+
+func TestLobbyRejoinRefreshesThePlayer(t *testing.T) {
+	h, game := newTestGame(t, nil)
+	joinWithAvatar(h, game, "alice", "creeper")
+	player := game.Players["alice"]
+	player.LastSeq = 5
+	player.LastUpdate = time.Now().Add(-time.Minute)
+	game.LobbyDirty = false
+
+	rejoined := joinWithAvatar(h, game, "alice", "pig")
+	if rejoined.CloseCode != 0 || game.Clients["alice"] != rejoined {
+		t.Fatalf("rejoin closed with %d", rejoined.CloseCode)
+	}
+	if game.Players["alice"] != player || game.PlayerCount != 1 {
+		t.Fatalf("rejoin made a new player, %d counted", game.PlayerCount)
+	}
+	if player.Avatar != "pig" || player.LastSeq != 0 || time.Since(player.LastUpdate) > time.Second {
+		t.Errorf("rejoined player has avatar %q, seq %d, last update %v ago", player.Avatar, player.LastSeq, time.Since(player.LastUpdate))
+	}
+	if !game.LobbyDirty {
+		t.Error("the new avatar wasn't announced to the lobby")
+	}
+
+	// Someone else can't use the name, and leaves the player alone
+	intruder := newTestClient("alice", "id-mallory")
+	intruder.Avatar = "sheep"
+	h.handleClientRegister(game, intruder)
+	if intruder.CloseCode != closeUsernameTaken.Code || game.Clients["alice"] != rejoined || player.Avatar != "pig" {
+		t.Errorf("intruder closed with %d, alice's avatar %q", intruder.CloseCode, player.Avatar)
+	}
+
+	// Once the game runs the avatar is fixed
+	game.Phase = schema.InGame
+	joinWithAvatar(h, game, "alice", "sheep")
+	if player.Avatar != "pig" {
+		t.Errorf("avatar changed to %q in game", player.Avatar)
+	}
+}
+
+// TestConcurrentConnectionsStateReadsAndTeardown runs a game's lifecycle while
+// clients connect and disconnect, the state is read and messages are
+// broadcast, then stops it with clients still connected. Run with -race.
+func TestConcurrentConnectionsStateReadsAndTeardown(t *testing.T) {
+	h, game := newTestGame(t, func(cfg *schema.GameConfig) {
+		cfg.LobbyUpdateIntervalMs = 0
+	})
+	h.storeGame(game)
+
+	server := serveGames(t, h)
+
+	lifecycleDone := make(chan struct{})
+	go func() {
+		h.GameLifeCycle(game)
+		close(lifecycleDone)
+	}()
+
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/game/" + game.ID + "/ws"
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+
+	// Clients joining, reading a few messages and leaving, over and over
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; ; j++ {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				name := fmt.Sprintf("p%d-%d", i, j)
+				conn, err := websocket.Dial(wsURL+"?username="+name+"&user_id=id-"+name, "", server.URL)
+				if err != nil {
+					t.Errorf("dial: %v", err)
+					return
+				}
+				conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+				for k := 0; k < 3; k++ {
+					var message map[string]interface{}
+					if err := websocket.JSON.Receive(conn, &message); err != nil {
+						if errors.Is(err, os.ErrDeadlineExceeded) {
+							t.Errorf("%s got no message or close", name)
+						}
+						break
+					}
+				}
+				conn.Close()
+			}
+		}(i)
+	}
+
+	// State reads meanwhile
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				resp, err := http.Get(server.URL + "/api/game/" + game.ID)
+				if err != nil {
+					t.Errorf("get state: %v", err)
+					return
+				}
+				io.Copy(io.Discard, resp.Body)
+				resp.Body.Close()
+			}
+		}()
+	}
+
+	// Broadcasts from outside the lifecycle, like the HTTP handlers', at a
+	// pace that leaves the lifecycle room for its own in the queue
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(2 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				game.Publish(map[string]interface{}{"event": "announcement", "data": map[string]interface{}{}})
+			}
+		}
+	}()
+
+	// Clients that stay connected through the teardown
+	var lingering []*websocket.Conn
+	for i := 0; i < 4; i++ {
+		name := fmt.Sprintf("stay%d", i)
+		conn, err := websocket.Dial(wsURL+"?username="+name+"&user_id=id-"+name, "", server.URL)
+		if err != nil {
+			t.Fatalf("dial: %v", err)
+		}
+		lingering = append(lingering, conn)
+	}
+
+	time.Sleep(300 * time.Millisecond)
+	game.Stop()
+	select {
+	case <-lifecycleDone:
+	case <-time.After(5 * time.Second):
+		t.Fatal("lifecycle did not stop")
+	}
+	close(stop)
+	wg.Wait()
+
+	// The lifecycle is gone, lingering connections still get closed
+	for _, conn := range lingering {
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		for {
+			var message map[string]interface{}
+			if err := websocket.JSON.Receive(conn, &message); err != nil {
+				if errors.Is(err, os.ErrDeadlineExceeded) {
+					t.Error("connection left open after the game stopped")
+				}
+				break
+			}
+		}
+		conn.Close()
+	}
+}
+
+// TestRoundTransitionsDuringPositionUpdates plays short rounds back to back
+// while every player streams position updates and the state is read. Run with
+// -race.
+func TestRoundTransitionsDuringPositionUpdates(t *testing.T) {
+	h, game := newTestGame(t, func(cfg *schema.GameConfig) {
+		cfg.TimingProgression = []schema.TimingRange{{StartRound: 1, EndRound: 100, Duration: 0.1}}
+		cfg.RoundBreatherSeconds = 0.05
+		cfg.FirstRoundGraceSeconds = 0
+		cfg.AutoStartCapacityRatio = 0
+		cfg.Lives = 1000 // Nobody falls out, the rounds keep coming
+	})
+	var rounds atomic.Int64
+	h.tickHook = func(game *schema.Game) { rounds.Store(int64(game.RoundNumber)) }
+	runGame(t, h, game)
+	server := serveGames(t, h)
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		name := fmt.Sprintf("p%d", i)
+		conn := dialGame(t, server, game.ID, "/ws?username="+name+"&user_id=id-"+name)
+		receiveUntil(t, conn, "game_update")
+
+		// Reading keeps the writer marshaling what the rounds publish
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for {
+				var message map[string]interface{}
+				if websocket.JSON.Receive(conn, &message) != nil {
+					return
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for seq := 1; ; seq++ {
+				select {
+				case <-stop:
+					return
+				case <-time.After(2 * time.Millisecond):
+				}
+				x := 2 + float64(seq%10)
+				if websocket.JSON.Send(conn, playerUpdate(x, 5, seq)) != nil {
+					return
+				}
+			}
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			resp, err := http.Get(server.URL + "/api/game/" + game.ID)
+			if err != nil {
+				return
+			}
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+	}()
+
+	game.Mu.Lock()
+	h.startGame(game)
+	game.Mu.Unlock()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for rounds.Load() < 5 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if rounds.Load() < 5 {
+		t.Errorf("only reached round %d", rounds.Load())
+	}
+	close(stop)
+	game.Stop()
+	<-game.Done
+	wg.Wait()
+}
+
+func TestPanickingGameDoesNotStopOthers(t *testing.T) {
+	h, broken := newTestGame(t, nil)
+	healthy := h.buildGame(defaultGameConfig())
+	brokenClient := joinTestPlayers(t, h, broken, "alice")["alice"]
+	joinTestPlayers(t, h, healthy, "bob")
+
+	var healthyTicks, brokenTicks atomic.Int32
+	h.tickHook = func(game *schema.Game) {
+		if game == healthy {
+			healthyTicks.Add(1)
+		}
+		if game == broken && brokenTicks.Add(1) == 3 {
+			panic("injected")
+		}
+	}
+	runGame(t, h, broken)
+	runGame(t, h, healthy)
+
+	select {
+	case <-broken.Done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the panicking game's lifecycle did not end")
+	}
+	if _, exists := h.getGame(broken.ID); exists {
+		t.Error("the panicking game is still in memory")
+	}
+	if brokenClient.CloseCode != closeGameError.Code {
+		t.Errorf("client closed with %d, want %d", brokenClient.CloseCode, closeGameError.Code)
+	}
+	if len(withEvent(received(brokenClient), "game_error")) != 1 {
+		t.Error("client was not sent game_error")
+	}
+
+	before := healthyTicks.Load()
+	time.Sleep(200 * time.Millisecond)
+	if healthyTicks.Load() <= before {
+		t.Error("the other game stopped ticking")
+	}
+	if _, exists := h.getGame(healthy.ID); !exists {
+		t.Error("the other game was removed")
+	}
+}
+
+// joinWithAvatar registers a client picking the avatar, returning it
+func joinWithAvatar(h *GameHandler, game *schema.Game, name, avatar string) *schema.WebSocketClient {
+	client := newTestClient(name, "id-"+name)
+	client.Avatar = avatar
+	h.handleClientRegister(game, client)
+	return client
+}
+
+func TestAvatarIsCarriedToClients(t *testing.T) {
+	h, game := newTestGame(t, nil)
+	joinWithAvatar(h, game, "alice", "creeper")
+	joinWithAvatar(h, game, "bob", "")
+
+	if got := game.Players["alice"].Avatar; got != "creeper" {
+		t.Errorf("alice's avatar = %q, want creeper", got)
+	}
+	state, err := json.Marshal(h.createGameStateMessage(game))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(state), `"avatar":"creeper"`) {
+		t.Errorf("game state does not carry alice's avatar: %s", state)
+	}
+
+	// Without UniqueAvatars two players may share one
+	joinWithAvatar(h, game, "carol", "creeper")
+	if got := game.Players["carol"]; got == nil || got.Avatar != "creeper" {
+		t.Errorf("carol = %+v, want the shared creeper avatar", got)
+	}
+}
+
+func TestUniqueAvatars(t *testing.T) {
+	h, game := newTestGame(t, func(cfg *schema.GameConfig) {
+		cfg.UniqueAvatars = true
+	})
+	joinWithAvatar(h, game, "alice", "creeper")
+
+	bob := joinWithAvatar(h, game, "bob", "creeper")
+	if _, joined := game.Players["bob"]; joined || bob.CloseReason != closeAvatarTaken.Reason {
+		t.Errorf("bob joined %v, closed with %q, want %s", joined, bob.CloseReason, closeAvatarTaken.Reason)
+	}
+	if joinWithAvatar(h, game, "carol", "pig"); game.Players["carol"] == nil {
+		t.Error("carol with a free avatar was rejected")
+	}
+
+	// A rejoin can't take another player's avatar but keeps its own
+	joinWithAvatar(h, game, "carol", "creeper")
+	if got := game.Players["carol"].Avatar; got != "pig" {
+		t.Errorf("carol's avatar after rejoining = %q, want pig", got)
+	}
+	joinWithAvatar(h, game, "carol", "sheep")
+	if got := game.Players["carol"].Avatar; got != "sheep" {
+		t.Errorf("carol's avatar after rejoining = %q, want sheep", got)
+	}
+}
+
+func TestPanicNotifiesConnectedClientsAndObservers(t *testing.T) {
+	h, game := newTestGame(t, nil)
+	var panicNow atomic.Bool
+	h.tickHook = func(g *schema.Game) {
+		if panicNow.Load() {
+			panic("injected")
+		}
+	}
+	server := serveGames(t, h)
+	runGame(t, h, game)
+
+	player := dialGame(t, server, game.ID, "/ws?username=alice&user_id=u1")
+	receiveUntil(t, player, "game_update")
+	observer := dialGame(t, server, game.ID, "/observe")
+	receiveUntil(t, observer, "game_update")
+	panicNow.Store(true)
+
+	for name, conn := range map[string]*websocket.Conn{"player": player, "observer": observer} {
+		receiveUntil(t, conn, "game_error")
+		if closing := receiveClose(t, conn); closing == nil || closing["reason"] != closeGameError.Reason {
+			t.Errorf("%s closed with %v, want %s", name, closing, closeGameError.Reason)
+		}
+	}
+	if _, exists := h.getGame(game.ID); exists {
+		t.Error("the game is still in memory")
+	}
+}
+
+func TestRepeatedStopsNeverBlock(t *testing.T) {
+	h, game := newTestGame(t, nil)
+	h.storeGame(game)
+	lifecycleDone := make(chan struct{})
+	go func() {
+		h.GameLifeCycle(game)
+		close(lifecycleDone)
+	}()
+	<-game.Ready
+
+	// Stopping from several places at once, then again once the lifecycle is gone
+	stopped := make(chan struct{})
+	go func() {
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				game.Stop()
+			}()
+		}
+		wg.Wait()
+		<-lifecycleDone
+		game.Stop()
+		game.Stop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("a stop request blocked")
+	}
+	select {
+	case <-game.Done:
+	default:
+		t.Error("Done not closed after the lifecycle returned")
+	}
+}
+
+func TestStoppedLifecycleReleasesEveryone(t *testing.T) {
+	h, game := newTestGame(t, nil)
+	server := serveGames(t, h)
+	runGame(t, h, game)
+	alice := dialGame(t, server, game.ID, "/ws?username=alice&user_id=u1")
+	receiveUntil(t, alice, "game_update")
+
+	game.Stop()
+	select {
+	case <-game.Done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("lifecycle did not stop")
+	}
+	if closing := receiveClose(t, alice); closing == nil || closing["reason"] != closeGameClosed.Reason {
+		t.Errorf("alice closed with %v, want %s", closing, closeGameClosed.Reason)
+	}
+
+	// More than the queue holds, with nothing left draining it
+	published := make(chan struct{})
+	go func() {
+		for i := 0; i < 2*cap(game.Broadcast); i++ {
+			game.Publish(map[string]interface{}{"event": "announcement", "data": map[string]interface{}{}})
+		}
+		close(published)
+	}()
+	select {
+	case <-published:
+	case <-time.After(5 * time.Second):
+		t.Fatal("publishing to the stopped game blocked")
+	}
+
+	// A connection arriving late is turned away rather than left waiting
+	bob := dialGame(t, server, game.ID, "/ws?username=bob&user_id=u2")
+	if closing := receiveClose(t, bob); closing == nil || closing["reason"] != closeGameClosed.Reason {
+		t.Errorf("bob closed with %v, want %s", closing, closeGameClosed.Reason)
+	}
+	game.Mu.Lock()
+	defer game.Mu.Unlock()
+	if len(game.Clients) != 0 {
+		t.Errorf("%d clients left registered after the lifecycle returned", len(game.Clients))
+	}
+}
+
+func TestSmallSendBufferDropsSlowClient(t *testing.T) {
+	tests := []struct {
+		buffer  int
+		dropped bool
+	}{
+		{buffer: 8, dropped: true},
+		{buffer: 64, dropped: false},
+	}
+	for _, tt := range tests {
+		h, game := newTestGame(t, func(cfg *schema.GameConfig) { cfg.ClientSendBuffer = tt.buffer })
+		h.storeGame(game)
+		client := newTestClient("alice", "id-alice")
+		client.Send = make(chan interface{}, game.Config.ClientSendBuffer)
+		h.handleClientRegister(game, client)
+
+		// The client never reads while 20 updates go out
+		for i := 0; i < 20; i++ {
+			h.broadcastToClients(game, schema.QueuedMessage{
+				Message:    map[string]interface{}{"event": "player_update", "data": i},
+				EnqueuedAt: time.Now(),
+			})
+		}
+
+		if _, connected := game.Clients["alice"]; connected == tt.dropped {
+			t.Errorf("buffer %d: client connected %v after 20 unread updates", tt.buffer, connected)
+		}
+		wantFull := 0
+		if tt.dropped {
+			wantFull = 1
+			if client.CloseCode != closeUnresponsive.Code {
+				t.Errorf("buffer %d: closed with %d, want %d", tt.buffer, client.CloseCode, closeUnresponsive.Code)
+			}
+		}
+		if game.SendBufferFull != wantFull || h.SendBufferFullCount() != wantFull {
+			t.Errorf("buffer %d: %d full buffers, %d counted by the handler, want %d",
+				tt.buffer, game.SendBufferFull, h.SendBufferFullCount(), wantFull)
+		}
+	}
+}
+
+func TestConnectingDuringSettlementGetsFinalResults(t *testing.T) {
+	h, game, _ := startTestGame(t, nil, "alice", "bob")
+	judgeRound(t, h, game, "bob")
+	if game.Phase != schema.Settlement {
+		t.Fatalf("game in %s, want settlement", game.Phase)
+	}
+
+	for _, client := range []*schema.WebSocketClient{
+		newTestClient("alice", "id-alice"), // Reconnecting
+		newTestClient("dave", "id-dave"),   // Never played
+	} {
+		h.handleClientRegister(game, client)
+		messages := received(client)
+		if len(messages) == 0 || messages[0]["event"] != "final_results" {
+			t.Fatalf("%s got %v first, want final_results", client.Username, messages)
+		}
+		results := messages[0]["data"].(map[string]any)
+		standings := results["standings"].([]map[string]any)
+		if len(standings) != 2 || standings[0]["name"] != "alice" || standings[1]["name"] != "bob" {
+			t.Errorf("%s got standings %v, want alice then bob", client.Username, standings)
+		}
+		if len(messages) != 1 {
+			t.Errorf("%s got %d messages, want only the final results", client.Username, len(messages))
+		}
+	}
+}
+
+func TestReconnectStormSharesOneState(t *testing.T) {
+	h, game := newTestGame(t, nil)
+	names := make([]string, 16)
+	for i := range names {
+		names[i] = fmt.Sprintf("p%d", i)
+	}
+	joinTestPlayers(t, h, game, names...)
+
+	// Everyone reconnects in the same tick
+	var states []interface{}
+	for _, name := range names {
+		client := newTestClient(name, "id-"+name)
+		h.handleClientRegister(game, client)
+		messages := received(client)
+		if len(messages) != 1 || messages[0]["event"] != "game_update" {
+			t.Fatalf("%s got %v, want the game state", name, messages)
+		}
+		states = append(states, messages[0])
+	}
+	for _, state := range states[1:] {
+		if reflect.ValueOf(state).Pointer() != reflect.ValueOf(states[0]).Pointer() {
+			t.Fatal("reconnects in the same tick got separately built states")
+		}
+	}
+
+	// Anything going out invalidates it
+	h.broadcastToClients(game, schema.QueuedMessage{Message: map[string]interface{}{"event": "game_update"}, EnqueuedAt: time.Now()})
+	client := newTestClient("p0", "id-p0")
+	h.handleClientRegister(game, client)
+	if state := received(client)[0]; reflect.ValueOf(state).Pointer() == reflect.ValueOf(states[0]).Pointer() {
+		t.Error("a reconnect after a broadcast got the stale state")
+	}
+
+	// Building the state once for 16 clients allocates far less than for each of them
+	clients := make([]*schema.WebSocketClient, len(names))
+	for i, name := range names {
+		clients[i] = newTestClient(name, "id-"+name)
+	}
+	storm := func(shared bool) float64 {
+		return testing.AllocsPerRun(10, func() {
+			game.InitialState = nil
+			for _, client := range clients {
+				if !shared {
+					game.InitialState = nil
+				}
+				h.sendInitialState(game, client)
+				<-client.Send
+			}
+		})
+	}
+	shared, separate := storm(true), storm(false)
+	if shared*4 > separate {
+		t.Errorf("16 reconnects allocate %.0f times sharing the state, %.0f building it for each", shared, separate)
+	}
+}
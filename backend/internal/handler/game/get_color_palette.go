@@ -0,0 +1,13 @@
+package game
+
+import (
+	"net/http"
+
+	"github.com/yorukot/blind-party/internal/schema"
+	"github.com/yorukot/blind-party/pkg/response"
+)
+
+// GetColorPalette returns the color metadata table, indexed by WoolColor ID
+func (h *GameHandler) GetColorPalette(w http.ResponseWriter, r *http.Request) {
+	response.OK(w, schema.ColorPalette)
+}
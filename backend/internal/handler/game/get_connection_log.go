@@ -0,0 +1,47 @@
+package game
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/yorukot/blind-party/internal/schema"
+	"github.com/yorukot/blind-party/pkg/response"
+)
+
+// connectionLogSize is how many connection events a game keeps for its audit trail
+const connectionLogSize = 500
+
+// recordConnection adds a connection event to the game's audit trail, the caller holds game.Mu
+func recordConnection(game *schema.Game, client *schema.WebSocketClient, event schema.ConnectionEvent) {
+	if game.ConnectionLog == nil {
+		game.ConnectionLog = schema.NewConnectionLog(connectionLogSize)
+	}
+	game.ConnectionLog.Add(client, event, time.Now())
+}
+
+// GetConnectionLog lists a game's recent connects and disconnects, oldest
+// first, for admins auditing who was connected when
+func (h *GameHandler) GetConnectionLog(w http.ResponseWriter, r *http.Request) {
+	gameID := chi.URLParam(r, "gameID")
+	if gameID == "" {
+		response.Fail(w, http.StatusBadRequest, "MISSING_GAME_ID", "Game ID is required")
+		return
+	}
+
+	game, exists := h.getGame(gameID)
+	if !exists {
+		response.Fail(w, http.StatusNotFound, "GAME_NOT_FOUND", "Game not found")
+		return
+	}
+
+	game.Mu.RLock()
+	entries := []schema.ConnectionLogEntry{}
+	if game.ConnectionLog != nil {
+		entries = game.ConnectionLog.Entries()
+	}
+	game.Mu.RUnlock()
+
+	response.OKWithMeta(w, entries, &response.Meta{Total: len(entries)})
+}
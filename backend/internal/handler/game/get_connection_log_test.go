@@ -0,0 +1,80 @@
+package game
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+// getConnectionLog serves GetConnectionLog for the game like the router does
+func getConnectionLog(h *GameHandler, gameID string) *httptest.ResponseRecorder {
+	return serveRoute(h.GetConnectionLog, http.MethodGet, "/api/admin/game/{gameID}/connections", "/api/admin/game/"+gameID+"/connections", nil)
+}
+
+// connectionLog fetches the game's connection log from the admin endpoint
+func connectionLog(t *testing.T, h *GameHandler, gameID string) []schema.ConnectionLogEntry {
+	t.Helper()
+	recorder := getConnectionLog(h, gameID)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", recorder.Code, recorder.Body)
+	}
+	var body struct {
+		Data []schema.ConnectionLogEntry `json:"data"`
+	}
+	if err := json.NewDecoder(recorder.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	return body.Data
+}
+
+func TestConnectThenDisconnectIsLoggedInOrder(t *testing.T) {
+	h, game := newTestGame(t, nil)
+	runGame(t, h, game)
+	server := serveGames(t, h)
+
+	// bob stays so the game keeps running
+	bob := dialGame(t, server, game.ID, "/ws?username=bob&user_id=id-bob")
+	receiveUntil(t, bob, "game_update")
+	alice := dialGame(t, server, game.ID, "/ws?username=alice&user_id=id-alice")
+	receiveUntil(t, alice, "game_update")
+	alice.Close()
+
+	var entries []schema.ConnectionLogEntry
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		entries = nil
+		for _, entry := range connectionLog(t, h, game.ID) {
+			if entry.Username == "alice" {
+				entries = append(entries, entry)
+			}
+		}
+		if len(entries) >= 2 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("alice's entries %+v, want a connect and a disconnect", entries)
+	}
+	if entries[0].Event != schema.ConnectionConnect || entries[1].Event != schema.ConnectionDisconnect {
+		t.Errorf("events %s then %s, want connect then disconnect", entries[0].Event, entries[1].Event)
+	}
+	if entries[0].UserID != "id-alice" || entries[1].UserID != "id-alice" {
+		t.Errorf("user IDs %q and %q", entries[0].UserID, entries[1].UserID)
+	}
+	if entries[1].Timestamp.Before(entries[0].Timestamp) {
+		t.Errorf("disconnect at %v before the connect at %v", entries[1].Timestamp, entries[0].Timestamp)
+	}
+}
+
+func TestConnectionLogOfUnknownGame(t *testing.T) {
+	h, _ := newTestGame(t, nil)
+	if recorder := getConnectionLog(h, "missing"); recorder.Code != http.StatusNotFound {
+		t.Errorf("status %d, want 404", recorder.Code)
+	}
+}
@@ -0,0 +1,66 @@
+package game
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/yorukot/blind-party/internal/schema"
+	"github.com/yorukot/blind-party/pkg/response"
+)
+
+// diagnosticsResponse is the response of GetDiagnostics
+type diagnosticsResponse struct {
+	GameID              string           `json:"game_id"`
+	Phase               schema.GamePhase `json:"phase"`
+	Clients             int              `json:"clients"`
+	Observers           int              `json:"observers"`
+	BroadcastQueueDepth int              `json:"broadcast_queue_depth"` // Messages published but not yet handed to the clients
+	SendBufferCapacity  int              `json:"send_buffer_capacity"`
+	AverageSendDepth    float64          `json:"average_send_depth"` // Messages already waiting in a client's buffer when another was queued
+	MaxSendDepth        int              `json:"max_send_depth"`
+	DroppedMessages     int              `json:"dropped_messages"` // Messages that found a client's send buffer full
+	Broadcasts          int              `json:"broadcasts"`
+	AverageLatencyMs    float64          `json:"average_broadcast_latency_ms"` // From Publish to the clients' send buffers
+	MaxLatencyMs        float64          `json:"max_broadcast_latency_ms"`
+}
+
+// GetDiagnostics reports a game's WebSocket backpressure, for admins looking
+// into a laggy game
+func (h *GameHandler) GetDiagnostics(w http.ResponseWriter, r *http.Request) {
+	gameID := chi.URLParam(r, "gameID")
+	if gameID == "" {
+		response.Fail(w, http.StatusBadRequest, "MISSING_GAME_ID", "Game ID is required")
+		return
+	}
+
+	game, exists := h.getGame(gameID)
+	if !exists {
+		response.Fail(w, http.StatusNotFound, "GAME_NOT_FOUND", "Game not found")
+		return
+	}
+
+	response.OK(w, gameDiagnostics(game))
+}
+
+// gameDiagnostics snapshots a game's backpressure metrics
+func gameDiagnostics(game *schema.Game) diagnosticsResponse {
+	game.Mu.RLock()
+	defer game.Mu.RUnlock()
+
+	metrics := game.SendMetrics
+	return diagnosticsResponse{
+		GameID:              game.ID,
+		Phase:               game.Phase,
+		Clients:             len(game.Clients),
+		Observers:           len(game.Observers),
+		BroadcastQueueDepth: len(game.Broadcast),
+		SendBufferCapacity:  game.Config.ClientSendBuffer,
+		AverageSendDepth:    metrics.AverageDepth(),
+		MaxSendDepth:        metrics.MaxDepth,
+		DroppedMessages:     game.SendBufferFull,
+		Broadcasts:          metrics.Broadcasts,
+		AverageLatencyMs:    float64(metrics.AverageLatency().Microseconds()) / 1000,
+		MaxLatencyMs:        float64(metrics.MaxLatency.Microseconds()) / 1000,
+	}
+}
@@ -0,0 +1,64 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+func TestSlowClientsCountAsDroppedMessages(t *testing.T) {
+	const buffer = 4
+	h, game := newTestGame(t, func(cfg *schema.GameConfig) { cfg.ClientSendBuffer = buffer })
+	for _, name := range []string{"slow1", "slow2", "slow3", "fast"} {
+		client := newTestClient(name, "id-"+name)
+		if name == "fast" {
+			client.Send = make(chan interface{}, 1000)
+		} else {
+			client.Send = make(chan interface{}, buffer)
+		}
+		h.handleClientRegister(game, client)
+	}
+	runGame(t, h, game)
+
+	// None of the clients read while the messages go out
+	for i := 0; i < 2*buffer; i++ {
+		game.Publish(map[string]interface{}{"event": "player_update", "data": i})
+	}
+	// The slow clients are dropped before the last messages are delivered
+	deadline := time.Now().Add(2 * time.Second)
+	for d := gameDiagnostics(game); d.DroppedMessages < 3 || d.Broadcasts < 2*buffer; d = gameDiagnostics(game) {
+		if time.Now().After(deadline) {
+			t.Fatalf("diagnostics %+v, want the 3 slow clients dropped and every message delivered", d)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	recorder := serveRoute(h.GetDiagnostics, http.MethodGet, "/api/admin/game/{gameID}/diagnostics", "/api/admin/game/"+game.ID+"/diagnostics", nil)
+	var body struct {
+		Data diagnosticsResponse `json:"data"`
+	}
+	if err := json.NewDecoder(recorder.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	diagnostics := body.Data
+	if diagnostics.DroppedMessages != 3 || diagnostics.Clients != 1 {
+		t.Errorf("%d dropped messages and %d clients left, want 3 and the fast one", diagnostics.DroppedMessages, diagnostics.Clients)
+	}
+	if diagnostics.MaxSendDepth < buffer || diagnostics.SendBufferCapacity != buffer {
+		t.Errorf("max send depth %d with a capacity of %d, want at least a full buffer", diagnostics.MaxSendDepth, diagnostics.SendBufferCapacity)
+	}
+	if diagnostics.Broadcasts < 2*buffer || diagnostics.MaxLatencyMs < diagnostics.AverageLatencyMs {
+		t.Errorf("%d broadcasts with %.3fms max and %.3fms average latency",
+			diagnostics.Broadcasts, diagnostics.MaxLatencyMs, diagnostics.AverageLatencyMs)
+	}
+
+	recorder = serveRoute(h.Metrics, http.MethodGet, "/api/metrics", "/api/metrics", nil)
+	if want := fmt.Sprintf("blind_party_dropped_messages_total{game_id=%q} 3\n", game.ID); !strings.Contains(recorder.Body.String(), want) {
+		t.Errorf("metrics lack %q:\n%s", want, recorder.Body.String())
+	}
+}
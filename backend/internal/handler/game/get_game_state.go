@@ -1,28 +1,61 @@
 package game
 
 import (
+	"encoding/json"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/yorukot/blind-party/internal/schema"
 	"github.com/yorukot/blind-party/pkg/response"
 )
 
+// gameStateView is the game with its map encoded in the negotiated format
+type gameStateView struct {
+	*schema.Game
+	Map       interface{} `json:"map"`
+	MapFormat mapFormat   `json:"map_format"`
+	HostName  string      `json:"host_name,omitempty"`
+}
+
 // GetGameState returns the current state of a specific game
 func (h *GameHandler) GetGameState(w http.ResponseWriter, r *http.Request) {
 	// Extract gameID from URL parameters
 	gameID := chi.URLParam(r, "gameID")
 	if gameID == "" {
-		response.RespondWithError(w, http.StatusBadRequest, "Game ID is required", "MISSING_GAME_ID")
+		response.Fail(w, http.StatusBadRequest, "MISSING_GAME_ID", "Game ID is required")
 		return
 	}
 
-	// Look up the game in GameData map
-	game, exists := h.GameData[gameID]
+	// Look up the game in memory
+	game, exists := h.getGame(gameID)
 	if !exists {
-		response.RespondWithError(w, http.StatusNotFound, "Game not found", "GAME_NOT_FOUND")
+		response.Fail(w, http.StatusNotFound, "GAME_NOT_FOUND", "Game not found")
+		return
+	}
+
+	// Negotiate the map encoding, ?map_format=array|rle|packed
+	format, ok := parseMapFormat(r.URL.Query().Get("map_format"), game.Config.MapWidth, game.Config.MapHeight)
+	if !ok {
+		response.FailValidation(w, map[string]string{
+			"map_format": "must be one of array, rle, packed",
+		})
+		return
+	}
+
+	// Encode under the lock, the lifecycle keeps changing the game meanwhile
+	game.Mu.RLock()
+	state, err := json.Marshal(gameStateView{
+		Game:      game,
+		Map:       encodeMap(game.MapArray, format),
+		MapFormat: format,
+		HostName:  hostName(game),
+	})
+	game.Mu.RUnlock()
+	if err != nil {
+		response.Fail(w, http.StatusInternalServerError, "STATE_UNAVAILABLE", "Failed to encode the game state")
 		return
 	}
 
 	// Return the game state
-	response.RespondWithData(w, game)
-}
\ No newline at end of file
+	response.OK(w, json.RawMessage(state))
+}
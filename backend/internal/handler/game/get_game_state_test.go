@@ -0,0 +1,73 @@
+package game
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/yorukot/blind-party/internal/middleware"
+)
+
+func TestGameStateEnvelope(t *testing.T) {
+	h, game := newTestGame(t, nil)
+	joinTestPlayers(t, h, game, "alice", "bob")
+	h.storeGame(game)
+
+	router := chi.NewRouter()
+	router.Use(middleware.ZapLoggerMiddleware(zap.NewNop()))
+	router.Get("/api/game/{gameID}", h.GetGameState)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/game/"+game.ID, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+
+	var body struct {
+		Data map[string]json.RawMessage `json:"data"`
+		Meta struct {
+			RequestID string `json:"request_id"`
+		} `json:"meta"`
+		Error json.RawMessage `json:"error"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+
+	// The game itself, unwrapped, under data
+	for _, key := range []string{"game_id", "phase", "players", "player_count", "map", "map_format", "map_seed", "config", "round_number"} {
+		if _, ok := body.Data[key]; !ok {
+			t.Errorf("data has no %s", key)
+		}
+	}
+	var gameID string
+	json.Unmarshal(body.Data["game_id"], &gameID)
+	if gameID != game.ID {
+		t.Errorf("data.game_id = %q, want %q", gameID, game.ID)
+	}
+	if body.Meta.RequestID == "" || body.Meta.RequestID != rec.Header().Get("X-Request-ID") {
+		t.Errorf("meta.request_id = %q, header %q", body.Meta.RequestID, rec.Header().Get("X-Request-ID"))
+	}
+	if body.Error != nil {
+		t.Errorf("successful response has an error block: %s", body.Error)
+	}
+}
+
+func TestGameStateNotFound(t *testing.T) {
+	h := &GameHandler{}
+	rec := serveRoute(h.GetGameState, http.MethodGet, "/api/game/{gameID}", "/api/game/000000", nil)
+
+	var body struct {
+		Data  json.RawMessage `json:"data"`
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &body)
+	if rec.Code != http.StatusNotFound || body.Error.Code != "GAME_NOT_FOUND" || body.Data != nil {
+		t.Errorf("got %d %s", rec.Code, rec.Body)
+	}
+}
@@ -0,0 +1,136 @@
+package game
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/yorukot/blind-party/internal/schema"
+	"github.com/yorukot/blind-party/pkg/response"
+)
+
+// roundView is the slice of game state spectator overlays poll for
+type roundView struct {
+	RoundNumber  int                `json:"round_number"`
+	Phase        schema.RoundPhase  `json:"phase"`
+	ColorToShow  schema.WoolColor   `json:"color_to_show"`
+	ColorsToShow []schema.WoolColor `json:"colors_to_show"`
+	RushDuration float64            `json:"rush_duration"`
+	PhaseEndsAt  *time.Time         `json:"phase_ends_at,omitempty"` // Unset when the phase has no countdown
+	AliveCount   int                `json:"alive_count"`
+}
+
+// GetCurrentRound returns the called color and timing of the current round. It
+// responds 204 when no round is running, e.g. in pre-game or between rounds.
+func (h *GameHandler) GetCurrentRound(w http.ResponseWriter, r *http.Request) {
+	gameID := chi.URLParam(r, "gameID")
+	if gameID == "" {
+		response.Fail(w, http.StatusBadRequest, "MISSING_GAME_ID", "Game ID is required")
+		return
+	}
+
+	game, exists := h.getGame(gameID)
+	if !exists {
+		response.Fail(w, http.StatusNotFound, "GAME_NOT_FOUND", "Game not found")
+		return
+	}
+
+	game.Mu.RLock()
+	defer game.Mu.RUnlock()
+
+	round := game.CurrentRound
+	if round == nil {
+		response.NoContent(w)
+		return
+	}
+
+	view := roundView{
+		RoundNumber:  round.Number,
+		Phase:        round.Phase,
+		ColorToShow:  round.ColorToShow,
+		ColorsToShow: round.ColorsToShow,
+		RushDuration: round.RushDuration,
+		AliveCount:   game.AliveCount,
+	}
+	if round.Phase == schema.ColorCall && game.Countdown != nil {
+		endsAt := game.LastTick.Add(time.Duration(*game.Countdown * float64(time.Second)))
+		view.PhaseEndsAt = &endsAt
+	}
+
+	response.OK(w, view)
+}
+
+// roundDetailView is a past or running round for match review
+type roundDetailView struct {
+	*schema.Round
+	DurationSeconds float64                    `json:"duration_seconds,omitempty"` // Start to end, unset while the round runs
+	Eliminated      []schema.EliminationRecord `json:"eliminated"`
+	Survivors       []string                   `json:"survivors"`
+}
+
+// GetRound returns one round of the game by number, with who was eliminated in
+// it and who made it through
+func (h *GameHandler) GetRound(w http.ResponseWriter, r *http.Request) {
+	gameID := chi.URLParam(r, "gameID")
+	if gameID == "" {
+		response.Fail(w, http.StatusBadRequest, "MISSING_GAME_ID", "Game ID is required")
+		return
+	}
+
+	number, err := strconv.Atoi(chi.URLParam(r, "number"))
+	if err != nil {
+		response.FailValidation(w, map[string]string{"number": "must be a round number"})
+		return
+	}
+
+	game, exists := h.getGame(gameID)
+	if !exists {
+		response.Fail(w, http.StatusNotFound, "GAME_NOT_FOUND", "Game not found")
+		return
+	}
+
+	game.Mu.RLock()
+	defer game.Mu.RUnlock()
+
+	if number < 1 || number > len(game.Rounds) {
+		response.Fail(w, http.StatusNotFound, "ROUND_NOT_FOUND", "Round not found")
+		return
+	}
+	round := game.Rounds[number-1]
+
+	view := roundDetailView{
+		Round:      round,
+		Eliminated: make([]schema.EliminationRecord, 0),
+		Survivors:  make([]string, 0),
+	}
+	if round.EndTime != nil {
+		view.DurationSeconds = round.EndTime.Sub(round.StartTime).Seconds()
+	}
+
+	eliminatedBy := make(map[string]int)
+	for _, record := range game.Eliminations {
+		eliminatedBy[record.Name] = record.RoundNumber
+		if record.RoundNumber == round.Number {
+			view.Eliminated = append(view.Eliminated, record)
+		}
+	}
+
+	// Survivors played the round and weren't eliminated in it or before
+	if round.EndTime != nil {
+		for _, player := range game.Players {
+			if player.IsSpectator || player.JoinedRound > round.Number {
+				continue
+			}
+			if eliminatedIn, eliminated := eliminatedBy[player.Name]; eliminated && eliminatedIn <= round.Number {
+				continue
+			}
+			view.Survivors = append(view.Survivors, player.Name)
+		}
+		sort.Strings(view.Survivors)
+	}
+
+	response.OK(w, view)
+}
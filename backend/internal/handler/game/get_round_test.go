@@ -0,0 +1,111 @@
+package game
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+// getRound requests path, e.g. "/round" or "/round/2", of the game
+func getRound(t *testing.T, h *GameHandler, gameID, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	if path == "/round" {
+		return serveRoute(h.GetCurrentRound, http.MethodGet, "/api/game/{gameID}/round", "/api/game/"+gameID+path, nil)
+	}
+	return serveRoute(h.GetRound, http.MethodGet, "/api/game/{gameID}/round/{number}", "/api/game/"+gameID+path, nil)
+}
+
+func TestCurrentRoundInPreGameIsEmpty(t *testing.T) {
+	h, game := newTestGame(t, nil)
+	joinTestPlayers(t, h, game, "alice", "bob")
+	h.storeGame(game)
+
+	rec := getRound(t, h, game.ID, "/round")
+	if rec.Code != http.StatusNoContent || rec.Body.Len() != 0 {
+		t.Errorf("got %d %q, want an empty 204", rec.Code, rec.Body)
+	}
+}
+
+func TestCurrentRoundInGame(t *testing.T) {
+	h, game := newTestGame(t, nil)
+	joinTestPlayers(t, h, game, "alice", "bob")
+	h.startGame(game)
+	h.startNewRound(game)
+	h.storeGame(game)
+
+	rec := getRound(t, h, game.ID, "/round")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	var body struct {
+		Data roundView `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	view, round := body.Data, game.CurrentRound
+	if view.RoundNumber != 1 || view.Phase != schema.ColorCall || view.ColorToShow != round.ColorToShow ||
+		view.RushDuration != round.RushDuration || view.AliveCount != 2 {
+		t.Errorf("got %+v for round %+v", view, round)
+	}
+	if view.PhaseEndsAt == nil || view.PhaseEndsAt.Before(round.StartTime) {
+		t.Errorf("phase_ends_at = %v, want after the round start", view.PhaseEndsAt)
+	}
+
+	if rec := getRound(t, h, "000000", "/round"); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown game: status = %d, want 404", rec.Code)
+	}
+}
+
+func TestPastRoundDetails(t *testing.T) {
+	h, game, _ := startTestGame(t, nil, "alice", "bob", "carol")
+	judgeRound(t, h, game, "bob")
+	h.storeGame(game)
+
+	rec := getRound(t, h, game.ID, "/round/1")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	var body struct {
+		Data roundDetailView `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	view, round := body.Data, game.Rounds[0]
+	if view.Round == nil || view.Number != 1 || view.ColorToShow != round.ColorToShow || view.EndTime == nil {
+		t.Errorf("got round %+v, want %+v", view.Round, round)
+	}
+	if view.DurationSeconds <= 0 {
+		t.Errorf("duration = %v, want positive", view.DurationSeconds)
+	}
+	if len(view.Eliminated) != 1 || view.Eliminated[0].Name != "bob" || view.Eliminated[0].RoundNumber != 1 {
+		t.Errorf("eliminated = %+v, want bob", view.Eliminated)
+	}
+	if len(view.Survivors) != 2 || view.Survivors[0] != "alice" || view.Survivors[1] != "carol" {
+		t.Errorf("survivors = %v, want [alice carol]", view.Survivors)
+	}
+}
+
+func TestRoundNumberOutOfRange(t *testing.T) {
+	h, game, _ := startTestGame(t, nil, "alice", "bob")
+	h.startNewRound(game)
+	h.storeGame(game)
+
+	for path, want := range map[string]int{
+		"/round/0":   http.StatusNotFound,
+		"/round/2":   http.StatusNotFound,
+		"/round/-1":  http.StatusNotFound,
+		"/round/one": http.StatusBadRequest,
+	} {
+		if rec := getRound(t, h, game.ID, path); rec.Code != want {
+			t.Errorf("%s: status = %d, want %d", path, rec.Code, want)
+		}
+	}
+	if rec := getRound(t, h, "000000", "/round/1"); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown game: status = %d, want 404", rec.Code)
+	}
+}
@@ -0,0 +1,32 @@
+package game
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/yorukot/blind-party/pkg/response"
+)
+
+// GetViolations lists the anti-cheat violations recorded for a game, for admins.
+// The game may have ended and left memory, the reporter outlives it.
+func (h *GameHandler) GetViolations(w http.ResponseWriter, r *http.Request) {
+	gameID := chi.URLParam(r, "gameID")
+	if gameID == "" {
+		response.Fail(w, http.StatusBadRequest, "MISSING_GAME_ID", "Game ID is required")
+		return
+	}
+
+	if h.Violations == nil {
+		response.Fail(w, http.StatusNotFound, "VIOLATIONS_NOT_FOUND", "Violation reporting is disabled")
+		return
+	}
+
+	violations, err := h.Violations.List(gameID)
+	if err != nil {
+		response.Fail(w, http.StatusInternalServerError, "VIOLATIONS_UNAVAILABLE", "Failed to read violations")
+		return
+	}
+
+	response.OKWithMeta(w, violations, &response.Meta{Total: len(violations)})
+}
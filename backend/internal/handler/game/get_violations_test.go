@@ -0,0 +1,48 @@
+package game
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/yorukot/blind-party/internal/schema"
+	"github.com/yorukot/blind-party/internal/violation"
+)
+
+func TestRejectedMovementIsListedAfterGameEnds(t *testing.T) {
+	h, game := newTestGame(t, nil)
+	h.Violations = violation.NewMemoryReporter(100, time.Hour)
+	joinTestPlayers(t, h, game, "alice")
+	h.storeGame(game)
+
+	game.Mu.Lock()
+	player := game.Players["alice"]
+	player.LastMoveTime = time.Now().Add(-time.Second)
+	accepted := h.validateMovement(game, player, schema.Position{X: player.LastValidPosition.X + 15, Y: player.LastValidPosition.Y})
+	game.Mu.Unlock()
+	if accepted {
+		t.Fatal("a 15 block jump in a second was accepted")
+	}
+
+	// The game is gone, its violations aren't
+	h.removeGame(game.ID)
+
+	rec := serveRoute(h.GetViolations, http.MethodGet, "/api/game/{gameID}/violations", "/api/game/"+game.ID+"/violations", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+
+	var body struct {
+		Data []violation.Violation `json:"data"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.Data) != 1 {
+		t.Fatalf("listed %d violations, want 1", len(body.Data))
+	}
+	if v := body.Data[0]; v.Username != "alice" || v.UserID != "id-alice" || v.Reason != "movement_too_fast" {
+		t.Errorf("violation = %+v", v)
+	}
+}
@@ -0,0 +1,13 @@
+package game
+
+import (
+	"net/http"
+
+	"github.com/yorukot/blind-party/internal/wsschema"
+	"github.com/yorukot/blind-party/pkg/response"
+)
+
+// GetWSSchema returns the catalog of WebSocket messages with the shape of their data
+func (h *GameHandler) GetWSSchema(w http.ResponseWriter, r *http.Request) {
+	response.OK(w, wsschema.Catalog())
+}
@@ -0,0 +1,103 @@
+package game
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/yorukot/blind-party/internal/wsschema"
+)
+
+// sentEvents returns the event names of the message literals in a package's
+// non-test sources, with the file they were found in
+func sentEvents(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events := make(map[string]string)
+	fset := token.NewFileSet()
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		source, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsed, err := parser.ParseFile(fset, file, source, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ast.Inspect(parsed, func(node ast.Node) bool {
+			pair, ok := node.(*ast.KeyValueExpr)
+			if !ok {
+				return true
+			}
+			key, ok := pair.Key.(*ast.BasicLit)
+			if !ok || key.Value != `"event"` {
+				return true
+			}
+			if value, ok := pair.Value.(*ast.BasicLit); ok && value.Kind == token.STRING {
+				event, _ := strconv.Unquote(value.Value)
+				events[event] = filepath.Base(file)
+			}
+			return true
+		})
+	}
+	return events
+}
+
+func TestEverySentEventIsInTheCatalog(t *testing.T) {
+	events := sentEvents(t, ".")
+	for event, file := range sentEvents(t, "../../schema") {
+		events[event] = file
+	}
+	if len(events) < 20 {
+		t.Fatalf("found only %d events, is the scan broken?", len(events))
+	}
+
+	for event, file := range events {
+		if !wsschema.Registered(wsschema.ServerToClient, event) {
+			t.Errorf("%s sends %q, which is missing from the ws-schema catalog", file, event)
+		}
+	}
+}
+
+func TestWSSchemaServesTheCatalog(t *testing.T) {
+	h, _ := newTestGame(t, nil)
+	recorder := httptest.NewRecorder()
+	h.GetWSSchema(recorder, httptest.NewRequest(http.MethodGet, "/api/ws-schema", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status %d", recorder.Code)
+	}
+
+	var body struct {
+		Data []wsschema.Entry `json:"data"`
+	}
+	if err := json.NewDecoder(recorder.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.Data) != len(wsschema.Catalog()) {
+		t.Fatalf("served %d entries, catalog has %d", len(body.Data), len(wsschema.Catalog()))
+	}
+	for _, entry := range body.Data {
+		if entry.Event == "game_update" && entry.Direction == wsschema.ServerToClient {
+			if _, ok := entry.Data.(map[string]any)["target_color?"]; !ok {
+				t.Errorf("game_update data %v lacks target_color?", entry.Data)
+			}
+			return
+		}
+	}
+	t.Error("game_update is not served")
+}
@@ -1,7 +1,132 @@
 package game
 
-import "github.com/yorukot/blind-party/internal/schema"
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/yorukot/blind-party/internal/config"
+	"github.com/yorukot/blind-party/internal/schema"
+	"github.com/yorukot/blind-party/internal/violation"
+	"github.com/yorukot/blind-party/internal/webhook"
+	"github.com/yorukot/blind-party/pkg/ttlcache"
+)
 
 type GameHandler struct {
-	GameData map[string]*schema.Game
-}
\ No newline at end of file
+	// games holds the games in memory by ID. Game goroutines remove their own
+	// game while HTTP handlers read, so only touch it through the accessors below.
+	games   map[string]*schema.Game
+	gamesMu sync.RWMutex
+
+	// IdempotencyKeys maps scoped Idempotency-Key values to the game they created
+	IdempotencyKeys *ttlcache.Cache
+
+	// Violations records anti-cheat rejections for operators to review
+	Violations violation.Reporter
+
+	// Webhooks delivers game events to the webhook URL a game was created with
+	Webhooks *webhook.Notifier
+
+	// ReadTimeout closes connections that send nothing for this long, zero
+	// uses WS_READ_TIMEOUT_SECONDS
+	ReadTimeout time.Duration
+
+	// Rand drives game IDs and map seeds. Games get their own Rand seeded from
+	// the map seed. Leave nil for a time-seeded source, set a fixed one in tests.
+	Rand   *rand.Rand
+	randMu sync.Mutex
+
+	// tickHook runs at the start of every game tick when set, for tests
+	tickHook func(game *schema.Game)
+}
+
+// readTimeout returns how long a connection may stay silent before it is closed
+func (h *GameHandler) readTimeout() time.Duration {
+	if h.ReadTimeout > 0 {
+		return h.ReadTimeout
+	}
+	return time.Duration(config.Env().WSReadTimeoutSeconds) * time.Second
+}
+
+// randInt63 returns a non-negative random int63 from the handler's source
+func (h *GameHandler) randInt63() int64 {
+	h.randMu.Lock()
+	defer h.randMu.Unlock()
+	if h.Rand == nil {
+		h.Rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	return h.Rand.Int63()
+}
+
+// randIntn returns a random int in [0, n) from the handler's source
+func (h *GameHandler) randIntn(n int) int {
+	h.randMu.Lock()
+	defer h.randMu.Unlock()
+	if h.Rand == nil {
+		h.Rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	return h.Rand.Intn(n)
+}
+
+// getGame looks up a game in memory by ID
+func (h *GameHandler) getGame(gameID string) (*schema.Game, bool) {
+	h.gamesMu.RLock()
+	defer h.gamesMu.RUnlock()
+	game, exists := h.games[gameID]
+	return game, exists
+}
+
+// storeGame adds a game to memory under its ID
+func (h *GameHandler) storeGame(game *schema.Game) {
+	h.gamesMu.Lock()
+	defer h.gamesMu.Unlock()
+	if h.games == nil {
+		h.games = make(map[string]*schema.Game)
+	}
+	h.games[game.ID] = game
+}
+
+// removeGame drops a game from memory
+func (h *GameHandler) removeGame(gameID string) {
+	h.gamesMu.Lock()
+	defer h.gamesMu.Unlock()
+	delete(h.games, gameID)
+}
+
+// allGames returns a snapshot of the games in memory, safe to range over
+// while games are added and removed
+func (h *GameHandler) allGames() []*schema.Game {
+	h.gamesMu.RLock()
+	defer h.gamesMu.RUnlock()
+	games := make([]*schema.Game, 0, len(h.games))
+	for _, game := range h.games {
+		games = append(games, game)
+	}
+	return games
+}
+
+// GameCounts returns the number of games still running and the number kept in memory
+func (h *GameHandler) GameCounts() (active, total int) {
+	games := h.allGames()
+	for _, game := range games {
+		game.Mu.RLock()
+		if game.Phase != schema.Settlement {
+			active++
+		}
+		game.Mu.RUnlock()
+	}
+	return active, len(games)
+}
+
+// SendBufferFullCount returns how many messages found a client's send buffer
+// full across the games in memory. A steady rise means client_send_buffer is
+// too small for the message rate or clients are too slow.
+func (h *GameHandler) SendBufferFullCount() int {
+	count := 0
+	for _, game := range h.allGames() {
+		game.Mu.RLock()
+		count += game.SendBufferFull
+		game.Mu.RUnlock()
+	}
+	return count
+}
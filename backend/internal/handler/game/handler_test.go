@@ -0,0 +1,110 @@
+package game
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+func TestGameCountsOnlyCountsRunningGamesAsActive(t *testing.T) {
+	h := &GameHandler{}
+	for _, phase := range []schema.GamePhase{schema.PreGame, schema.InGame, schema.Settlement} {
+		_, game := newTestGame(t, nil)
+		game.ID = string(phase)
+		game.Phase = phase
+		h.storeGame(game)
+	}
+
+	if active, total := h.GameCounts(); active != 2 || total != 3 {
+		t.Errorf("GameCounts = %d active of %d, want 2 of 3", active, total)
+	}
+}
+
+func TestHandlersReturnTheEnvelope(t *testing.T) {
+	h := newGameHandler(t, time.Minute)
+	_, game := newTestGame(t, nil)
+	game.HostID = "id-host"
+	runGame(t, h, game)
+
+	router := chi.NewRouter()
+	router.Get("/api/colors", h.GetColorPalette)
+	router.Get("/api/ws-schema", h.GetWSSchema)
+	router.Post("/api/game", h.NewGame)
+	router.Get("/api/game/{gameID}/state", h.GetGameState)
+	router.Get("/api/game/{gameID}/round/{number}", h.GetRound)
+	router.Get("/api/game/{gameID}/replay", h.GetReplay)
+	router.Post("/api/game/{gameID}/regenerate-map", h.RegenerateMap)
+	router.Post("/api/game/{gameID}/map", h.UploadMap)
+	router.Get("/api/game/{gameID}/export/players.csv", h.ExportPlayersCSV)
+	router.Get("/api/game/{gameID}/export/rounds.csv", h.ExportRoundsCSV)
+	router.Get("/api/game/{gameID}/violations", h.GetViolations)
+	router.Get("/api/game/{gameID}/diagnostics", h.GetDiagnostics)
+	router.Get("/api/game/{gameID}/connections", h.GetConnectionLog)
+	router.Post("/api/game/{gameID}/pause", h.PauseGame)
+	router.Post("/api/game/{gameID}/resume", h.ResumeGame)
+
+	tests := []struct {
+		method, path string
+		body         any
+		status       int
+	}{
+		{http.MethodGet, "/api/colors", nil, http.StatusOK},
+		{http.MethodGet, "/api/ws-schema", nil, http.StatusOK},
+		{http.MethodPost, "/api/game", map[string]any{"user_id": "id-host"}, http.StatusOK},
+		{http.MethodPost, "/api/game", map[string]any{"config": map[string]any{"lives": -1}}, http.StatusBadRequest},
+		{http.MethodGet, "/api/game/" + game.ID + "/state", nil, http.StatusOK},
+		{http.MethodGet, "/api/game/missing/state", nil, http.StatusNotFound},
+		{http.MethodGet, "/api/game/" + game.ID + "/round/x", nil, http.StatusBadRequest},
+		{http.MethodGet, "/api/game/" + game.ID + "/replay", nil, http.StatusNotFound},
+		{http.MethodPost, "/api/game/" + game.ID + "/regenerate-map", map[string]any{"user_id": "id-host"}, http.StatusOK},
+		{http.MethodPost, "/api/game/" + game.ID + "/map", map[string]any{"user_id": "id-guest"}, http.StatusForbidden},
+		{http.MethodGet, "/api/game/missing/export/players.csv", nil, http.StatusNotFound},
+		{http.MethodGet, "/api/game/missing/export/rounds.csv", nil, http.StatusNotFound},
+		{http.MethodGet, "/api/game/" + game.ID + "/violations", nil, http.StatusNotFound},
+		{http.MethodGet, "/api/game/" + game.ID + "/diagnostics", nil, http.StatusOK},
+		{http.MethodGet, "/api/game/" + game.ID + "/connections", nil, http.StatusOK},
+		{http.MethodPost, "/api/game/" + game.ID + "/pause", nil, http.StatusOK},
+		{http.MethodPost, "/api/game/" + game.ID + "/resume", nil, http.StatusOK},
+		{http.MethodPost, "/api/game/" + game.ID + "/resume", nil, http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		var body io.Reader
+		if tt.body != nil {
+			body = jsonBody(t, tt.body)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, body))
+
+		name := tt.method + " " + tt.path
+		if rec.Code != tt.status {
+			t.Errorf("%s: status = %d, want %d: %s", name, rec.Code, tt.status, rec.Body)
+			continue
+		}
+		if got := rec.Header().Get("Content-Type"); got != "application/json" {
+			t.Errorf("%s: content type %q", name, got)
+		}
+		var envelope map[string]json.RawMessage
+		if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+			t.Errorf("%s: body is not JSON: %v", name, err)
+			continue
+		}
+		_, hasData := envelope["data"]
+		_, hasError := envelope["error"]
+		if hasData == hasError || (hasError != (tt.status >= 400)) {
+			t.Errorf("%s: body %s is not a data or error envelope", name, rec.Body)
+		}
+		for key := range envelope {
+			if key != "data" && key != "error" && key != "meta" {
+				t.Errorf("%s: unexpected top-level field %q", name, key)
+			}
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package game
+
+import (
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+// hardModeRound is a round as clients see it in hard mode during the color
+// call: the safe colors are hidden and the colors to avoid listed instead
+type hardModeRound struct {
+	*schema.Round
+	ColorToShow  *schema.WoolColor  `json:"color_to_show,omitempty"`  // Hidden, shadows the round's
+	ColorsToShow []schema.WoolColor `json:"colors_to_show,omitempty"` // Hidden, shadows the round's
+	UnsafeColors []schema.WoolColor `json:"unsafe_colors"`
+}
+
+// hidesSafeColors reports whether clients must work out the safe colors
+// themselves: in hard mode while the colors are being called. Once the unsafe
+// blocks are removed the answer is on the map anyway.
+func hidesSafeColors(game *schema.Game) bool {
+	return game.Config.HardMode && game.CurrentRound != nil && game.CurrentRound.Phase == schema.ColorCall
+}
+
+// unsafeColors returns every wool color that isn't safe in the round
+func unsafeColors(round *schema.Round) []schema.WoolColor {
+	colors := make([]schema.WoolColor, 0, int(schema.Black)+1)
+	for color := schema.White; color <= schema.Black; color++ {
+		if !round.IsSafe(color) {
+			colors = append(colors, color)
+		}
+	}
+	return colors
+}
+
+// withCalledColors adds the called colors to round update data: the safe
+// colors normally, the colors to avoid in hard mode
+func withCalledColors(game *schema.Game, data map[string]any) map[string]any {
+	if game.CurrentRound == nil {
+		return data
+	}
+	if hidesSafeColors(game) {
+		data["unsafe_colors"] = unsafeColors(game.CurrentRound)
+		return data
+	}
+	data["target_color"] = game.CurrentRound.ColorToShow
+	data["target_color_info"] = game.CurrentRound.ColorToShow.Info()
+	data["target_colors"] = append([]schema.WoolColor(nil), game.CurrentRound.ColorsToShow...)
+	return data
+}
@@ -0,0 +1,62 @@
+package game
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+func TestHardModeCallsTheColorsToAvoid(t *testing.T) {
+	for _, hard := range []bool{false, true} {
+		h, game, _ := startTestGame(t, func(cfg *schema.GameConfig) { cfg.HardMode = hard }, "alice", "bob")
+		published(game)
+
+		h.startNewRound(game)
+		round := game.CurrentRound
+		var call map[string]interface{}
+		for _, update := range withEvent(published(game), "game_update") {
+			if update["round_number"] == game.RoundNumber {
+				call = update
+			}
+		}
+		if call == nil {
+			t.Fatalf("hard %v: no round start announced", hard)
+		}
+
+		if !hard {
+			if call["target_color"] != round.ColorToShow || !slices.Equal(call["target_colors"].([]schema.WoolColor), round.ColorsToShow) {
+				t.Errorf("normal mode called %v, want the safe %s", call["target_colors"], round.ColorToShow)
+			}
+			if _, hidden := call["unsafe_colors"]; hidden {
+				t.Error("normal mode sent unsafe_colors")
+			}
+			continue
+		}
+
+		if _, shown := call["target_color"]; shown {
+			t.Errorf("hard mode gave away the safe color %v", call["target_color"])
+		}
+		unsafe := call["unsafe_colors"].([]schema.WoolColor)
+		if len(unsafe)+len(round.ColorsToShow) != int(schema.Black)+1 {
+			t.Errorf("hard mode called %d unsafe colors with %d safe, want all the others", len(unsafe), len(round.ColorsToShow))
+		}
+		for _, color := range unsafe {
+			if round.IsSafe(color) {
+				t.Errorf("safe %s called unsafe", color)
+			}
+		}
+
+		// The state sent to clients hides the round's safe colors too
+		state, err := json.Marshal(h.createGameStateMessage(game)["data"].(map[string]interface{})["round"])
+		if err != nil {
+			t.Fatal(err)
+		}
+		var shown map[string]any
+		json.Unmarshal(state, &shown)
+		if _, leaked := shown["color_to_show"]; leaked || len(shown["unsafe_colors"].([]any)) != len(unsafe) {
+			t.Errorf("hard mode state round %s", state)
+		}
+	}
+}
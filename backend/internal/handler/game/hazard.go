@@ -0,0 +1,108 @@
+package game
+
+import (
+	"log"
+	"time"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+// handleDropHazard lets an eliminated player turn a block to Air for the next
+// round, when the game runs with SpectatorHazards. The drop is broadcast right
+// away so survivors know to avoid the block.
+func (h *GameHandler) handleDropHazard(game *schema.Game, username string, message map[string]interface{}) {
+	game.Mu.Lock()
+	defer game.Mu.Unlock()
+
+	player, exists := game.Players[username]
+	if !exists {
+		log.Printf("Hazard from unknown user %s", username)
+		return
+	}
+
+	x, y, validCell := hazardCell(game, message)
+	if reason := hazardRejection(game, player, validCell); reason != "" {
+		log.Printf("Rejecting hazard from user %s: %s", username, reason)
+		sendToClient(game, username, map[string]interface{}{
+			"event": "hazard_rejected",
+			"data": map[string]interface{}{
+				"reason": reason,
+			},
+		})
+		return
+	}
+
+	player.HazardsDropped++
+	player.LastHazardAt = time.Now()
+	game.PendingHazards = append(game.PendingHazards, schema.Hazard{X: x, Y: y, DroppedBy: player.Name})
+	log.Printf("Player %s dropped a hazard at (%d, %d) in game %s", player.Name, x, y, game.ID)
+
+	game.Publish(map[string]interface{}{
+		"event": "hazard_dropped",
+		"data": map[string]interface{}{
+			"name":         player.Name,
+			"x":            x,
+			"y":            y,
+			"round_number": game.RoundNumber + 1,
+			"hazards_left": game.Config.HazardsPerSpectator - player.HazardsDropped,
+		},
+	})
+}
+
+// hazardCell reads the block a drop_hazard targets, false if it's missing,
+// off the map or in the border
+func hazardCell(game *schema.Game, message map[string]interface{}) (int, int, bool) {
+	data, ok := message["data"].(map[string]interface{})
+	if !ok {
+		return 0, 0, false
+	}
+	rawX, errX := parseFloat(data["x"])
+	rawY, errY := parseFloat(data["y"])
+	if errX != nil || errY != nil {
+		return 0, 0, false
+	}
+
+	x, y := int(rawX), int(rawY)
+	if _, inBounds := game.ColorAt(x, y); !inBounds || inBorder(game, x, y) {
+		return 0, 0, false
+	}
+	return x, y, true
+}
+
+// hazardRejection returns why the player can't drop a hazard right now, empty if they can
+func hazardRejection(game *schema.Game, player *schema.Player, validCell bool) string {
+	switch {
+	case !game.Config.SpectatorHazards:
+		return "hazards_disabled"
+	case game.Phase != schema.InGame:
+		return "game_not_in_progress"
+	case !player.IsEliminated:
+		return "not_eliminated"
+	case player.HazardsDropped >= game.Config.HazardsPerSpectator:
+		return "no_hazards_left"
+	case time.Since(player.LastHazardAt) < time.Duration(game.Config.HazardCooldownMs)*time.Millisecond:
+		return "cooldown"
+	case !validCell:
+		return "invalid_cell"
+	default:
+		return ""
+	}
+}
+
+// applyHazards turns the blocks of the pending hazards to Air on the freshly
+// generated map and returns them. The next map is generated from scratch, so
+// a hazard lasts exactly one round.
+func (h *GameHandler) applyHazards(game *schema.Game) []schema.Hazard {
+	hazards := game.PendingHazards
+	if len(hazards) == 0 {
+		return nil
+	}
+	game.PendingHazards = nil
+
+	for _, hazard := range hazards {
+		game.SetColorAt(hazard.X, hazard.Y, schema.Air)
+	}
+	h.syncMapArray(game)
+	log.Printf("Applied %d hazard(s) to round %d of game %s", len(hazards), game.RoundNumber, game.ID)
+	return hazards
+}
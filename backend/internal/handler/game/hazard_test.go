@@ -0,0 +1,119 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+// dropHazard sends a drop_hazard for the block at x, y as the player
+func dropHazard(h *GameHandler, game *schema.Game, name string, x, y int) {
+	h.handleDropHazard(game, name, map[string]interface{}{
+		"event": "drop_hazard",
+		"data":  map[string]interface{}{"x": float64(x), "y": float64(y)},
+	})
+}
+
+// hazardGame returns a running game with hazards on in which dave was eliminated in the first round
+func hazardGame(t *testing.T, configure func(*schema.GameConfig)) (*GameHandler, *schema.Game, map[string]*schema.WebSocketClient) {
+	t.Helper()
+	h, game, clients := startTestGame(t, func(cfg *schema.GameConfig) {
+		cfg.SpectatorHazards = true
+		cfg.HazardCooldownMs = 0
+		if configure != nil {
+			configure(cfg)
+		}
+	}, "alice", "bob", "carol", "dave")
+	judgeRound(t, h, game, "dave")
+	if !game.Players["dave"].IsEliminated {
+		t.Fatal("dave survived the first round")
+	}
+	game.CurrentRound = nil
+	published(game)
+	return h, game, clients
+}
+
+func TestHazardLastsOneRound(t *testing.T) {
+	h, game, _ := hazardGame(t, nil)
+	x, y := game.Config.MapWidth/2, game.Config.MapHeight/2
+
+	dropHazard(h, game, "dave", x, y)
+	if dropped := withEvent(published(game), "hazard_dropped"); len(dropped) != 1 || dropped[0]["round_number"] != 2 {
+		t.Fatalf("hazard_dropped %v, want one for round 2", dropped)
+	}
+
+	h.startNewRound(game)
+	if color, _ := game.ColorAt(x, y); color != schema.Air {
+		t.Errorf("the hazard block is %s in round 2, want Air", color)
+	}
+	if hazards := game.CurrentRound.Hazards; len(hazards) != 1 || hazards[0].X != x || hazards[0].Y != y || hazards[0].DroppedBy != "dave" {
+		t.Errorf("round 2 hazards %v, want dave's", hazards)
+	}
+	if game.MapArray[y][x] != int(schema.Air) {
+		t.Error("the map array doesn't show the hazard")
+	}
+
+	game.CurrentRound = nil
+	h.startNewRound(game)
+	if color, _ := game.ColorAt(x, y); color == schema.Air {
+		t.Error("the hazard block is still Air in round 3")
+	}
+	if len(game.CurrentRound.Hazards) != 0 {
+		t.Errorf("round 3 hazards %v, want none", game.CurrentRound.Hazards)
+	}
+}
+
+func TestHazardsPerSpectatorLimit(t *testing.T) {
+	h, game, clients := hazardGame(t, func(cfg *schema.GameConfig) {
+		cfg.HazardsPerSpectator = 2
+	})
+
+	for i := 0; i < 3; i++ {
+		dropHazard(h, game, "dave", 2+i, 2)
+	}
+	if dropped := withEvent(published(game), "hazard_dropped"); len(dropped) != 2 || dropped[1]["hazards_left"] != 0 {
+		t.Errorf("hazard_dropped %v, want 2 leaving none", dropped)
+	}
+	if len(game.PendingHazards) != 2 {
+		t.Errorf("%d pending hazards, want 2", len(game.PendingHazards))
+	}
+	if rejected := withEvent(received(clients["dave"]), "hazard_rejected"); len(rejected) != 1 || rejected[0]["reason"] != "no_hazards_left" {
+		t.Errorf("dave got hazard_rejected %v, want no_hazards_left", rejected)
+	}
+
+	// The limit holds over rounds, the next one doesn't refill it
+	game.CurrentRound = nil
+	h.startNewRound(game)
+	dropHazard(h, game, "dave", 2, 3)
+	if rejected := withEvent(received(clients["dave"]), "hazard_rejected"); len(rejected) != 1 || rejected[0]["reason"] != "no_hazards_left" {
+		t.Errorf("dave got hazard_rejected %v in round 2, want no_hazards_left", rejected)
+	}
+}
+
+func TestHazardRejections(t *testing.T) {
+	tests := []struct {
+		name      string
+		player    string
+		disabled  bool
+		x, y      int
+		wantCause string
+	}{
+		{name: "survivor", player: "alice", x: 2, y: 2, wantCause: "not_eliminated"},
+		{name: "disabled", player: "dave", disabled: true, x: 2, y: 2, wantCause: "hazards_disabled"},
+		{name: "off the map", player: "dave", x: -1, y: 2, wantCause: "invalid_cell"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, game, clients := hazardGame(t, nil)
+			game.Config.SpectatorHazards = !tt.disabled
+
+			dropHazard(h, game, tt.player, tt.x, tt.y)
+			if rejected := withEvent(received(clients[tt.player]), "hazard_rejected"); len(rejected) != 1 || rejected[0]["reason"] != tt.wantCause {
+				t.Errorf("hazard_rejected %v, want %s", rejected, tt.wantCause)
+			}
+			if len(game.PendingHazards) != 0 {
+				t.Errorf("%d pending hazards, want none", len(game.PendingHazards))
+			}
+		})
+	}
+}
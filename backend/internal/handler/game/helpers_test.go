@@ -0,0 +1,276 @@
+package game
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"golang.org/x/net/websocket"
+
+	"github.com/yorukot/blind-party/internal/config"
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	if _, err := config.InitConfig(); err != nil {
+		log.Fatal(err)
+	}
+	os.Exit(m.Run())
+}
+
+// newTestGame builds a game from the default config changed by configure,
+// with a fixed map seed and without starting its lifecycle
+func newTestGame(t *testing.T, configure func(*schema.GameConfig)) (*GameHandler, *schema.Game) {
+	t.Helper()
+	h := &GameHandler{Rand: rand.New(rand.NewSource(1))}
+	gameConfig := defaultGameConfig()
+	gameConfig.MapSeed = 42
+	if configure != nil {
+		configure(&gameConfig)
+	}
+	return h, h.buildGame(gameConfig)
+}
+
+// newTestClient returns a client whose messages queue up in Send
+func newTestClient(username, userID string) *schema.WebSocketClient {
+	return &schema.WebSocketClient{
+		Username:  username,
+		UserID:    userID,
+		Send:      make(chan interface{}, 256),
+		Connected: time.Now(),
+	}
+}
+
+// joinTestPlayers registers a client for each name, as if each connected
+func joinTestPlayers(t *testing.T, h *GameHandler, game *schema.Game, names ...string) map[string]*schema.WebSocketClient {
+	t.Helper()
+	clients := make(map[string]*schema.WebSocketClient, len(names))
+	for _, name := range names {
+		client := newTestClient(name, "id-"+name)
+		h.handleClientRegister(game, client)
+		if game.Clients[name] != client {
+			t.Fatalf("%s was not registered", name)
+		}
+		clients[name] = client
+	}
+	return clients
+}
+
+// startTestGame builds a game like newTestGame with a player for each name and
+// takes it in game, without starting a round
+func startTestGame(t *testing.T, configure func(*schema.GameConfig), names ...string) (*GameHandler, *schema.Game, map[string]*schema.WebSocketClient) {
+	t.Helper()
+	h, game := newTestGame(t, configure)
+	clients := joinTestPlayers(t, h, game, names...)
+	h.initializeAllPlayerStats(game)
+	game.Phase = schema.InGame
+	return h, game, clients
+}
+
+// blockWhere returns the center of the first block for which match is true
+func blockWhere(t *testing.T, game *schema.Game, match func(schema.WoolColor) bool) schema.Position {
+	t.Helper()
+	for y := 0; y < game.Config.MapHeight; y++ {
+		for x := 0; x < game.Config.MapWidth; x++ {
+			if color, _ := game.ColorAt(x, y); match(color) {
+				return spawnPoint(x, y)
+			}
+		}
+	}
+	t.Fatal("no matching block on the map")
+	return schema.Position{}
+}
+
+// startTestRound starts a round with a single safe color and returns a block
+// of that color and a block of another
+func startTestRound(t *testing.T, h *GameHandler, game *schema.Game) (safe, unsafe schema.Position) {
+	t.Helper()
+	h.startNewRound(game)
+	round := game.CurrentRound
+	round.ColorsToShow = []schema.WoolColor{round.ColorToShow}
+	safe = blockWhere(t, game, round.IsSafe)
+	unsafe = blockWhere(t, game, func(c schema.WoolColor) bool { return !round.IsSafe(c) && c != schema.Air })
+	return safe, unsafe
+}
+
+// judgeRound starts a round with a single safe color and runs its elimination
+// check with the wrong players on another color and everyone else on it
+func judgeRound(t *testing.T, h *GameHandler, game *schema.Game, wrong ...string) {
+	t.Helper()
+	safe, unsafe := startTestRound(t, h, game)
+	for _, player := range game.Players {
+		player.Position = safe
+	}
+	for _, name := range wrong {
+		game.Players[name].Position = unsafe
+	}
+	h.handleEliminationCheckPhase(game)
+}
+
+// allColorsSafe makes every wool color safe in the current round, so only
+// players off the map are caught
+func allColorsSafe(game *schema.Game) {
+	game.CurrentRound.ColorsToShow = nil
+	for color := schema.White; color <= schema.Black; color++ {
+		game.CurrentRound.ColorsToShow = append(game.CurrentRound.ColorsToShow, color)
+	}
+}
+
+// published drains the messages queued for broadcast
+func published(game *schema.Game) []map[string]interface{} {
+	var messages []map[string]interface{}
+	for {
+		select {
+		case queued := <-game.Broadcast:
+			message, _ := queued.Message.(map[string]interface{})
+			messages = append(messages, message)
+		default:
+			return messages
+		}
+	}
+}
+
+// received drains the messages sent to a client so far
+func received(client *schema.WebSocketClient) []map[string]interface{} {
+	var messages []map[string]interface{}
+	for {
+		select {
+		case message, open := <-client.Send:
+			if !open {
+				return messages
+			}
+			m, _ := message.(map[string]interface{})
+			messages = append(messages, m)
+		default:
+			return messages
+		}
+	}
+}
+
+// withEvent returns the data of every message with the given event
+func withEvent(messages []map[string]interface{}, event string) []map[string]interface{} {
+	var data []map[string]interface{}
+	for _, message := range messages {
+		if message["event"] == event {
+			d, _ := message["data"].(map[string]interface{})
+			data = append(data, d)
+		}
+	}
+	return data
+}
+
+// jsonBody encodes v as a request body
+func jsonBody(t *testing.T, v any) io.Reader {
+	t.Helper()
+	body, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bytes.NewReader(body)
+}
+
+// serveRoute serves a request to path from a router with only handler mounted,
+// at pattern, so the handler reads its URL parameters as it does in the app
+func serveRoute(handler http.HandlerFunc, method, pattern, path string, body io.Reader) *httptest.ResponseRecorder {
+	router := chi.NewRouter()
+	router.MethodFunc(method, pattern, handler)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(method, path, body))
+	return rec
+}
+
+// gameState fetches the game's state the way any client can and returns the
+// raw body
+func gameState(t *testing.T, h *GameHandler, gameID string) []byte {
+	t.Helper()
+	rec := serveRoute(h.GetGameState, http.MethodGet, "/api/game/{gameID}", "/api/game/"+gameID, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("game state: status = %d: %s", rec.Code, rec.Body)
+	}
+	return rec.Body.Bytes()
+}
+
+// serveGames serves h's game state and WebSocket routes like the router does
+func serveGames(t *testing.T, h *GameHandler) *httptest.Server {
+	t.Helper()
+	router := chi.NewRouter()
+	router.Get("/api/game/{gameID}", h.GetGameState)
+	router.Handle("/api/game/{gameID}/ws", websocket.Server{Handler: h.ConnectWebSocket, Handshake: NegotiateSubprotocol})
+	router.Handle("/api/game/{gameID}/observe", websocket.Server{Handler: h.ObserveWebSocket, Handshake: NegotiateSubprotocol})
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// runGame starts the game's lifecycle, stopping it and waiting for it to
+// return when the test ends
+func runGame(t *testing.T, h *GameHandler, game *schema.Game) {
+	t.Helper()
+	h.storeGame(game)
+	go h.GameLifeCycle(game)
+	t.Cleanup(func() {
+		game.Stop()
+		<-game.Done
+	})
+}
+
+// dialGame opens a WebSocket to path, e.g. "/ws?username=alice", of the game
+func dialGame(t *testing.T, server *httptest.Server, gameID, path string) *websocket.Conn {
+	t.Helper()
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/game/" + gameID + path
+	conn, err := websocket.Dial(url, "", server.URL)
+	if err != nil {
+		t.Fatalf("dial %s: %v", path, err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// receiveUntil reads messages until one with the event arrives and returns
+// its data, failing the test if the connection closes or stays quiet first
+func receiveUntil(t *testing.T, conn *websocket.Conn, event string) map[string]interface{} {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		var message map[string]interface{}
+		if err := websocket.JSON.Receive(conn, &message); err != nil {
+			t.Fatalf("waiting for %s: %v", event, err)
+		}
+		if message["event"] == event {
+			data, _ := message["data"].(map[string]interface{})
+			return data
+		}
+	}
+}
+
+// receiveClose reads messages until the server closes the connection and
+// returns the data of the connection_closing message sent before it, if any
+func receiveClose(t *testing.T, conn *websocket.Conn) map[string]interface{} {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var closing map[string]interface{}
+	for {
+		var message map[string]interface{}
+		err := websocket.JSON.Receive(conn, &message)
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			t.Fatal("connection still open")
+		}
+		if err != nil {
+			return closing
+		}
+		if message["event"] == "connection_closing" {
+			closing, _ = message["data"].(map[string]interface{})
+		}
+	}
+}
@@ -8,7 +8,7 @@ import (
 	"github.com/yorukot/blind-party/internal/schema"
 )
 
-func getRandomColor() schema.WoolColor {
+func getRandomColor(rng *rand.Rand) schema.WoolColor {
 	colors := []schema.WoolColor{
 		schema.White,     // 0
 		schema.Orange,    // 1
@@ -27,36 +27,54 @@ func getRandomColor() schema.WoolColor {
 		schema.Red,       // 14
 		schema.Black,     // 15
 	}
-	return colors[rand.Intn(len(colors))]
+	return colors[rng.Intn(len(colors))]
 }
 
 // generateRandomMap creates a new random map with all 16 colors
 func (h *GameHandler) generateRandomMap(game *schema.Game) {
 	for y := 0; y < game.Config.MapHeight; y++ {
 		for x := 0; x < game.Config.MapWidth; x++ {
-			game.Map[y][x] = getRandomColor()
+			game.SetColorAt(x, y, getRandomColor(game.Rand))
 		}
 	}
+	h.symmetrizeMap(game)
+	h.applyBorder(game)
+	h.syncMapArray(game)
 	log.Printf("Generated new random map for game %s", game.ID)
 }
 
-// removeNonTargetColors removes all blocks except the target color, turning them to Air
-func (h *GameHandler) removeNonTargetColors(game *schema.Game, targetColor schema.WoolColor) {
+// removeNonTargetColors removes all blocks except the round's safe colors, turning them to Air
+func (h *GameHandler) removeNonTargetColors(game *schema.Game, round *schema.Round) {
 	for y := 0; y < game.Config.MapHeight; y++ {
 		for x := 0; x < game.Config.MapWidth; x++ {
-			if game.Map[y][x] != targetColor {
-				game.Map[y][x] = schema.Air
+			if color, _ := game.ColorAt(x, y); !round.IsSafe(color) {
+				game.SetColorAt(x, y, schema.Air)
 			}
 		}
 	}
-	log.Printf("Removed all non-target colors except %d from game %s", targetColor, game.ID)
+	h.syncMapArray(game)
+	log.Printf("Removed all non-target colors except %v from game %s", round.ColorsToShow, game.ID)
 }
 
-// calculateRoundDuration returns the rush duration based on round number
-func (h *GameHandler) calculateRoundDuration(roundNumber int) float64 {
+// baseRushDuration is the rush duration of the first round, the longest one
+const baseRushDuration = 20.0
+
+// calculateRoundDuration returns the rush duration based on round number, from
+// the configured TimingProgression if any. Rounds past its last range keep
+// that range's duration.
+func (h *GameHandler) calculateRoundDuration(cfg schema.GameConfig, roundNumber int) float64 {
+	if len(cfg.TimingProgression) > 0 {
+		for _, r := range cfg.TimingProgression {
+			if roundNumber <= r.EndRound {
+				return r.Duration
+			}
+		}
+		return cfg.TimingProgression[len(cfg.TimingProgression)-1].Duration
+	}
+
 	// Progressive timing: starts at 20.0s and decreases to 80% each round
 	// Based on game.md requirement for decreasing countdown each round
-	baseDuration := 20.0
+	baseDuration := baseRushDuration
 	minDuration := 1.2
 
 	// Calculate duration as 80% of previous round (exponential decay)
@@ -71,7 +89,7 @@ func (h *GameHandler) calculateRoundDuration(roundNumber int) float64 {
 	return duration
 }
 
-func (h *GameHandler) eliminatePlayer(game *schema.Game, player *schema.Player) {
+func (h *GameHandler) eliminatePlayer(game *schema.Game, player *schema.Player, reason schema.EliminationReason) {
 	if player.IsEliminated {
 		return
 	}
@@ -79,29 +97,126 @@ func (h *GameHandler) eliminatePlayer(game *schema.Game, player *schema.Player)
 	player.IsEliminated = true
 	now := time.Now()
 	player.Stats.EliminatedAt = &now
-	player.Stats.RoundsSurvived = game.CurrentRound.Number - 1
+	player.Stats.RoundsSurvived = game.CurrentRound.Number - roundsCountedFrom(player) - player.Stats.DownedRounds
 	// Count alive players for final position
 	aliveCount := 0
 	for _, p := range game.Players {
-		if !p.IsEliminated {
+		if !p.IsEliminated && !p.IsSpectator {
 			aliveCount++
 		}
 	}
 	player.Stats.FinalPosition = aliveCount
+
+	game.Eliminations = append(game.Eliminations, newEliminationRecord(game, player, reason))
+	game.CurrentRound.EliminatedCount++
+	game.CurrentRound.EliminationReasons[reason]++
+
+	previousAlive := game.AliveCount
+	game.AliveCount = aliveCount
+	game.PendingSpectatorSwitches = append(game.PendingSpectatorSwitches, schema.SpectatorSwitch{Player: player, Reason: reason})
+	if !game.Config.BatchEliminationNotices {
+		h.sendSpectatorSwitches(game)
+	}
+	h.broadcastMilestones(game, previousAlive, aliveCount)
+}
+
+// sendSpectatorSwitches tells every eliminated player's client waiting for it
+// to switch to spectating, with the map and the players still in the game so
+// it can follow the rest of the action without waiting for the next state
+// update. A mass elimination is sent in this one pass under the caller's lock,
+// once every tie is ranked, and all of its players share the same alive list.
+func (h *GameHandler) sendSpectatorSwitches(game *schema.Game) {
+	if len(game.PendingSpectatorSwitches) == 0 {
+		return
+	}
+
+	alive := make([]*schema.Player, 0, game.AliveCount)
+	for _, p := range game.Players {
+		if !p.IsEliminated && !p.IsSpectator {
+			alive = append(alive, p)
+		}
+	}
+
+	// Between rounds the notices belong to the round that just ended
+	roundNumber := game.RoundNumber
+	if game.CurrentRound != nil {
+		roundNumber = game.CurrentRound.Number
+	}
+
+	for _, pending := range game.PendingSpectatorSwitches {
+		sendToClient(game, pending.Player.Name, map[string]any{
+			"event": "switch_to_spectator",
+			"data": map[string]any{
+				"round_number":   roundNumber,
+				"reason":         pending.Reason,
+				"final_position": placement(pending.Player),
+				"map":            game.MapArray,
+				"alive_players":  playersSnapshot(alive),
+				"alive_count":    len(alive),
+			},
+		})
+	}
+	game.PendingSpectatorSwitches = game.PendingSpectatorSwitches[:0]
+}
+
+// broadcastMilestones sends a milestone event for every configured threshold the
+// alive count dropped to or below. Each threshold fires at most once per game.
+func (h *GameHandler) broadcastMilestones(game *schema.Game, previousAlive, aliveCount int) {
+	for _, threshold := range game.Config.MilestoneThresholds {
+		if previousAlive <= threshold || aliveCount > threshold || game.MilestonesReached[threshold] {
+			continue
+		}
+		game.MilestonesReached[threshold] = true
+
+		log.Printf("Game %s reached milestone: %d players alive (threshold %d)", game.ID, aliveCount, threshold)
+		milestone := map[string]any{
+			"threshold":    threshold,
+			"alive_count":  aliveCount,
+			"round_number": game.CurrentRound.Number,
+		}
+		game.Publish(map[string]any{
+			"event": "milestone",
+			"data":  milestone,
+		})
+		h.notifyWebhook(game, "milestone", milestone)
+	}
 }
 
 // startNewRound initializes and starts a new round in the game
 func (h *GameHandler) startNewRound(game *schema.Game) {
 	game.RoundNumber++
 
-	// Step 1: Generate a new map (per game.md requirement)
-	h.generateRandomMap(game)
+	// Step 1: Generate a new map (per game.md requirement), the first round
+	// plays the host's custom map instead
+	if game.RoundNumber > 1 || !game.CustomMap {
+		h.generateRandomMap(game)
+		h.guaranteeSpawnDiversity(game)
+	}
+
+	// Knock out the blocks eliminated players picked, only on this round's map
+	hazards := h.applyHazards(game)
+
+	h.broadcastColorOdds(game)
+
+	// Bring back players who used a revive last round
+	h.reviveDownedPlayers(game)
 
 	// Step 2: Determine target color (per game.md requirement)
-	targetColor := getRandomColor()
+	targetColor, ok := h.pickTargetColor(game)
+	if !ok {
+		// No color can be survived, end the game instead of eliminating everyone
+		log.Printf("No colored blocks left on the map for round %d of game %s, ending the game", game.RoundNumber, game.ID)
+		game.RoundNumber--
+		h.endGame(game, time.Now(), nil)
+		return
+	}
 
 	// Step 3: Calculate progressive round duration (per game.md step 6)
-	rushDuration := h.calculateRoundDuration(game.RoundNumber)
+	rushDuration := h.calculateRoundDuration(game.Config, game.RoundNumber)
+	finalTwo := h.isFinalTwo(game)
+	if finalTwo {
+		rushDuration *= game.Config.FinalTwoSlowMo
+	}
 
 	game.CurrentRound = &schema.Round{
 		Number:       game.RoundNumber,
@@ -109,24 +224,38 @@ func (h *GameHandler) startNewRound(game *schema.Game) {
 		StartTime:    time.Now(),
 		EndTime:      nil,
 		ColorToShow:  targetColor,
+		ColorsToShow: h.pickSafeColors(game, targetColor, safeColorCount(game.Config, game.RoundNumber)),
 		RushDuration: rushDuration,
+		Warmup:       game.RoundNumber <= game.Config.WarmupRounds,
+
+		EliminationReasons: make(map[schema.EliminationReason]int),
+		PhaseStartedAt:     time.Now(),
+		Hazards:            hazards,
 	}
+	game.Rounds = append(game.Rounds, game.CurrentRound)
 
 	// Set countdown to rush duration (per game.md step 3)
 	game.Countdown = &rushDuration
 
-	log.Printf("Started round %d for game %s with target color %d and duration %.1fs",
+	log.Printf("Started round %d for game %s with target color %s and duration %.1fs",
 		game.RoundNumber, game.ID, targetColor, rushDuration)
 
 	// Broadcast new round start
-	game.Broadcast <- map[string]any{
+	game.Publish(map[string]any{
 		"event": "game_update",
-		"data": map[string]any{
+		"data": withCalledColors(game, map[string]any{
 			"round_number": game.RoundNumber,
-			"target_color": targetColor,
-			"countdown": rushDuration,
-			"map": h.convertMapToArray(game),
-		},
+			"countdown":    rushDuration,
+			"map":          game.MapArray,
+			"hazards":      hazards,
+		}),
+	})
+
+	if game.CurrentRound.Warmup {
+		h.broadcastWarmupRound(game)
+	}
+	if finalTwo {
+		h.broadcastFinalShowdown(game)
 	}
 }
 
@@ -136,24 +265,50 @@ func (h *GameHandler) convertMapToArray(game *schema.Game) [][]int {
 	for i := range mapArray {
 		mapArray[i] = make([]int, game.Config.MapWidth)
 		for j := range mapArray[i] {
-			mapArray[i][j] = int(game.Map[i][j])
+			color, _ := game.ColorAt(j, i)
+			mapArray[i][j] = int(color)
 		}
 	}
 	return mapArray
 }
 
+// syncMapArray rebuilds MapArray, the copy of the map sent to clients, from
+// Map. Every change to Map must be followed by it or clients see a stale map.
+func (h *GameHandler) syncMapArray(game *schema.Game) {
+	game.MapArray = h.convertMapToArray(game)
+}
+
 func (h *GameHandler) handleInGamePhase(game *schema.Game) {
+	// The hard cap ends the game wherever the round is
+	if h.gameDurationExceeded(game) {
+		h.endGameAtTimeLimit(game)
+		return
+	}
+
 	// Ensure there is a current round
 	if game.CurrentRound == nil {
+		// Wait out the first round grace or the break between rounds
+		if !h.roundBreakElapsed(game) {
+			return
+		}
 		h.startNewRound(game)
 		return
 	}
 
+	// A round stuck in a phase, e.g. from a clock anomaly, is forced along
+	if h.roundPhaseExpired(game) {
+		h.forceFinishPhase(game)
+		return
+	}
+
 	switch game.CurrentRound.Phase {
 	case schema.ColorCall:
 		h.handleColorCallPhase(game)
 	case schema.EliminationCheck:
 		h.handleEliminationCheckPhase(game)
+	default:
+		log.Printf("Round %d of game %s is in unknown phase %q", game.CurrentRound.Number, game.ID, game.CurrentRound.Phase)
+		h.forceFinishPhase(game)
 	}
 }
 
@@ -166,118 +321,178 @@ func (h *GameHandler) handleColorCallPhase(game *schema.Game) {
 	}
 
 	// Broadcast countdown update
-	game.Broadcast <- map[string]any{
+	game.Publish(map[string]any{
 		"event": "game_update",
-		"data": map[string]any{
-			"countdown_seconds": game.Countdown,
-			"target_color": game.CurrentRound.ColorToShow,
-		},
+		"data": withCalledColors(game, map[string]any{
+			"countdown_seconds": countdownSnapshot(game),
+		}),
+	})
+
+	h.sendSafetyHints(game)
+
+	// When countdown reaches 0 and the lag compensation window of the
+	// stalest player has passed, transition to elimination phase
+	if game.Countdown == nil || *game.Countdown <= -h.rushGracePeriod(game).Seconds() {
+		h.finishColorCall(game)
 	}
+}
 
-	// When countdown reaches 0, transition to elimination phase
-	if game.Countdown == nil || *game.Countdown <= 0 {
-		// Step 4: Remove all blocks except target color (per game.md requirement)
-		h.removeNonTargetColors(game, game.CurrentRound.ColorToShow)
+// finishColorCall removes the unsafe blocks and moves the round to the elimination check
+func (h *GameHandler) finishColorCall(game *schema.Game) {
+	// Freeze positions at the deadline so updates racing the next tick can't change the outcome
+	if game.Config.EliminationSnapshot {
+		h.snapshotPositions(game)
+	}
 
-		// Broadcast map change
-		game.Broadcast <- map[string]any{
-			"event": "game_update",
-			"data": map[string]any{
-				"map": h.convertMapToArray(game),
-				"blocks_removed": true,
-			},
-		}
+	// Step 4: Remove all blocks except target color (per game.md requirement)
+	h.removeNonTargetColors(game, game.CurrentRound)
+
+	// Broadcast map change
+	game.Publish(map[string]any{
+		"event": "game_update",
+		"data": map[string]any{
+			"map":            game.MapArray,
+			"blocks_removed": true,
+		},
+	})
+
+	h.broadcastColorOdds(game)
 
-		game.CurrentRound.Phase = schema.EliminationCheck
-		game.Countdown = nil
-		log.Printf("Round %d countdown finished, removed non-target blocks for game %s",
-			game.CurrentRound.Number, game.ID)
+	if err := advancePhase(game.CurrentRound, schema.EliminationCheck); err != nil {
+		log.Printf("Game %s: %v", game.ID, err)
 	}
+	game.Countdown = nil
+	log.Printf("Round %d countdown finished, removed non-target blocks for game %s",
+		game.CurrentRound.Number, game.ID)
 }
 
 func (h *GameHandler) handleEliminationCheckPhase(game *schema.Game) {
 	eliminatedPlayers := []string{}
+	eliminatedThisRound := []*schema.Player{}
+	stoodOn := make(map[string]schema.WoolColor) // Block each judged player stood on
+	safePlayers := []*schema.Player{}
+	caught := []caughtPlayer{}
+	firstElimination := len(game.Eliminations)
 
 	// Step 5: Check each non-eliminated player's position (per game.md requirement)
 	for _, player := range game.Players {
-		if player.IsEliminated {
+		if player.IsEliminated || player.IsSpectator || player.IsDowned {
 			continue
 		}
 
-		// Convert player position to map coordinates
-		// Player positions are 1-based, map is 0-based
-		// Add 0.5 adjustment for proper block center alignment
-		x := int(player.Position.X + 0.5)
-		y := int(player.Position.Y + 0.5)
+		// Late joiners can't be eliminated in the round they joined mid-way
+		if player.ImmuneRound == game.CurrentRound.Number {
+			log.Printf("Player %s is immune in round %d (late join)", player.Name, game.CurrentRound.Number)
+			continue
+		}
+
+		// Judge the position the player had when the rush ended
+		position := judgedPosition(game.CurrentRound, player)
+
+		// Convert player position to map coordinates, see worldToCell for
+		// which cell a position on a boundary belongs to
+		x, y := worldToCell(position, game.Config.CellEpsilon)
 
 		// Bounds checking
-		if x < 0 || x >= game.Config.MapWidth || y < 0 || y >= game.Config.MapHeight {
-			// Player is out of bounds, eliminate them
-			h.eliminatePlayer(game, player)
-			eliminatedPlayers = append(eliminatedPlayers, player.Name)
-			log.Printf("Player %s eliminated (out of bounds) at position (%.1f, %.1f)",
-				player.Name, player.Position.X, player.Position.Y)
+		blockUnder, inBounds := game.ColorAt(x, y)
+		stoodOn[player.Name] = blockUnder
+		if !inBounds {
+			// Player is out of bounds, eliminated below unless they can revive
+			caught = append(caught, caughtPlayer{player: player, reason: schema.EliminatedOutOfBounds})
+			log.Printf("Player %s out of bounds at position (%.1f, %.1f)",
+				player.Name, position.X, position.Y)
 			continue
 		}
 
 		// Check if player is standing on Air (eliminated) or wrong color
-		blockUnder := game.Map[y][x]
-		blockName := "Unknown"
-		targetName := "Unknown"
-
 		// Convert block values to readable names for debugging
-		if blockUnder == schema.Air {
-			blockName = "Air"
-		} else if blockUnder >= 0 && blockUnder <= 15 {
-			colorNames := []string{"White", "Orange", "Magenta", "LightBlue", "Yellow", "Lime", "Pink", "Gray", "LightGray", "Cyan", "Purple", "Blue", "Brown", "Green", "Red", "Black"}
-			blockName = colorNames[blockUnder]
-		}
-
-		if game.CurrentRound.ColorToShow >= 0 && game.CurrentRound.ColorToShow <= 15 {
-			colorNames := []string{"White", "Orange", "Magenta", "LightBlue", "Yellow", "Lime", "Pink", "Gray", "LightGray", "Cyan", "Purple", "Blue", "Brown", "Green", "Red", "Black"}
-			targetName = colorNames[game.CurrentRound.ColorToShow]
-		}
+		blockName := blockUnder.Info().Name
+		targetName := game.CurrentRound.ColorToShow.Info().Name
 
 		log.Printf("Player %s at position (%.2f, %.2f) -> adjusted (%.2f, %.2f) -> map[%d][%d] = %s(%d), target: %s(%d)",
-			player.Name, player.Position.X, player.Position.Y,
-			player.Position.X+0.5, player.Position.Y+0.5, y, x, blockName, blockUnder, targetName, game.CurrentRound.ColorToShow)
+			player.Name, position.X, position.Y,
+			position.X+0.5, position.Y+0.5, y, x, blockName, blockUnder, targetName, game.CurrentRound.ColorToShow)
 
-		if blockUnder == schema.Air || blockUnder != game.CurrentRound.ColorToShow {
-			h.eliminatePlayer(game, player)
-			eliminatedPlayers = append(eliminatedPlayers, player.Name)
+		if blockUnder == schema.Air || !game.CurrentRound.IsSafe(blockUnder) {
+			reason := schema.EliminatedWrongColor
 			if blockUnder == schema.Air {
-				log.Printf("Player %s eliminated (standing on Air) at position (%.1f, %.1f)",
-					player.Name, player.Position.X, player.Position.Y)
+				reason = schema.EliminatedOnAir
+			}
+			caught = append(caught, caughtPlayer{player: player, reason: reason})
+			if blockUnder == schema.Air {
+				log.Printf("Player %s standing on Air at position (%.1f, %.1f)",
+					player.Name, position.X, position.Y)
 			} else {
-				log.Printf("Player %s eliminated (wrong block: %s, target: %s) at position (%.1f, %.1f)",
-					player.Name, blockName, targetName, player.Position.X, player.Position.Y)
+				log.Printf("Player %s on the wrong block: %s, target: %s at position (%.1f, %.1f)",
+					player.Name, blockName, targetName, position.X, position.Y)
 			}
 		} else {
+			safePlayers = append(safePlayers, player)
 			log.Printf("Player %s survives round %d - standing on correct block %s",
 				player.Name, game.CurrentRound.Number, blockName)
 		}
 	}
 
+	// Caught players are eliminated unless they can revive, lives only save
+	// those still on the map. Past the round's elimination cap the rest are spared.
+	h.orderForEliminationCap(game, caught)
+	for _, c := range caught {
+		outOfBounds := c.reason == schema.EliminatedOutOfBounds
+		if h.spareWarmupPlayer(game, c.player, c.reason) || (!outOfBounds && h.loseLife(game, c.player, c.reason)) || h.downPlayer(game, c.player, c.reason) {
+			continue
+		}
+		if h.spareOverEliminationCap(game, c.player, c.reason, len(eliminatedThisRound)) {
+			continue
+		}
+		h.eliminatePlayer(game, c.player, c.reason)
+		eliminatedPlayers = append(eliminatedPlayers, c.player.Name)
+		eliminatedThisRound = append(eliminatedThisRound, c.player)
+		log.Printf("Player %s eliminated (%s)", c.player.Name, c.reason)
+	}
+
+	// In party mode a round nobody fails still costs the slowest responder
+	if len(caught) == 0 {
+		if slowest := h.eliminateSlowest(game, safePlayers); slowest != nil {
+			eliminatedPlayers = append(eliminatedPlayers, slowest.Name)
+			eliminatedThisRound = append(eliminatedThisRound, slowest)
+		}
+	}
+
+	// Players eliminated together are tied, earlier joiners rank higher
+	rankTiedEliminations(eliminatedThisRound)
+	h.sendSpectatorSwitches(game)
+
 	// Broadcast elimination results
 	if len(eliminatedPlayers) > 0 {
-		game.Broadcast <- map[string]any{
+		game.Publish(map[string]any{
 			"event": "game_update",
 			"data": map[string]any{
-				"eliminated_players": eliminatedPlayers,
-				"round_number": game.CurrentRound.Number,
-				"target_color": game.CurrentRound.ColorToShow,
+				"eliminated_players":  eliminatedPlayers,
+				"elimination_details": game.Eliminations[firstElimination:],
+				"eliminations":        eliminationAnimations(game.CurrentRound, eliminatedThisRound, stoodOn),
+				"round_number":        game.CurrentRound.Number,
+				"target_color":        game.CurrentRound.ColorToShow,
+				"target_color_info":   game.CurrentRound.ColorToShow.Info(),
+				"target_colors":       game.CurrentRound.ColorsToShow,
 			},
-		}
+		})
 	}
 
 	// End the current round
 	now := time.Now()
 	game.CurrentRound.EndTime = &now
+	h.calculateRoundScores(game, game.CurrentRound)
+
+	// In a score race the first player to ScoreToWin ends the game on the spot
+	if leaders := scoreLeaders(game); len(leaders) > 0 {
+		h.finishGame(game, now, leaders, VictoryScore)
+		return
+	}
 
 	// Count remaining alive players
 	aliveCount := 0
 	for _, player := range game.Players {
-		if !player.IsEliminated {
+		if !player.IsEliminated && !player.IsSpectator {
 			aliveCount++
 		}
 	}
@@ -285,52 +500,67 @@ func (h *GameHandler) handleEliminationCheckPhase(game *schema.Game) {
 
 	// Check if game should end (per game.md step 7)
 	if aliveCount <= 1 {
-		game.Phase = schema.Settlement
-		game.EndedAt = &now
-
-		// Find winner if there's exactly one player left
-		var winnerID string
-		for _, player := range game.Players {
-			if !player.IsEliminated {
-				winnerID = player.Name
-				break
-			}
-		}
-
-		game.Broadcast <- map[string]any{
-			"event": "game_update",
-			"data": map[string]any{
-				"winner_id": winnerID,
-				"end_time": now,
-				"total_rounds": game.RoundNumber,
-				"alive_count": aliveCount,
-			},
-		}
-
-		log.Printf("Game %s ended after %d rounds with winner: %s", game.ID, game.RoundNumber, winnerID)
+		h.endGame(game, now, eliminatedThisRound)
 	} else {
 		// Continue to next round (per game.md step 7)
 		log.Printf("Round %d completed for game %s, %d players remaining",
 			game.CurrentRound.Number, game.ID, aliveCount)
 
 		// Broadcast round end
-		game.Broadcast <- map[string]any{
+		game.Publish(map[string]any{
 			"event": "game_update",
 			"data": map[string]any{
-				"round_number": game.CurrentRound.Number,
-				"alive_count": aliveCount,
-				"next_round_in": 2.0, // 2 second break between rounds
+				"round_number":  game.CurrentRound.Number,
+				"alive_count":   aliveCount,
+				"next_round_in": game.Config.RoundBreatherSeconds,
 			},
-		}
+		})
 
-		// Clear current round and start next one after brief delay
+		// Clear current round, the next one starts after a brief break.
+		// The break is ticked by the lifecycle so the next round can't be
+		// started twice, a panic in it is recovered like any other and a
+		// game ended or stopped during it never starts another round.
 		game.CurrentRound = nil
-		game.Countdown = nil
+		startRoundBreather(game, now)
+	}
+}
+
+// startRoundBreather starts the RoundBreatherSeconds countdown to the next round
+func startRoundBreather(game *schema.Game, now time.Time) {
+	breather := game.Config.RoundBreatherSeconds
+	game.Countdown = &breather
+	game.LastTick = now
+}
+
+// roundBreakElapsed ticks the countdown before the next round and reports whether it may start
+func (h *GameHandler) roundBreakElapsed(game *schema.Game) bool {
+	if game.Countdown == nil {
+		return true
+	}
+
+	now := time.Now()
+	*game.Countdown -= now.Sub(game.LastTick).Seconds()
+	game.LastTick = now
+	if *game.Countdown > 0 {
+		return false
+	}
+
+	game.Countdown = nil
+	return true
+}
 
-		// Add small delay before next round starts (simulating rest period)
-		go func() {
-			time.Sleep(2 * time.Second)
-			h.startNewRound(game)
-		}()
+// eliminationAnimations describes each player eliminated in the round with what
+// clients need to animate the fall: their final rank, the block they stood on
+// (Air when they were off the map) and the color that was called
+func eliminationAnimations(round *schema.Round, eliminated []*schema.Player, stoodOn map[string]schema.WoolColor) []map[string]any {
+	animations := make([]map[string]any, 0, len(eliminated))
+	for _, player := range eliminated {
+		animations = append(animations, map[string]any{
+			"user_id":        player.Name,
+			"final_position": placement(player),
+			"stood_on_color": stoodOn[player.Name],
+			"called_color":   round.ColorToShow,
+		})
 	}
+	return animations
 }
@@ -0,0 +1,239 @@
+package game
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+func TestEliminationsCarryTheColorStoodOn(t *testing.T) {
+	h, game, _ := startTestGame(t, nil, "alice", "bob", "carol")
+	safe, _ := startTestRound(t, h, game)
+	target := game.CurrentRound.ColorToShow
+
+	game.Players["alice"].Position = safe
+	bobColor := schema.Air
+	game.Players["bob"].Position = blockWhere(t, game, func(c schema.WoolColor) bool {
+		if c == target || c == schema.Air {
+			return false
+		}
+		bobColor = c
+		return true
+	})
+	carolColor := schema.Air
+	game.Players["carol"].Position = blockWhere(t, game, func(c schema.WoolColor) bool {
+		if c == target || c == schema.Air || c == bobColor {
+			return false
+		}
+		carolColor = c
+		return true
+	})
+	published(game)
+
+	h.handleEliminationCheckPhase(game)
+
+	var eliminations []map[string]any
+	for _, update := range withEvent(published(game), "game_update") {
+		if e, ok := update["eliminations"].([]map[string]any); ok {
+			eliminations = append(eliminations, e...)
+		}
+	}
+	want := map[string]schema.WoolColor{"bob": bobColor, "carol": carolColor}
+	if len(eliminations) != len(want) {
+		t.Fatalf("eliminations = %v, want bob and carol", eliminations)
+	}
+	positions := make(map[any]bool)
+	for _, elimination := range eliminations {
+		name, _ := elimination["user_id"].(string)
+		if elimination["stood_on_color"] != want[name] {
+			t.Errorf("%s stood on %v, want %s", name, elimination["stood_on_color"], want[name])
+		}
+		if elimination["called_color"] != target {
+			t.Errorf("%s's called color is %v, want %s", name, elimination["called_color"], target)
+		}
+		positions[elimination["final_position"]] = true
+	}
+	// Eliminated together, one of them ranks second and the other third
+	if !positions[2] || !positions[3] {
+		t.Errorf("final positions %v, want 2 and 3", positions)
+	}
+}
+
+// assertMapArrayInSync fails the test if MapArray differs from Map
+func assertMapArrayInSync(t *testing.T, game *schema.Game, when string) {
+	t.Helper()
+	if len(game.MapArray) != game.Config.MapHeight {
+		t.Fatalf("%s: MapArray has %d rows, want %d", when, len(game.MapArray), game.Config.MapHeight)
+	}
+	for y, row := range game.MapArray {
+		for x, cell := range row {
+			if color, _ := game.ColorAt(x, y); cell != int(color) {
+				t.Fatalf("%s: MapArray has %d at (%d, %d), the map %d", when, cell, x, y, color)
+			}
+		}
+	}
+}
+
+func TestMapArrayFollowsTheMap(t *testing.T) {
+	h, game := newTestGame(t, nil)
+	joinTestPlayers(t, h, game, "alice", "bob")
+	assertMapArrayInSync(t, game, "new game")
+
+	game.SetColorAt(3, 4, schema.Air)
+	if game.MapArray[4][3] == int(schema.Air) {
+		t.Fatal("test block was Air already")
+	}
+	h.syncMapArray(game)
+	if game.MapArray[4][3] != int(schema.Air) {
+		t.Error("MapArray not updated by syncMapArray")
+	}
+
+	game.Phase = schema.InGame
+	h.startNewRound(game)
+	assertMapArrayInSync(t, game, "new round")
+	h.removeNonTargetColors(game, game.CurrentRound)
+	assertMapArrayInSync(t, game, "blocks removed")
+}
+
+func TestEliminatedPlayerIsSwitchedToSpectating(t *testing.T) {
+	h, game, clients := startTestGame(t, nil, "alice", "bob", "carol", "dave")
+
+	judgeRound(t, h, game, "carol")
+
+	switches := withEvent(received(clients["carol"]), "switch_to_spectator")
+	if len(switches) != 1 {
+		t.Fatalf("carol got %d switch_to_spectator messages, want 1", len(switches))
+	}
+	directive := switches[0]
+	if !reflect.DeepEqual(directive["map"], game.MapArray) {
+		t.Error("the directive doesn't carry the current map")
+	}
+	var alive []string
+	for _, player := range directive["alive_players"].([]schema.Player) {
+		alive = append(alive, player.Name)
+	}
+	sort.Strings(alive)
+	if strings.Join(alive, ",") != "alice,bob,dave" || directive["alive_count"] != 3 {
+		t.Errorf("alive players %v (%v counted), want alice, bob and dave", alive, directive["alive_count"])
+	}
+
+	for _, name := range []string{"alice", "bob", "dave"} {
+		if switches := withEvent(received(clients[name]), "switch_to_spectator"); len(switches) != 0 {
+			t.Errorf("surviving %s was switched to spectating", name)
+		}
+	}
+}
+
+func TestMassEliminationNoticesGoOutInOnePass(t *testing.T) {
+	names := []string{"p0", "p1", "p2", "p3", "p4", "p5", "p6", "p7", "p8", "p9"}
+	for _, batch := range []bool{true, false} {
+		t.Run(fmt.Sprintf("batch %v", batch), func(t *testing.T) {
+			h, game, clients := startTestGame(t, func(cfg *schema.GameConfig) {
+				cfg.BatchEliminationNotices = batch
+			}, names...)
+
+			// Judge under the lock the tick holds; taking it again would deadlock
+			game.Mu.Lock()
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				judgeRound(t, h, game, names[2:]...)
+			}()
+			select {
+			case <-done:
+			case <-time.After(2 * time.Second):
+				t.Fatal("the elimination check tried to take the game lock again")
+			}
+			game.Mu.Unlock()
+
+			var counts []any
+			for _, name := range names[2:] {
+				switches := withEvent(received(clients[name]), "switch_to_spectator")
+				if len(switches) != 1 {
+					t.Fatalf("%s got %d switch_to_spectator messages, want 1", name, len(switches))
+				}
+				counts = append(counts, switches[0]["alive_count"])
+			}
+			if len(game.PendingSpectatorSwitches) != 0 {
+				t.Errorf("%d notices left pending", len(game.PendingSpectatorSwitches))
+			}
+
+			// A batch is sent once every player is judged, so all notices see the same survivors
+			for _, count := range counts {
+				if batch && count != 2 {
+					t.Fatalf("batched alive counts %v, want all 2", counts)
+				}
+			}
+			distinct := make(map[any]bool)
+			for _, count := range counts {
+				distinct[count] = true
+			}
+			if !batch && len(distinct) != len(counts) {
+				t.Errorf("unbatched alive counts %v, want each sent as its player fell", counts)
+			}
+		})
+	}
+}
+
+// breatherGame returns a game whose first round just ended, in the breather before the second
+func breatherGame(t *testing.T, breather float64) (*GameHandler, *schema.Game) {
+	t.Helper()
+	h, game, _ := startTestGame(t, func(cfg *schema.GameConfig) { cfg.RoundBreatherSeconds = breather },
+		"alice", "bob", "carol", "dave")
+	judgeRound(t, h, game, "dave")
+	if game.CurrentRound != nil || game.Countdown == nil {
+		t.Fatal("the first round didn't end in a breather")
+	}
+	return h, game
+}
+
+func TestNextRoundStartsAfterTheBreather(t *testing.T) {
+	const breather = 0.2
+	h, game := breatherGame(t, breather)
+	ended := time.Now()
+
+	var announced []any
+	for _, update := range withEvent(published(game), "game_update") {
+		if next, ok := update["next_round_in"]; ok {
+			announced = append(announced, next)
+		}
+	}
+	if len(announced) != 1 || announced[0] != breather {
+		t.Errorf("next_round_in %v, want %v", announced, breather)
+	}
+
+	for game.RoundNumber == 1 {
+		if time.Since(ended) > 2*time.Second {
+			t.Fatal("the next round never started")
+		}
+		time.Sleep(10 * time.Millisecond)
+		h.processGameState(game)
+		published(game)
+	}
+	if waited := time.Since(ended).Seconds(); waited < breather || waited > breather+0.5 {
+		t.Errorf("round 2 started after %.2fs, want about %v", waited, breather)
+	}
+}
+
+func TestStoppingDuringTheBreatherStartsNoRound(t *testing.T) {
+	const breather = 0.2
+	h, game := breatherGame(t, breather)
+	runGame(t, h, game)
+
+	wait := time.Duration(breather * float64(time.Second))
+	time.Sleep(wait / 4)
+	game.Stop()
+	<-game.Done
+	time.Sleep(2 * wait)
+
+	game.Mu.RLock()
+	defer game.Mu.RUnlock()
+	if game.RoundNumber != 1 || game.CurrentRound != nil {
+		t.Errorf("in round %d after being stopped during the breather", game.RoundNumber)
+	}
+}
@@ -0,0 +1,72 @@
+package game
+
+import (
+	"log"
+	"sort"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+// admitLateJoiner decides how a player joining a running game takes part. Within
+// the first LateJoinRounds rounds they play: they spawn on a colored block and
+// can't be eliminated in the round they joined mid-way. Later they spectate.
+func (h *GameHandler) admitLateJoiner(game *schema.Game, player *schema.Player) {
+	if game.Phase != schema.InGame || player.JoinedRound > game.Config.LateJoinRounds {
+		player.IsSpectator = true
+		log.Printf("Player %s joined game %s in round %d as spectator", player.Name, game.ID, player.JoinedRound)
+		return
+	}
+
+	if spawns := h.validSpawnPositions(game); len(spawns) > 0 {
+		placeAtSpawn(player, spawns[0])
+	}
+	player.Stats.RoundsSurvived = 0
+	player.RevivesLeft = game.Config.RevivesPerPlayer
+	player.LivesLeft = game.Config.Lives
+
+	if game.CurrentRound != nil {
+		player.ImmuneRound = game.CurrentRound.Number
+	}
+
+	log.Printf("Player %s late joined game %s in round %d at (%.1f, %.1f)",
+		player.Name, game.ID, player.JoinedRound, player.Position.X, player.Position.Y)
+
+	game.Publish(map[string]any{
+		"event": "late_player_joined",
+		"data": map[string]any{
+			"player":       *player,
+			"joined_round": player.JoinedRound,
+			"immune_round": player.ImmuneRound,
+		},
+	})
+}
+
+// roundsCountedFrom returns the first round that counts towards a player's
+// survival, so late joiners only accrue from the round they joined
+func roundsCountedFrom(player *schema.Player) int {
+	if player.JoinedRound > 1 {
+		return player.JoinedRound
+	}
+	return 1
+}
+
+// rankTiedEliminations reassigns the final positions of players eliminated in the
+// same check so that ties break in favor of the player who joined earlier
+func rankTiedEliminations(players []*schema.Player) {
+	if len(players) < 2 {
+		return
+	}
+
+	positions := make([]int, len(players))
+	for i, player := range players {
+		positions[i] = player.Stats.FinalPosition
+	}
+	sort.Ints(positions)
+
+	sort.SliceStable(players, func(i, j int) bool {
+		return players[i].JoinedRound < players[j].JoinedRound
+	})
+	for i, player := range players {
+		player.Stats.FinalPosition = positions[i]
+	}
+}
@@ -0,0 +1,94 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+func TestLateJoinerIsImmuneInTheRoundTheyJoined(t *testing.T) {
+	h, game, _ := startTestGame(t, nil, "alice", "bob", "carol")
+	game.RoundNumber = 1
+	h.startNewRound(game)
+	published(game)
+
+	joinTestPlayers(t, h, game, "late")
+	late := game.Players["late"]
+	if late.IsSpectator || late.IsEliminated {
+		t.Fatalf("late joiner in round 2 is a spectator or eliminated: %+v", late)
+	}
+	if late.JoinedRound != 2 || late.ImmuneRound != 2 {
+		t.Errorf("joined round %d, immune round %d, want 2 and 2", late.JoinedRound, late.ImmuneRound)
+	}
+	x, y := worldToCell(late.Position, game.Config.CellEpsilon)
+	if color, ok := game.ColorAt(x, y); !ok || color == schema.Air {
+		t.Errorf("late joiner spawned on %v at (%d, %d)", color, x, y)
+	}
+	joined := withEvent(published(game), "late_player_joined")
+	if len(joined) != 1 || joined[0]["joined_round"] != 2 {
+		t.Errorf("late_player_joined = %v", joined)
+	}
+
+	// Off the map in the round they joined, they are spared
+	allColorsSafe(game)
+	late.Position = schema.Position{X: -5, Y: -5}
+	h.handleEliminationCheckPhase(game)
+	if late.IsEliminated {
+		t.Fatal("late joiner was eliminated in the round they joined")
+	}
+
+	// The next round judges them like everyone else
+	h.startNewRound(game)
+	allColorsSafe(game)
+	late.Position = schema.Position{X: -5, Y: -5}
+	h.handleEliminationCheckPhase(game)
+	if !late.IsEliminated {
+		t.Error("late joiner was spared in the round after they joined")
+	}
+}
+
+func TestLateJoinerAfterWindowSpectates(t *testing.T) {
+	h, game, _ := startTestGame(t, nil, "alice", "bob")
+	game.RoundNumber = game.Config.LateJoinRounds
+	h.startNewRound(game)
+
+	joinTestPlayers(t, h, game, "late")
+	if !game.Players["late"].IsSpectator {
+		t.Errorf("player joining in round %d plays", game.CurrentRound.Number)
+	}
+}
+
+func TestSpectatorCapIsSeparateFromPlayers(t *testing.T) {
+	h, game, _ := startTestGame(t, func(cfg *schema.GameConfig) { cfg.MaxSpectators = 2 }, "alice", "bob")
+	game.RoundNumber = game.Config.LateJoinRounds
+	h.startNewRound(game)
+
+	joinTestPlayers(t, h, game, "carol", "dave")
+	if game.PlayerCount != 2 || game.AliveCount != 2 || game.SpectatorCount != 2 {
+		t.Errorf("%d players, %d alive and %d spectators after two spectators joined",
+			game.PlayerCount, game.AliveCount, game.SpectatorCount)
+	}
+
+	extra := newTestClient("erin", "id-erin")
+	h.handleClientRegister(game, extra)
+	if _, joined := game.Players["erin"]; joined {
+		t.Error("a spectator joined past the spectator limit")
+	}
+	if extra.CloseCode != closeSpectatorsFull.Code {
+		t.Errorf("rejected spectator closed with %d, want %d", extra.CloseCode, closeSpectatorsFull.Code)
+	}
+	if game.PlayerCount != 2 || game.SpectatorCount != 2 {
+		t.Errorf("%d players and %d spectators after the rejection", game.PlayerCount, game.SpectatorCount)
+	}
+}
+
+func TestTiedEliminationsRankEarlierJoinerHigher(t *testing.T) {
+	early := &schema.Player{Name: "early", JoinedRound: 0, Stats: schema.PlayerStats{FinalPosition: 3}}
+	late := &schema.Player{Name: "late", JoinedRound: 2, Stats: schema.PlayerStats{FinalPosition: 2}}
+
+	rankTiedEliminations([]*schema.Player{late, early})
+
+	if early.Stats.FinalPosition != 2 || late.Stats.FinalPosition != 3 {
+		t.Errorf("early placed %d, late placed %d, want 2 and 3", early.Stats.FinalPosition, late.Stats.FinalPosition)
+	}
+}
@@ -0,0 +1,32 @@
+package game
+
+import (
+	"log"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+// loseLife takes one of the player's extra lives instead of eliminating them.
+// Unlike a revive the player stays where they are and keeps playing this round.
+// It reports false if the player is on their last life.
+func (h *GameHandler) loseLife(game *schema.Game, player *schema.Player, reason schema.EliminationReason) bool {
+	if player.LivesLeft <= 1 {
+		return false
+	}
+
+	player.LivesLeft--
+	player.LostLifeIn = game.CurrentRound.Number
+	log.Printf("Player %s lost a life (%s) in round %d, %d lives left",
+		player.Name, reason, game.CurrentRound.Number, player.LivesLeft)
+
+	game.Publish(map[string]any{
+		"event": "life_lost",
+		"data": map[string]any{
+			"name":         player.Name,
+			"reason":       reason,
+			"round_number": game.CurrentRound.Number,
+			"lives_left":   player.LivesLeft,
+		},
+	})
+	return true
+}
@@ -0,0 +1,32 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+func TestSecondLifeSurvivesOneWrongRound(t *testing.T) {
+	h, game, _ := startTestGame(t, func(cfg *schema.GameConfig) {
+		cfg.Lives = 2
+	}, "alice", "bob", "carol")
+	alice := game.Players["alice"]
+
+	judgeRound(t, h, game, "alice")
+	if alice.IsEliminated || alice.LivesLeft != 1 || alice.LostLifeIn != 1 {
+		t.Fatalf("after the first wrong round alice eliminated %v with %d lives, lost one in round %d",
+			alice.IsEliminated, alice.LivesLeft, alice.LostLifeIn)
+	}
+	lost := withEvent(published(game), "life_lost")
+	if len(lost) != 1 || lost[0]["name"] != "alice" || lost[0]["lives_left"] != 1 {
+		t.Errorf("life_lost = %v", lost)
+	}
+
+	judgeRound(t, h, game, "alice")
+	if !alice.IsEliminated {
+		t.Error("alice survived a wrong round on their last life")
+	}
+	if game.Players["bob"].IsEliminated || game.Players["bob"].LivesLeft != 2 {
+		t.Error("bob lost a life on the safe color")
+	}
+}
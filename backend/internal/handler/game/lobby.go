@@ -0,0 +1,99 @@
+package game
+
+import (
+	"time"
+
+	"github.com/yorukot/blind-party/internal/config"
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+// notifyRosterChange tells clients a player joined or left. In the lobby the
+// change is coalesced into the next throttled lobby_update, unless it's the
+// host, whose comings and goings are sent right away. HostID is a user ID, so
+// is userID. Callers must hold game.Mu.
+func (h *GameHandler) notifyRosterChange(game *schema.Game, userID string) {
+	game.InitialState = nil
+	if game.Phase != schema.PreGame {
+		game.Publish(h.createGameStateMessage(game))
+		return
+	}
+
+	game.LobbyDirty = true
+	if game.HostID != "" && userID == game.HostID {
+		h.broadcastLobbyUpdate(game)
+	}
+}
+
+// flushLobbyUpdate broadcasts pending roster changes once the throttle interval has passed
+func (h *GameHandler) flushLobbyUpdate(game *schema.Game) {
+	if !game.LobbyDirty {
+		return
+	}
+	interval := time.Duration(game.Config.LobbyUpdateIntervalMs) * time.Millisecond
+	if time.Since(game.LastLobbyUpdate) < interval {
+		return
+	}
+	h.broadcastLobbyUpdate(game)
+}
+
+// lobbyStateDue reports whether the lobby's full game state should be
+// broadcast this tick. Nothing but the countdown moves before the game starts,
+// so it goes out at the lobby_update interval rather than every tick.
+func lobbyStateDue(game *schema.Game) bool {
+	interval := time.Duration(game.Config.LobbyUpdateIntervalMs) * time.Millisecond
+	if time.Since(game.LastLobbyState) < interval {
+		return false
+	}
+	game.LastLobbyState = time.Now()
+	return true
+}
+
+// broadcastLobbyUpdate sends the full lobby roster and counts
+func (h *GameHandler) broadcastLobbyUpdate(game *schema.Game) {
+	players := make([]*schema.Player, 0, len(game.Players))
+	for _, player := range game.Players {
+		players = append(players, player)
+	}
+
+	game.Publish(map[string]interface{}{
+		"event": "lobby_update",
+		"data": map[string]interface{}{
+			"game_id":      game.ID,
+			"host_name":    hostName(game),
+			"players":      playersSnapshot(players),
+			"player_count": game.PlayerCount,
+			"min_players":  config.Env().MinPlayers,
+			"max_players":  config.Env().MaxPlayers,
+		},
+	})
+
+	game.LobbyDirty = false
+	game.LastLobbyUpdate = time.Now()
+}
+
+// hostName is the name of the player who joined as the host, empty while they
+// aren't in the game. Clients get this rather than HostID, which would let
+// anyone act as the host.
+func hostName(game *schema.Game) string {
+	if game.HostID == "" {
+		return ""
+	}
+	for _, player := range game.Players {
+		if player.UserID == game.HostID {
+			return player.Name
+		}
+	}
+	return ""
+}
+
+// removePlayer returns a copy of the players list without the player. Queued
+// messages may still reference the old list, so it isn't changed in place.
+func removePlayer(players []*schema.Player, player *schema.Player) []*schema.Player {
+	remaining := make([]*schema.Player, 0, len(players))
+	for _, p := range players {
+		if p != player {
+			remaining = append(remaining, p)
+		}
+	}
+	return remaining
+}
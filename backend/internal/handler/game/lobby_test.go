@@ -0,0 +1,51 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+func TestRapidJoinsAreThrottled(t *testing.T) {
+	h, game := newTestGame(t, func(cfg *schema.GameConfig) {
+		cfg.LobbyUpdateIntervalMs = 60 * 60 * 1000
+	})
+	names := []string{"p1", "p2", "p3"}
+	joinTestPlayers(t, h, game, names...)
+
+	for i := 0; i < 5; i++ {
+		h.processGameState(game)
+	}
+	messages := published(game)
+
+	updates := withEvent(messages, "lobby_update")
+	if len(updates) != 1 {
+		t.Fatalf("got %d lobby_update events for %d joins, want 1", len(updates), len(names))
+	}
+	if count := updates[0]["player_count"]; count != len(names) {
+		t.Errorf("player_count = %v, want %d", count, len(names))
+	}
+	if states := withEvent(messages, "game_update"); len(states) != 1 {
+		t.Errorf("got %d game_update events over 5 lobby ticks, want 1", len(states))
+	}
+}
+
+func TestHostJoinIsSentRightAway(t *testing.T) {
+	h, game := newTestGame(t, func(cfg *schema.GameConfig) {
+		cfg.LobbyUpdateIntervalMs = 60 * 60 * 1000
+	})
+	game.HostID = "id-host"
+	joinTestPlayers(t, h, game, "guest")
+	if updates := withEvent(published(game), "lobby_update"); len(updates) != 0 {
+		t.Fatalf("guest join sent %d lobby_update events, want it coalesced", len(updates))
+	}
+
+	joinTestPlayers(t, h, game, "host")
+	updates := withEvent(published(game), "lobby_update")
+	if len(updates) != 1 {
+		t.Fatalf("host join sent %d lobby_update events, want 1", len(updates))
+	}
+	if count := updates[0]["player_count"]; count != 2 {
+		t.Errorf("player_count = %v, want 2", count)
+	}
+}
@@ -0,0 +1,75 @@
+package game
+
+import (
+	"encoding/base64"
+)
+
+// mapFormat is the encoding used when sending the map over HTTP
+type mapFormat string
+
+const (
+	mapFormatArray  mapFormat = "array"  // [][]int, one int per cell
+	mapFormatRLE    mapFormat = "rle"    // row-major [color, count] runs
+	mapFormatPacked mapFormat = "packed" // base64, one byte per cell in row-major order
+)
+
+// packedMapThreshold is the cell count above which the packed format is the default
+const packedMapThreshold = 64 * 64
+
+// encodedMap is the envelope for the rle and packed formats
+type encodedMap struct {
+	Width  int      `json:"width"`
+	Height int      `json:"height"`
+	Runs   [][2]int `json:"runs,omitempty"`
+	Data   string   `json:"data,omitempty"`
+}
+
+// parseMapFormat resolves the requested format, falling back to packed for large
+// maps and the plain array otherwise. ok is false for an unknown format.
+func parseMapFormat(requested string, width, height int) (format mapFormat, ok bool) {
+	switch mapFormat(requested) {
+	case mapFormatArray, mapFormatRLE, mapFormatPacked:
+		return mapFormat(requested), true
+	case "":
+		if width*height > packedMapThreshold {
+			return mapFormatPacked, true
+		}
+		return mapFormatArray, true
+	default:
+		return "", false
+	}
+}
+
+// encodeMap encodes the map array in the given format
+func encodeMap(mapArray [][]int, format mapFormat) interface{} {
+	height := len(mapArray)
+	width := 0
+	if height > 0 {
+		width = len(mapArray[0])
+	}
+
+	switch format {
+	case mapFormatRLE:
+		runs := make([][2]int, 0)
+		for _, row := range mapArray {
+			for _, cell := range row {
+				if last := len(runs) - 1; last >= 0 && runs[last][0] == cell {
+					runs[last][1]++
+					continue
+				}
+				runs = append(runs, [2]int{cell, 1})
+			}
+		}
+		return encodedMap{Width: width, Height: height, Runs: runs}
+	case mapFormatPacked:
+		packed := make([]byte, 0, width*height)
+		for _, row := range mapArray {
+			for _, cell := range row {
+				packed = append(packed, byte(cell))
+			}
+		}
+		return encodedMap{Width: width, Height: height, Data: base64.StdEncoding.EncodeToString(packed)}
+	default:
+		return mapArray
+	}
+}
@@ -0,0 +1,98 @@
+package game
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+// decodeMap turns a map encoded as JSON in the given format back into rows
+func decodeMap(t *testing.T, raw json.RawMessage, format mapFormat) [][]int {
+	t.Helper()
+	if format == mapFormatArray {
+		var rows [][]int
+		if err := json.Unmarshal(raw, &rows); err != nil {
+			t.Fatal(err)
+		}
+		return rows
+	}
+
+	var encoded encodedMap
+	if err := json.Unmarshal(raw, &encoded); err != nil {
+		t.Fatal(err)
+	}
+	var cells []int
+	if format == mapFormatRLE {
+		for _, run := range encoded.Runs {
+			for i := 0; i < run[1]; i++ {
+				cells = append(cells, run[0])
+			}
+		}
+	} else {
+		packed, err := base64.StdEncoding.DecodeString(encoded.Data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, b := range packed {
+			cells = append(cells, int(b))
+		}
+	}
+	if len(cells) != encoded.Width*encoded.Height {
+		t.Fatalf("%s: %d cells for %dx%d", format, len(cells), encoded.Width, encoded.Height)
+	}
+	rows := make([][]int, encoded.Height)
+	for y := range rows {
+		rows[y] = cells[y*encoded.Width : (y+1)*encoded.Width]
+	}
+	return rows
+}
+
+func TestMapFormatsDecodeToSameMap(t *testing.T) {
+	h, game := newTestGame(t, nil)
+	h.storeGame(game)
+
+	for _, format := range []mapFormat{mapFormatArray, mapFormatRLE, mapFormatPacked} {
+		rec := serveRoute(h.GetGameState, http.MethodGet, "/api/game/{gameID}", "/api/game/"+game.ID+"?map_format="+string(format), nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d: %s", format, rec.Code, rec.Body)
+		}
+		var body struct {
+			Data struct {
+				Map       json.RawMessage `json:"map"`
+				MapFormat mapFormat       `json:"map_format"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatal(err)
+		}
+		if body.Data.MapFormat != format {
+			t.Errorf("map_format = %q, want %q", body.Data.MapFormat, format)
+		}
+		if got := decodeMap(t, body.Data.Map, format); !reflect.DeepEqual(got, game.MapArray) {
+			t.Errorf("%s does not decode back to the map", format)
+		}
+	}
+}
+
+func TestParseMapFormat(t *testing.T) {
+	tests := []struct {
+		requested     string
+		width, height int
+		want          mapFormat
+		ok            bool
+	}{
+		{"", 20, 20, mapFormatArray, true},
+		{"", 256, 256, mapFormatPacked, true},
+		{"rle", 256, 256, mapFormatRLE, true},
+		{"array", 256, 256, mapFormatArray, true},
+		{"png", 20, 20, "", false},
+	}
+	for _, tt := range tests {
+		format, ok := parseMapFormat(tt.requested, tt.width, tt.height)
+		if format != tt.want || ok != tt.ok {
+			t.Errorf("parseMapFormat(%q, %d, %d) = %q, %v, want %q, %v", tt.requested, tt.width, tt.height, format, ok, tt.want, tt.ok)
+		}
+	}
+}
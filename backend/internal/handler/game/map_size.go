@@ -0,0 +1,43 @@
+package game
+
+import (
+	"log"
+	"math"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+// minAutoMapSide keeps an auto-sized map playable for the smallest lobbies
+const minAutoMapSide = 8
+
+// autoSizeMap fits the playable area to the player count when AutoSizeMap is
+// set, so each player gets about MapCellsPerPlayer blocks inside the border.
+// The size is capped by the map array. It must run before spawns are assigned.
+func (h *GameHandler) autoSizeMap(game *schema.Game) {
+	// A custom map was drawn for the configured size, cropping it would ruin it
+	if !game.Config.AutoSizeMap || game.CustomMap {
+		return
+	}
+
+	width, height := autoMapSize(game.Config, game.PlayerCount, len(game.Map[0]), len(game.Map))
+	log.Printf("Auto sizing map of game %s for %d players: %dx%d -> %dx%d",
+		game.ID, game.PlayerCount, game.Config.MapWidth, game.Config.MapHeight, width, height)
+
+	game.Config.MapWidth = width
+	game.Config.MapHeight = height
+	// The symmetry is about the new center
+	h.symmetrizeMap(game)
+	h.applyBorder(game)
+	h.syncMapArray(game)
+}
+
+// autoMapSize returns the square map size giving playerCount players
+// MapCellsPerPlayer blocks each inside the border, within maxWidth x maxHeight
+func autoMapSize(cfg schema.GameConfig, playerCount, maxWidth, maxHeight int) (width, height int) {
+	border := 2 * cfg.BorderThickness
+	inner := int(math.Ceil(math.Sqrt(float64(playerCount * cfg.MapCellsPerPlayer))))
+
+	side := max(inner+border, minAutoMapSide+border)
+	side = min(side, maxWidth, maxHeight)
+	return side, side
+}
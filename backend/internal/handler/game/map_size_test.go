@@ -0,0 +1,62 @@
+package game
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+func TestAutoSizedMapFitsThePlayers(t *testing.T) {
+	tests := []struct {
+		players, border int
+		wantSide        int
+	}{
+		{players: 4, border: 0, wantSide: 10},
+		{players: 4, border: 1, wantSide: 12},
+		{players: 16, border: 0, wantSide: 20},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d players border %d", tt.players, tt.border), func(t *testing.T) {
+			h, game := newTestGame(t, func(cfg *schema.GameConfig) {
+				cfg.AutoSizeMap = true
+				cfg.MapCellsPerPlayer = 25
+				cfg.BorderThickness = tt.border
+			})
+			names := make([]string, tt.players)
+			for i := range names {
+				names[i] = fmt.Sprintf("player%d", i)
+			}
+			joinTestPlayers(t, h, game, names...)
+
+			h.startGame(game)
+
+			if game.Config.MapWidth != tt.wantSide || game.Config.MapHeight != tt.wantSide {
+				t.Errorf("map is %dx%d, want %dx%d", game.Config.MapWidth, game.Config.MapHeight, tt.wantSide, tt.wantSide)
+			}
+			taken := make(map[[2]int]string)
+			for name, player := range game.Players {
+				x, y := worldToCell(player.Position, 0)
+				if !spawnable(game, x, y) {
+					t.Errorf("%s spawned off the playable map at (%d, %d)", name, x, y)
+				}
+				if other, ok := taken[[2]int{x, y}]; ok {
+					t.Errorf("%s and %s spawned on the same block (%d, %d)", name, other, x, y)
+				}
+				taken[[2]int{x, y}] = name
+			}
+		})
+	}
+}
+
+func TestAutoSizeLeavesCustomMapsAlone(t *testing.T) {
+	h, game := newTestGame(t, func(cfg *schema.GameConfig) { cfg.AutoSizeMap = true })
+	game.CustomMap = true
+	joinTestPlayers(t, h, game, "alice", "bob")
+
+	h.autoSizeMap(game)
+
+	if game.Config.MapWidth != 20 || game.Config.MapHeight != 20 {
+		t.Errorf("custom map resized to %dx%d", game.Config.MapWidth, game.Config.MapHeight)
+	}
+}
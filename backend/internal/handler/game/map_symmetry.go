@@ -0,0 +1,102 @@
+package game
+
+import (
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+// cell is a block on the map by column and row, 0-based
+type cell struct{ x, y int }
+
+// symmetricCells returns the cells the map symmetry makes equivalent to x, y,
+// x, y itself first. Without symmetry that's only x, y.
+func symmetricCells(game *schema.Game, x, y int) []cell {
+	w, h := game.Config.MapWidth, game.Config.MapHeight
+	var candidates []cell
+	switch game.Config.SymmetricMap {
+	case schema.MapSymmetryMirror:
+		candidates = []cell{{x, y}, {w - 1 - x, y}, {x, h - 1 - y}, {w - 1 - x, h - 1 - y}}
+	case schema.MapSymmetryRotational:
+		candidates = []cell{{x, y}, {w - 1 - x, h - 1 - y}}
+	default:
+		return []cell{{x, y}}
+	}
+
+	// Cells on a center line are their own mirror image
+	cells := make([]cell, 0, len(candidates))
+	for _, candidate := range candidates {
+		duplicate := false
+		for _, c := range cells {
+			duplicate = duplicate || c == candidate
+		}
+		if !duplicate {
+			cells = append(cells, candidate)
+		}
+	}
+	return cells
+}
+
+// isCanonicalCell reports whether x, y is the first of its symmetric cells in
+// row order, the one the others copy
+func isCanonicalCell(game *schema.Game, x, y int) bool {
+	for _, c := range symmetricCells(game, x, y) {
+		if c.y < y || (c.y == y && c.x < x) {
+			return false
+		}
+	}
+	return true
+}
+
+// symmetrizeMap copies every canonical cell's color onto its symmetric cells,
+// so the map has the configured symmetry. Without one the map is left alone.
+func (h *GameHandler) symmetrizeMap(game *schema.Game) {
+	if game.Config.SymmetricMap == schema.MapSymmetryNone {
+		return
+	}
+	for y := 0; y < game.Config.MapHeight; y++ {
+		for x := 0; x < game.Config.MapWidth; x++ {
+			if !isCanonicalCell(game, x, y) {
+				continue
+			}
+			color, _ := game.ColorAt(x, y)
+			for _, c := range symmetricCells(game, x, y)[1:] {
+				game.SetColorAt(c.x, c.y, color)
+			}
+		}
+	}
+}
+
+// symmetricSpawnPositions returns the spawnable blocks grouped by symmetry:
+// a random canonical block followed by its symmetric blocks, then the next
+// group. Players spawned in order fill each set of equivalent spots first.
+func (h *GameHandler) symmetricSpawnPositions(game *schema.Game) []schema.Position {
+	canonical := make([]cell, 0)
+	for y := 0; y < game.Config.MapHeight; y++ {
+		for x := 0; x < game.Config.MapWidth; x++ {
+			if isCanonicalCell(game, x, y) {
+				canonical = append(canonical, cell{x, y})
+			}
+		}
+	}
+
+	// Reproducible from the map seed, like the unsymmetric shuffle
+	game.Rand.Shuffle(len(canonical), func(i, j int) {
+		canonical[i], canonical[j] = canonical[j], canonical[i]
+	})
+
+	positions := make([]schema.Position, 0)
+	for _, c := range canonical {
+		// A custom map may not be symmetric, keep only the groups that fully are
+		group := symmetricCells(game, c.x, c.y)
+		complete := true
+		for _, member := range group {
+			complete = complete && spawnable(game, member.x, member.y)
+		}
+		if !complete {
+			continue
+		}
+		for _, member := range group {
+			positions = append(positions, spawnPoint(member.x, member.y))
+		}
+	}
+	return positions
+}
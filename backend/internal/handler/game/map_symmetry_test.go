@@ -0,0 +1,79 @@
+package game
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+// mirrorImages returns the cells that must have the color of x, y under the symmetry
+func mirrorImages(symmetry schema.MapSymmetry, w, h, x, y int) [][2]int {
+	switch symmetry {
+	case schema.MapSymmetryMirror:
+		return [][2]int{{w - 1 - x, y}, {x, h - 1 - y}, {w - 1 - x, h - 1 - y}}
+	case schema.MapSymmetryRotational:
+		return [][2]int{{w - 1 - x, h - 1 - y}}
+	}
+	return nil
+}
+
+// assertSymmetric fails the test if the map lacks the game's symmetry
+func assertSymmetric(t *testing.T, game *schema.Game, when string) {
+	t.Helper()
+	w, h := game.Config.MapWidth, game.Config.MapHeight
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			color, _ := game.ColorAt(x, y)
+			for _, image := range mirrorImages(game.Config.SymmetricMap, w, h, x, y) {
+				if other, _ := game.ColorAt(image[0], image[1]); other != color {
+					t.Fatalf("%s: (%d, %d) is %s but (%d, %d) is %s", when, x, y, color, image[0], image[1], other)
+				}
+			}
+		}
+	}
+}
+
+func TestGeneratedMapsAreSymmetric(t *testing.T) {
+	for _, symmetry := range []schema.MapSymmetry{schema.MapSymmetryMirror, schema.MapSymmetryRotational} {
+		for _, size := range [][2]int{{20, 20}, {15, 13}} {
+			t.Run(fmt.Sprintf("%s %dx%d", symmetry, size[0], size[1]), func(t *testing.T) {
+				h, game := newTestGame(t, func(cfg *schema.GameConfig) {
+					cfg.SymmetricMap = symmetry
+					cfg.MapWidth, cfg.MapHeight = size[0], size[1]
+				})
+				assertSymmetric(t, game, "new game")
+
+				joinTestPlayers(t, h, game, "alice", "bob", "carol", "dave")
+				h.startGame(game)
+				for round := 1; round <= 3; round++ {
+					h.startNewRound(game)
+					assertSymmetric(t, game, fmt.Sprintf("round %d", round))
+					game.CurrentRound = nil
+				}
+			})
+		}
+	}
+}
+
+func TestSymmetricSpawnsAreEquivalent(t *testing.T) {
+	h, game := newTestGame(t, func(cfg *schema.GameConfig) { cfg.SymmetricMap = schema.MapSymmetryMirror })
+	spawns := h.validSpawnPositions(game)
+	if len(spawns) < 4 {
+		t.Fatalf("%d spawns", len(spawns))
+	}
+
+	// The first four spots are one block and its three mirror images
+	x, y := worldToCell(spawns[0], 0)
+	want := map[[2]int]bool{{x, y}: true}
+	for _, image := range mirrorImages(schema.MapSymmetryMirror, game.Config.MapWidth, game.Config.MapHeight, x, y) {
+		want[image] = true
+	}
+	for _, spawn := range spawns[:4] {
+		x, y := worldToCell(spawn, 0)
+		if !want[[2]int{x, y}] {
+			t.Errorf("spawns %v are not mirror images of each other", spawns[:4])
+			break
+		}
+	}
+}
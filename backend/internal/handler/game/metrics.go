@@ -0,0 +1,57 @@
+package game
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// metricFamily is one metric of the Prometheus exposition, with a value per game
+type metricFamily struct {
+	name  string
+	kind  string // gauge or counter
+	help  string
+	value func(diagnosticsResponse) float64
+}
+
+var backpressureMetrics = []metricFamily{
+	{"blind_party_clients", "gauge", "Players connected to the game.",
+		func(d diagnosticsResponse) float64 { return float64(d.Clients) }},
+	{"blind_party_broadcast_queue_depth", "gauge", "Messages published but not yet handed to the clients.",
+		func(d diagnosticsResponse) float64 { return float64(d.BroadcastQueueDepth) }},
+	{"blind_party_send_buffer_depth_average", "gauge", "Mean depth of a client's send buffer when a message was queued on it.",
+		func(d diagnosticsResponse) float64 { return d.AverageSendDepth }},
+	{"blind_party_send_buffer_depth_max", "gauge", "Deepest a client's send buffer was when a message was queued on it.",
+		func(d diagnosticsResponse) float64 { return float64(d.MaxSendDepth) }},
+	{"blind_party_dropped_messages_total", "counter", "Messages that found a client's send buffer full.",
+		func(d diagnosticsResponse) float64 { return float64(d.DroppedMessages) }},
+	{"blind_party_broadcasts_total", "counter", "Broadcasts handed to the clients.",
+		func(d diagnosticsResponse) float64 { return float64(d.Broadcasts) }},
+	{"blind_party_broadcast_latency_seconds_average", "gauge", "Mean time from publishing a broadcast to handing it to the clients.",
+		func(d diagnosticsResponse) float64 { return d.AverageLatencyMs / 1000 }},
+	{"blind_party_broadcast_latency_seconds_max", "gauge", "Longest time from publishing a broadcast to handing it to the clients.",
+		func(d diagnosticsResponse) float64 { return d.MaxLatencyMs / 1000 }},
+}
+
+// Metrics serves the backpressure metrics of every game in memory in the
+// Prometheus text format, labeled by game ID
+func (h *GameHandler) Metrics(w http.ResponseWriter, r *http.Request) {
+	inMemory := h.allGames()
+	games := make([]diagnosticsResponse, 0, len(inMemory))
+	for _, game := range inMemory {
+		games = append(games, gameDiagnostics(game))
+	}
+	sort.Slice(games, func(i, j int) bool { return games[i].GameID < games[j].GameID })
+
+	var b strings.Builder
+	for _, metric := range backpressureMetrics {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", metric.name, metric.help, metric.name, metric.kind)
+		for _, game := range games {
+			fmt.Fprintf(&b, "%s{game_id=%q} %g\n", metric.name, game.GameID, metric.value(game))
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Write([]byte(b.String()))
+}
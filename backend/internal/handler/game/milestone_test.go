@@ -0,0 +1,47 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+func TestBatchEliminationCrossingTwoMilestones(t *testing.T) {
+	h, game := newTestGame(t, func(cfg *schema.GameConfig) {
+		cfg.MilestoneThresholds = []int{5, 3, 2}
+		cfg.BatchEliminationNotices = true
+	})
+	joinTestPlayers(t, h, game, "alice", "bob", "carol", "dave", "erin", "frank")
+	h.startNewRound(game)
+	game.AliveCount = 6
+	published(game)
+
+	// One check takes the alive count from 6 to 3, past 5 and 3
+	for _, name := range []string{"alice", "bob", "carol"} {
+		h.eliminatePlayer(game, game.Players[name], schema.EliminatedWrongColor)
+	}
+	milestones := withEvent(published(game), "milestone")
+	if len(milestones) != 2 {
+		t.Fatalf("got %d milestone events, want 2: %v", len(milestones), milestones)
+	}
+	for i, want := range []int{5, 3} {
+		if milestones[i]["threshold"] != want {
+			t.Errorf("milestone %d threshold = %v, want %d", i, milestones[i]["threshold"], want)
+		}
+	}
+	if milestones[1]["alive_count"] != 3 {
+		t.Errorf("alive_count = %v, want 3", milestones[1]["alive_count"])
+	}
+
+	// Thresholds already reached don't fire again
+	h.eliminatePlayer(game, game.Players["dave"], schema.EliminatedWrongColor)
+	milestones = withEvent(published(game), "milestone")
+	if len(milestones) != 1 || milestones[0]["threshold"] != 2 {
+		t.Errorf("after the fourth elimination got %v, want only threshold 2", milestones)
+	}
+	game.AliveCount = 6
+	h.eliminatePlayer(game, game.Players["erin"], schema.EliminatedWrongColor)
+	if milestones := withEvent(published(game), "milestone"); len(milestones) != 0 {
+		t.Errorf("reached milestones fired again: %v", milestones)
+	}
+}
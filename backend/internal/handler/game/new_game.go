@@ -1,29 +1,250 @@
 package game
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"math/rand"
 	"net/http"
 	"strconv"
 	"time"
 
+	"github.com/yorukot/blind-party/internal/config"
 	"github.com/yorukot/blind-party/internal/schema"
 	"github.com/yorukot/blind-party/pkg/response"
 )
 
+// IdempotencyKeyHeader lets clients retry NewGame without creating duplicate games
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// newGameRequest is the optional body of NewGame
+type newGameRequest struct {
+	IdempotencyKey string `json:"idempotency_key"`
+	UserID         string `json:"user_id"`
+	WebhookURL     string `json:"webhook_url"` // Optional, receives game_started, game_ended and milestone events
+
+	// Config overrides the default game config field by field
+	Config json.RawMessage `json:"config"`
+}
+
+// newGameResponse is the response of NewGame
+type newGameResponse struct {
+	GameID   string `json:"game_id"`
+	MapSeed  int64  `json:"map_seed"`
+	Replayed bool   `json:"replayed,omitempty"`
+}
+
 func (h *GameHandler) NewGame(w http.ResponseWriter, r *http.Request) {
+	// The body is optional, an empty one is fine
+	var req newGameRequest
+	if r.Body != nil && r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.Fail(w, http.StatusBadRequest, "INVALID_REQUEST_BODY", "Request body must be valid JSON")
+			return
+		}
+	}
+
+	gameConfig := defaultGameConfig()
+	if len(req.Config) > 0 {
+		if err := json.Unmarshal(req.Config, &gameConfig); err != nil {
+			response.Fail(w, http.StatusBadRequest, "INVALID_CONFIG", "Config must match the game config format")
+			return
+		}
+	}
+
+	// The map is laid out from its size as the game is built, an impossible
+	// size must be turned away first
+	if fields := mapSizeProblems(gameConfig); len(fields) > 0 {
+		response.FailValidation(w, fields)
+		return
+	}
+
+	// Reject configs that would create an unplayable room
+	game := h.buildGame(gameConfig)
+	game.HostID = req.UserID
+	game.WebhookURL = req.WebhookURL
+	fields := validateGameConfig(game, config.Env().MaxPlayers)
+	if req.WebhookURL != "" {
+		if !validWebhookURL(req.WebhookURL) {
+			fields["webhook_url"] = "must be an absolute http or https URL"
+		} else if err := h.Webhooks.CheckURL(r.Context(), req.WebhookURL); err != nil {
+			fields["webhook_url"] = "must resolve to a public address"
+		}
+	}
+	if len(fields) > 0 {
+		response.FailValidation(w, fields)
+		return
+	}
+
+	launch := func() string {
+		h.launchGame(game)
+		return game.ID
+	}
+
+	idempotencyKey := r.Header.Get(IdempotencyKeyHeader)
+	if idempotencyKey == "" {
+		idempotencyKey = req.IdempotencyKey
+	}
+
+	// Without a key every request creates a new game
+	if idempotencyKey == "" || h.IdempotencyKeys == nil {
+		response.OK(w, newGameResponse{GameID: launch(), MapSeed: game.MapSeed})
+		return
+	}
+
+	// A repeated key returns the game created by the first request, unless
+	// that game was removed meanwhile, e.g. abandoned, and a retry would
+	// otherwise be sent to a game that doesn't exist
+	gameExists := func(gameID string) bool {
+		_, exists := h.getGame(gameID)
+		return exists
+	}
+	gameID, replayed := h.IdempotencyKeys.GetOrSetValid(scopeIdempotencyKey(req.UserID, idempotencyKey), gameExists, launch)
+	resp := newGameResponse{GameID: gameID, Replayed: replayed}
+	if created, exists := h.getGame(gameID); exists {
+		resp.MapSeed = created.MapSeed
+	}
+	response.OK(w, resp)
+}
+
+// scopeIdempotencyKey hashes the key with the creator's user ID so different
+// creators that happen to send the same key don't collide
+func scopeIdempotencyKey(userID, key string) string {
+	sum := sha256.Sum256([]byte(userID + ":" + key))
+	return hex.EncodeToString(sum[:])
+}
+
+// defaultGameConfig returns the config games are created with unless overridden
+func defaultGameConfig() schema.GameConfig {
+	return schema.GameConfig{
+		MapWidth:            20,
+		MapHeight:           20,
+		AutoSizeMap:         false,
+		MapCellsPerPlayer:   25,
+		SpectatorOnlyRounds: 2,
+		BorderThickness:     0,
+		LateJoinRounds:      3,
+		MaxSpectators:       20,
+		RevivesPerPlayer:    0,
+		Lives:               1,
+		UniqueAvatars:       false,
+		UniqueNames:         false,
+		InitialSafeColors:   1,
+		SafeColorDecay:      3,
+
+		// Spawn placement
+		SpawnMode: schema.SpawnRandom,
+
+		// Spawn diversity
+		GuaranteeSpawnDiversity: false,
+		SpawnDiversityColors:    4,
+		SpawnDiversityRadius:    1,
+
+		// Scarcity ramp
+		ScarcityRampStartRound: 0,
+		ScarcityRampRounds:     5,
+
+		// Party mode
+		ForceEliminationEachRound: false,
+
+		// Elimination cap
+		MaxEliminationsPerRound: 0,
+		EliminationCapMode:      schema.EliminationCapSlowest,
+
+		// Revenge of the eliminated
+		SpectatorHazards:    false,
+		HazardsPerSpectator: 3,
+		HazardCooldownMs:    2000,
+
+		// Hard cap on a game's length
+		MaxGameDurationSeconds: 0,
+
+		// Breather between two rounds
+		RoundBreatherSeconds: 2,
+
+		// Notices to eliminated players
+		BatchEliminationNotices: true,
+
+		// Lobby auto-start
+		AutoStartSeconds:       config.Env().AutoStartSeconds,
+		AutoStartCapacityRatio: config.Env().AutoStartCapacityRatio,
+		LobbyUpdateIntervalMs:  250,
+		FirstRoundGraceSeconds: 3,
+		WarmupRounds:           0,
+		PreGameTimeoutSeconds:  600,
+
+		// Scoring Configuration
+		SurvivalPointsPerRound:     10,
+		LateRoundThreshold:         10,
+		LateRoundScoreMultiplier:   2.0,
+		EliminationBonusMultiplier: 5,
+		EliminationBonusFormula:    schema.BonusLinear,
+		FinalWinnerBonus:           100,
+		CatchupBonus:               0,
+
+		// Movement & Anti-cheat
+		BaseMovementSpeed: 4.0,
+		MaxMovementSpeed:  5.0,
+		LagCompensationMs: 50,
+		PositionUpdateHz:  10,
+		TimerUpdateHz:     20,
+		CellEpsilon:       1e-6,
+
+		LockDuringColorCall: false,
+		MaxPhaseSeconds:     30,
+		EliminationSnapshot: true,
+
+		AntiCheatMode:       schema.AntiCheatReset,
+		FreezeDurationMs:    1000,
+		KickAfterViolations: 5,
+
+		// Spectacle
+		MilestoneThresholds: []int{10, 5, 3, 2},
+
+		// Connection quality
+		PingIntervalMs:       2000,
+		StalenessThresholdMs: 250,
+		MaxLagCompensationMs: 300,
+		ClientSendBuffer:     256,
+
+		// Photo finish
+		FinalTwoSlowMo: 0,
+
+		// Hard mode
+		HardMode: false,
+
+		// Active play
+		DistancePointsPer:      0,
+		DistancePointsRoundCap: 5,
+
+		// Coaching
+		SafetyHints:          false,
+		SafetyHintIntervalMs: 500,
+		ShowColorOdds:        false,
+	}
+}
+
+// buildGame creates a game instance with a fresh ID and map, without starting it
+func (h *GameHandler) buildGame(gameConfig schema.GameConfig) *schema.Game {
 	// Generate a new 6-digit game ID
 	var gameID string
 	for {
 		// Generate random number between 100000 and 999999
-		randomNum := rand.Intn(900000) + 100000
+		randomNum := h.randIntn(900000) + 100000
 		gameID = strconv.Itoa(randomNum)
 
 		// Check if the game ID already exists
-		if _, exists := h.GameData[gameID]; !exists {
+		if _, exists := h.getGame(gameID); !exists {
 			break
 		}
 	}
 
+	// Resolve the seed so the map can be reproduced
+	seed := gameConfig.MapSeed
+	if seed == 0 {
+		seed = h.newMapSeed()
+	}
+
 	// Create a new game instance
 	now := time.Now()
 	game := &schema.Game{
@@ -37,9 +258,15 @@ func (h *GameHandler) NewGame(w http.ResponseWriter, r *http.Request) {
 		PlayerCount: 0,
 		AliveCount:  0,
 
+		Eliminations:      make([]schema.EliminationRecord, 0),
+		MilestonesReached: make(map[int]bool),
+
+		PlayerPositionHistory: make(map[string]*schema.PositionHistory),
+
 		// WebSocket management
 		Clients:    make(map[string]*schema.WebSocketClient),
-		Broadcast:  make(chan interface{}, 256),
+		Observers:  make(map[*schema.WebSocketClient]bool),
+		Broadcast:  make(chan schema.QueuedMessage, 256),
 		Register:   make(chan *schema.WebSocketClient, 256),
 		Unregister: make(chan *schema.WebSocketClient, 256),
 
@@ -48,55 +275,55 @@ func (h *GameHandler) NewGame(w http.ResponseWriter, r *http.Request) {
 		RoundNumber:  0,
 
 		// Configuration
-		Config: schema.GameConfig{
-			MapWidth:            20,
-			MapHeight:           20,
-			CountdownSequence:   []int{30, 25, 20, 15, 10, 8, 6, 4, 3, 2},
-			SpectatorOnlyRounds: 2,
-
-			// Timing Progression (rush phase duration by round ranges)
-			TimingProgression: []schema.TimingRange{
-				{StartRound: 1, EndRound: 3, Duration: 4.0},
-				{StartRound: 4, EndRound: 6, Duration: 3.5},
-				{StartRound: 7, EndRound: 9, Duration: 3.0},
-				{StartRound: 10, EndRound: 12, Duration: 2.5},
-				{StartRound: 13, EndRound: 15, Duration: 2.0},
-			},
-
-			// Movement & Anti-cheat
-			BaseMovementSpeed: 4.0,
-			MaxMovementSpeed:  5.0,
-			LagCompensationMs: 50,
-			PositionUpdateHz:  10,
-			TimerUpdateHz:     20,
-		},
+		Config: gameConfig,
 
 		// Generate random map data
-		Map: generateRandomMap(),
+		Map:     generateRandomMap(seed),
+		MapSeed: seed,
+		Rand:    rand.New(rand.NewSource(seed)),
 
 		// Synchronization
 		StopTicker: make(chan bool),
+		Ready:      make(chan struct{}),
+		Done:       make(chan struct{}),
 	}
 
-	// Convert map to array for JSON serialization
-	game.MapArray = mapToArray(game.Map)
+	// Wall off the border before syncing the map array for JSON serialization
+	h.symmetrizeMap(game)
+	h.applyBorder(game)
+	h.syncMapArray(game)
+
+	return game
+}
+
+// launchGame stores the game in memory and starts its lifecycle
+func (h *GameHandler) launchGame(game *schema.Game) {
+	// Start recording the replay with the initial map and config
+	if config.Env().ReplayEnabled {
+		h.startReplay(game, config.Env().ReplayDir)
+	}
 
-	// Store the game in GameData map
-	h.GameData[gameID] = game
+	// Store the game in memory
+	h.storeGame(game)
 
 	// Start the game lifecycle in a separate goroutine
 	go h.GameLifeCycle(game)
+}
 
-	// Respond with the game ID
-	response.RespondWithData(
-		w,
-		map[string]string{"game_id": gameID},
-	)
+// newMapSeed picks a random non-zero seed, zero meaning "pick one"
+func (h *GameHandler) newMapSeed() int64 {
+	for {
+		if seed := h.randInt63(); seed != 0 {
+			return seed
+		}
+	}
 }
 
 // generateRandomMap creates a 20x20 map with equal distribution of 16 wool colors
-func generateRandomMap() schema.MapData {
+// from the seed, so the same seed always gives the same map
+func generateRandomMap(seed int64) schema.MapData {
 	var mapData schema.MapData
+	rng := rand.New(rand.NewSource(seed))
 
 	// Create a list of all possible positions
 	positions := make([]struct{ x, y int }, 0, 400) // 20*20 = 400 total blocks
@@ -107,7 +334,7 @@ func generateRandomMap() schema.MapData {
 	}
 
 	// Shuffle positions for random distribution
-	rand.Shuffle(len(positions), func(i, j int) {
+	rng.Shuffle(len(positions), func(i, j int) {
 		positions[i], positions[j] = positions[j], positions[i]
 	})
 
@@ -126,15 +353,3 @@ func generateRandomMap() schema.MapData {
 
 	return mapData
 }
-
-// mapToArray converts the 2D map array to a format suitable for JSON serialization
-func mapToArray(mapData schema.MapData) [][]int {
-	result := make([][]int, 20) // height = 20
-	for i := 0; i < 20; i++ {
-		result[i] = make([]int, 20) // width = 20
-		for j := 0; j < 20; j++ {
-			result[i][j] = int(mapData[i][j])
-		}
-	}
-	return result
-}
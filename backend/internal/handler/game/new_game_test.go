@@ -0,0 +1,271 @@
+package game
+
+import (
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yorukot/blind-party/internal/schema"
+	"github.com/yorukot/blind-party/pkg/ttlcache"
+)
+
+// newGameHandler returns a handler remembering idempotency keys for ttl,
+// stopping every game it creates when the test ends
+func newGameHandler(t *testing.T, ttl time.Duration) *GameHandler {
+	t.Helper()
+	h := &GameHandler{IdempotencyKeys: ttlcache.New(16, ttl)}
+	t.Cleanup(func() {
+		for _, game := range h.allGames() {
+			game.Stop()
+			<-game.Done
+		}
+	})
+	return h
+}
+
+// createGame posts to NewGame with the Idempotency-Key header and body
+func createGame(t *testing.T, h *GameHandler, key string, body map[string]any) newGameResponse {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/api/game", jsonBody(t, body))
+	req.Header.Set(IdempotencyKeyHeader, key)
+	rec := httptest.NewRecorder()
+	h.NewGame(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	var resp struct {
+		Data newGameResponse `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	return resp.Data
+}
+
+func TestRepeatedIdempotencyKeyReturnsSameGame(t *testing.T) {
+	h := newGameHandler(t, time.Minute)
+
+	first := createGame(t, h, "retry-1", map[string]any{"user_id": "alice"})
+	if first.Replayed {
+		t.Error("first request reported as replayed")
+	}
+	again := createGame(t, h, "retry-1", map[string]any{"user_id": "alice"})
+	if again.GameID != first.GameID || !again.Replayed || again.MapSeed != first.MapSeed {
+		t.Errorf("retry got %+v, want %+v replayed", again, first)
+	}
+
+	// The key is scoped to its creator
+	other := createGame(t, h, "retry-1", map[string]any{"user_id": "bob"})
+	if other.GameID == first.GameID || other.Replayed {
+		t.Errorf("another user's request got %+v", other)
+	}
+	if n := len(h.allGames()); n != 2 {
+		t.Errorf("%d games created, want 2", n)
+	}
+}
+
+func TestIdempotencyKeyInBodyOrMissing(t *testing.T) {
+	h := newGameHandler(t, time.Minute)
+
+	// The key may come in the body instead of the header
+	first := createGame(t, h, "", map[string]any{"user_id": "alice", "idempotency_key": "retry-1"})
+	again := createGame(t, h, "retry-1", map[string]any{"user_id": "alice"})
+	if again.GameID != first.GameID || !again.Replayed {
+		t.Errorf("header retry of a body key got %+v, want %s replayed", again, first.GameID)
+	}
+
+	// Without one every request is a new game
+	a := createGame(t, h, "", map[string]any{"user_id": "alice"})
+	b := createGame(t, h, "", map[string]any{"user_id": "alice"})
+	if a.GameID == b.GameID || a.Replayed || b.Replayed {
+		t.Errorf("keyless requests got %+v and %+v, want two new games", a, b)
+	}
+}
+
+func TestIdempotencyKeyExpires(t *testing.T) {
+	h := newGameHandler(t, 20*time.Millisecond)
+
+	first := createGame(t, h, "retry-1", map[string]any{"user_id": "alice"})
+	time.Sleep(40 * time.Millisecond)
+	again := createGame(t, h, "retry-1", map[string]any{"user_id": "alice"})
+	if again.GameID == first.GameID || again.Replayed {
+		t.Errorf("request after expiry got %+v, want a new game", again)
+	}
+}
+
+func TestIdempotencyKeyOfRemovedGameCreatesNewOne(t *testing.T) {
+	h := newGameHandler(t, time.Minute)
+
+	first := createGame(t, h, "retry-1", map[string]any{"user_id": "alice"})
+	game, _ := h.getGame(first.GameID)
+	game.Stop()
+	<-game.Done
+	h.removeGame(first.GameID)
+
+	again := createGame(t, h, "retry-1", map[string]any{"user_id": "alice"})
+	if again.GameID == first.GameID || again.Replayed {
+		t.Errorf("retry for a removed game got %+v, want a new game", again)
+	}
+}
+
+func TestConcurrentDuplicatesCreateOneGame(t *testing.T) {
+	h := newGameHandler(t, time.Minute)
+
+	const requests = 16
+	responses := make([]newGameResponse, requests)
+	var wg sync.WaitGroup
+	for i := range responses {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			responses[i] = createGame(t, h, "retry-1", map[string]any{"user_id": "alice"})
+		}()
+	}
+	wg.Wait()
+
+	created := 0
+	for _, resp := range responses {
+		if resp.GameID != responses[0].GameID {
+			t.Errorf("got game %s and %s", resp.GameID, responses[0].GameID)
+		}
+		if !resp.Replayed {
+			created++
+		}
+	}
+	if created != 1 || len(h.allGames()) != 1 {
+		t.Errorf("%d responses created a game, %d games exist, want 1", created, len(h.allGames()))
+	}
+}
+
+func TestNewGameRejectsMapTooSmallToSpawnEveryone(t *testing.T) {
+	h := newGameHandler(t, time.Minute)
+
+	post := func(cfg map[string]any) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.NewGame(rec, httptest.NewRequest(http.MethodPost, "/api/game", jsonBody(t, map[string]any{"config": cfg})))
+		return rec
+	}
+
+	// 15 blocks for 16 players
+	rec := post(map[string]any{"map_width": 5, "map_height": 3})
+	var body struct {
+		Error struct {
+			Code   string            `json:"code"`
+			Fields map[string]string `json:"fields"`
+		} `json:"error"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &body)
+	if rec.Code != http.StatusBadRequest || body.Error.Code != "VALIDATION_FAILED" || body.Error.Fields["map"] == "" {
+		t.Errorf("5x3 map: got %d %s, want a validation error on map", rec.Code, rec.Body)
+	}
+	if len(h.allGames()) != 0 {
+		t.Error("a game was created from the rejected config")
+	}
+
+	if rec := post(map[string]any{"map_width": 4, "map_height": 4}); rec.Code != http.StatusOK {
+		t.Errorf("4x4 map: got %d %s", rec.Code, rec.Body)
+	}
+}
+
+func TestReturnedSeedRegeneratesTheMap(t *testing.T) {
+	h := newGameHandler(t, time.Minute)
+	created := createGame(t, h, "", nil)
+	if created.MapSeed == 0 {
+		t.Fatal("NewGame returned no map seed")
+	}
+
+	game, _ := h.getGame(created.GameID)
+	game.Mu.RLock()
+	defer game.Mu.RUnlock()
+	if game.MapSeed != created.MapSeed {
+		t.Errorf("game seed %d, returned %d", game.MapSeed, created.MapSeed)
+	}
+	if generateRandomMap(created.MapSeed) != game.Map {
+		t.Error("the returned seed generates a different map")
+	}
+}
+
+// playSeeded builds a game from a handler with a fixed source, starts it and
+// plays three rounds, returning everything the source decided
+func playSeeded(t *testing.T, seed int64) (id string, mapSeed int64, maps []schema.MapData, colors []schema.WoolColor, spawns map[string]schema.Position) {
+	t.Helper()
+	h := &GameHandler{Rand: rand.New(rand.NewSource(seed))}
+	game := h.buildGame(defaultGameConfig())
+	joinTestPlayers(t, h, game, "alice", "bob", "carol", "dave")
+	h.startGame(game)
+
+	spawns = make(map[string]schema.Position)
+	for name, player := range game.Players {
+		spawns[name] = player.Position
+	}
+	maps = append(maps, game.Map)
+	for round := 0; round < 3; round++ {
+		h.startNewRound(game)
+		maps = append(maps, game.Map)
+		colors = append(colors, game.CurrentRound.ColorsToShow...)
+		game.CurrentRound = nil
+	}
+	return game.ID, game.MapSeed, maps, colors, spawns
+}
+
+func TestFixedSourceIsDeterministic(t *testing.T) {
+	id, mapSeed, maps, colors, spawns := playSeeded(t, 7)
+	id2, mapSeed2, maps2, colors2, spawns2 := playSeeded(t, 7)
+
+	if id != id2 || mapSeed != mapSeed2 {
+		t.Errorf("game %s seed %d, then game %s seed %d", id, mapSeed, id2, mapSeed2)
+	}
+	if !reflect.DeepEqual(maps, maps2) {
+		t.Error("the maps differ")
+	}
+	if !reflect.DeepEqual(colors, colors2) {
+		t.Errorf("called %v, then %v", colors, colors2)
+	}
+	if !reflect.DeepEqual(spawns, spawns2) {
+		t.Errorf("spawned at %v, then %v", spawns, spawns2)
+	}
+
+	if other, otherSeed, _, _, _ := playSeeded(t, 8); other == id && otherSeed == mapSeed {
+		t.Error("another source gave the same game")
+	}
+}
+
+func TestNewGameRejectsImpossibleMapSizes(t *testing.T) {
+	tests := []struct {
+		name  string
+		cfg   map[string]any
+		field string
+	}{
+		{name: "negative height", cfg: map[string]any{"map_height": -1}, field: "map_height"},
+		{name: "negative width", cfg: map[string]any{"map_width": -1}, field: "map_width"},
+		{name: "zero width", cfg: map[string]any{"map_width": 0}, field: "map_width"},
+		{name: "too tall", cfg: map[string]any{"map_height": 1000}, field: "map_height"},
+		{name: "negative with symmetry and border", cfg: map[string]any{"map_width": -5, "symmetric_map": "mirror", "border_thickness": 1}, field: "map_width"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newGameHandler(t, time.Minute)
+			rec := httptest.NewRecorder()
+			h.NewGame(rec, httptest.NewRequest(http.MethodPost, "/api/game", jsonBody(t, map[string]any{"config": tt.cfg})))
+
+			var body struct {
+				Error struct {
+					Code   string            `json:"code"`
+					Fields map[string]string `json:"fields"`
+				} `json:"error"`
+			}
+			json.Unmarshal(rec.Body.Bytes(), &body)
+			if rec.Code != http.StatusBadRequest || body.Error.Code != "VALIDATION_FAILED" || body.Error.Fields[tt.field] == "" {
+				t.Errorf("got %d %s, want a validation error on %s", rec.Code, rec.Body, tt.field)
+			}
+			if len(h.allGames()) != 0 {
+				t.Error("a game was created from the rejected config")
+			}
+		})
+	}
+}
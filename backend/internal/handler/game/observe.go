@@ -0,0 +1,141 @@
+package game
+
+import (
+	"crypto/subtle"
+	"log"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"golang.org/x/net/websocket"
+
+	"github.com/yorukot/blind-party/internal/config"
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+// ObserveWebSocket streams a game read-only, e.g. for a broadcast overlay.
+// Observers get the initial state and every broadcast but aren't players: they
+// don't show up in Players or the counts, and anything they send except a
+// keepalive ping is rejected. When OBSERVER_KEY is set it must be passed as ?key=.
+func (h *GameHandler) ObserveWebSocket(ws *websocket.Conn) {
+	defer ws.Close()
+	ws.MaxPayloadBytes = maxClientMessageBytes
+
+	req := ws.Request()
+	gameID := chi.URLParam(req, "gameID")
+	if gameID == "" {
+		sendCloseMessage(ws, closeMissingGameID)
+		return
+	}
+
+	key := config.Env().ObserverKey
+	if key != "" && subtle.ConstantTimeCompare([]byte(req.URL.Query().Get("key")), []byte(key)) != 1 {
+		log.Printf("Observer from %s rejected for game %s: invalid key", req.RemoteAddr, gameID)
+		sendCloseMessage(ws, closeBadObserverKey)
+		return
+	}
+
+	game, exists := h.getGame(gameID)
+	if !exists {
+		sendCloseMessage(ws, closeGameNotFound)
+		return
+	}
+
+	client := &schema.WebSocketClient{
+		Conn:      ws,
+		Username:  "observer",
+		Gzip:      req.URL.Query().Get("compress") == "gzip",
+		Send:      make(chan interface{}, game.Config.ClientSendBuffer),
+		Connected: time.Now(),
+	}
+
+	game.Mu.Lock()
+	game.Observers[client] = true
+	h.sendInitialState(game, client)
+	log.Printf("Observer from %s watching game %s (%d observers)", req.RemoteAddr, game.ID, len(game.Observers))
+	game.Mu.Unlock()
+
+	defer h.removeObserver(game, client)
+
+	go func() {
+		defer ws.Close()
+		for message := range client.Send {
+			if err := sendWithDeadline(ws, message); err != nil {
+				log.Printf("Error sending message to observer of game %s: %v", game.ID, err)
+				return
+			}
+		}
+
+		if client.CloseReason != "" {
+			sendCloseMessage(ws, closeReason{Code: client.CloseCode, Reason: client.CloseReason})
+		}
+	}()
+
+	for {
+		var message map[string]interface{}
+		if err := receiveWithDeadline(ws, &message, h.readTimeout()); err != nil {
+			if isTimeout(err) {
+				sendCloseMessage(ws, closeIdleTimeout)
+			}
+			return
+		}
+
+		switch message["event"] {
+		case "ping":
+			sendToObserver(game, client, map[string]interface{}{
+				"event": "pong",
+			})
+		case "pong":
+			// Nothing to measure for observers
+		default:
+			sendToObserver(game, client, map[string]interface{}{
+				"event": "input_rejected",
+				"data": map[string]interface{}{
+					"reason": "observer_read_only",
+					"event":  message["event"],
+				},
+			})
+		}
+	}
+}
+
+// sendToObserver delivers a private message to an observer that is still
+// registered, its send channel is closed once it's removed
+func sendToObserver(game *schema.Game, client *schema.WebSocketClient, message interface{}) {
+	game.Mu.Lock()
+	defer game.Mu.Unlock()
+
+	if !game.Observers[client] {
+		return
+	}
+	select {
+	case client.Send <- message:
+	default:
+		game.SendBufferFull++
+	}
+}
+
+// removeObserver unregisters an observer that disconnected on its own
+func (h *GameHandler) removeObserver(game *schema.Game, client *schema.WebSocketClient) {
+	game.Mu.Lock()
+	defer game.Mu.Unlock()
+
+	if game.Observers[client] {
+		delete(game.Observers, client)
+		close(client.Send)
+	}
+}
+
+// closeObservers disconnects every observer of the game with the reason, after
+// delivering a final message if there is one. Callers must hold game.Mu.
+func closeObservers(game *schema.Game, message interface{}, reason closeReason) {
+	for client := range game.Observers {
+		if message != nil {
+			select {
+			case client.Send <- message:
+			default:
+			}
+		}
+		closeClient(client, reason)
+		delete(game.Observers, client)
+	}
+}
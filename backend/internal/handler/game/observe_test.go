@@ -0,0 +1,65 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/net/websocket"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+// receiveUpdateWith reads until a game_update carrying key arrives and returns its data
+func receiveUpdateWith(t *testing.T, conn *websocket.Conn, key string) map[string]interface{} {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		var message map[string]interface{}
+		if err := websocket.JSON.Receive(conn, &message); err != nil {
+			t.Fatalf("waiting for a game_update with %s: %v", key, err)
+		}
+		data, _ := message["data"].(map[string]interface{})
+		if _, ok := data[key]; ok && message["event"] == "game_update" {
+			return data
+		}
+	}
+}
+
+func TestObserverWatchesButCannotPlay(t *testing.T) {
+	h, game := newTestGame(t, func(cfg *schema.GameConfig) { cfg.FirstRoundGraceSeconds = 60 })
+	joinTestPlayers(t, h, game, "alice", "bob", "carol")
+	server := serveGames(t, h)
+	runGame(t, h, game)
+
+	observer := dialGame(t, server, game.ID, "/observe")
+	receiveUntil(t, observer, "game_update")
+
+	// Play a round in which carol is eliminated, between lifecycle ticks
+	game.Mu.Lock()
+	h.initializeAllPlayerStats(game)
+	game.Phase = schema.InGame
+	judgeRound(t, h, game, "carol")
+	game.Mu.Unlock()
+
+	if round := receiveUpdateWith(t, observer, "hazards"); round["round_number"] != 1.0 {
+		t.Errorf("observer got round %v, want 1", round["round_number"])
+	}
+	eliminations := receiveUpdateWith(t, observer, "eliminations")["eliminations"].([]interface{})
+	if len(eliminations) != 1 || eliminations[0].(map[string]interface{})["user_id"] != "carol" {
+		t.Errorf("observer got eliminations %v, want carol", eliminations)
+	}
+
+	if err := websocket.JSON.Send(observer, playerUpdate(5, 5, 1)); err != nil {
+		t.Fatal(err)
+	}
+	if rejected := receiveUntil(t, observer, "input_rejected"); rejected["reason"] != "observer_read_only" {
+		t.Errorf("input_rejected = %v", rejected)
+	}
+
+	game.Mu.RLock()
+	defer game.Mu.RUnlock()
+	if len(game.Players) != 3 || game.PlayerCount != 3 || game.SpectatorCount != 0 || len(game.Observers) != 1 {
+		t.Errorf("%d players (%d counted, %d spectators) and %d observers, want 3 players and the observer apart",
+			len(game.Players), game.PlayerCount, game.SpectatorCount, len(game.Observers))
+	}
+}
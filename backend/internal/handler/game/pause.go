@@ -0,0 +1,128 @@
+package game
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/yorukot/blind-party/internal/schema"
+	"github.com/yorukot/blind-party/pkg/response"
+)
+
+// pauseResponse is the response of PauseGame and ResumeGame
+type pauseResponse struct {
+	GameID   string     `json:"game_id"`
+	PausedAt *time.Time `json:"paused_at,omitempty"`
+	PausedMs int64      `json:"paused_ms,omitempty"` // How long the game was paused, on resume
+}
+
+// PauseGame freezes a game for admins: the lifecycle stops progressing phases
+// and movement is rejected until the game is resumed
+func (h *GameHandler) PauseGame(w http.ResponseWriter, r *http.Request) {
+	game, ok := h.pausableGame(w, r)
+	if !ok {
+		return
+	}
+	defer game.Mu.Unlock()
+
+	if game.PausedAt != nil {
+		response.Fail(w, http.StatusConflict, "GAME_ALREADY_PAUSED", "The game is already paused")
+		return
+	}
+
+	now := time.Now()
+	game.PausedAt = &now
+	log.Printf("Game %s paused in phase %s", game.ID, game.Phase)
+
+	game.Publish(map[string]interface{}{
+		"event": "game_paused",
+		"data": map[string]interface{}{
+			"game_id":           game.ID,
+			"paused_at":         now,
+			"countdown_seconds": countdownSnapshot(game),
+		},
+	})
+
+	response.OK(w, pauseResponse{GameID: game.ID, PausedAt: &now})
+}
+
+// ResumeGame picks a paused game back up with the time left it had when paused
+func (h *GameHandler) ResumeGame(w http.ResponseWriter, r *http.Request) {
+	game, ok := h.pausableGame(w, r)
+	if !ok {
+		return
+	}
+	defer game.Mu.Unlock()
+
+	if game.PausedAt == nil {
+		response.Fail(w, http.StatusConflict, "GAME_NOT_PAUSED", "The game is not paused")
+		return
+	}
+
+	paused := time.Since(*game.PausedAt)
+	shiftGameClock(game, paused)
+	game.PausedFor += paused
+	game.PausedAt = nil
+	log.Printf("Game %s resumed after %s", game.ID, paused)
+
+	game.Publish(map[string]interface{}{
+		"event": "game_resumed",
+		"data": map[string]interface{}{
+			"game_id":           game.ID,
+			"paused_ms":         paused.Milliseconds(),
+			"countdown_seconds": countdownSnapshot(game),
+		},
+	})
+
+	response.OK(w, pauseResponse{GameID: game.ID, PausedMs: paused.Milliseconds()})
+}
+
+// pausableGame looks up the game of the request and locks it. It writes the
+// error response and returns false if the game is missing or already over.
+func (h *GameHandler) pausableGame(w http.ResponseWriter, r *http.Request) (*schema.Game, bool) {
+	gameID := chi.URLParam(r, "gameID")
+	if gameID == "" {
+		response.Fail(w, http.StatusBadRequest, "MISSING_GAME_ID", "Game ID is required")
+		return nil, false
+	}
+
+	game, exists := h.getGame(gameID)
+	if !exists {
+		response.Fail(w, http.StatusNotFound, "GAME_NOT_FOUND", "Game not found")
+		return nil, false
+	}
+
+	game.Mu.Lock()
+	if game.Phase == schema.Settlement {
+		game.Mu.Unlock()
+		response.Fail(w, http.StatusConflict, "GAME_ENDED", "The game has already ended")
+		return nil, false
+	}
+	return game, true
+}
+
+// shiftGameClock moves every timestamp phase timing is measured from forward by
+// the time spent paused, so no countdown or phase is shortened by the pause
+func shiftGameClock(game *schema.Game, paused time.Duration) {
+	game.LastTick = game.LastTick.Add(paused)
+	if round := game.CurrentRound; round != nil {
+		round.StartTime = round.StartTime.Add(paused)
+		round.PhaseStartedAt = round.PhaseStartedAt.Add(paused)
+	}
+
+	// Movement allowance is measured from the last move, don't let the pause count
+	for _, history := range game.PlayerPositionHistory {
+		history.Shift(paused)
+	}
+	for _, player := range game.Players {
+		player.LastMoveTime = player.LastMoveTime.Add(paused)
+		if !player.SettledAt.IsZero() {
+			player.SettledAt = player.SettledAt.Add(paused)
+		}
+		if !player.FrozenUntil.IsZero() {
+			player.FrozenUntil = player.FrozenUntil.Add(paused)
+		}
+	}
+}
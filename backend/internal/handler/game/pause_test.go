@@ -0,0 +1,69 @@
+package game
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// postPause posts to the game's pause or resume endpoint
+func postPause(t *testing.T, h *GameHandler, gameID, action string) *httptest.ResponseRecorder {
+	t.Helper()
+	handler := h.PauseGame
+	if action == "resume" {
+		handler = h.ResumeGame
+	}
+	return serveRoute(handler, http.MethodPost, "/api/game/{gameID}/"+action, "/api/game/"+gameID+"/"+action, nil)
+}
+
+func TestPausedGameKeepsItsRemainingTime(t *testing.T) {
+	h, game, _ := startTestGame(t, nil, "alice", "bob")
+	h.startNewRound(game)
+	h.storeGame(game)
+
+	countdown := 5.0
+	game.Countdown = &countdown
+	game.LastTick = time.Now()
+	round := game.CurrentRound
+	phase, phaseStarted := round.Phase, round.PhaseStartedAt
+
+	if rec := postPause(t, h, game.ID, "pause"); rec.Code != http.StatusOK {
+		t.Fatalf("pause: got %d %s", rec.Code, rec.Body)
+	}
+	if rec := postPause(t, h, game.ID, "pause"); rec.Code != http.StatusConflict {
+		t.Errorf("second pause: got %d, want %d", rec.Code, http.StatusConflict)
+	}
+
+	// Pretend the pause has lasted ten seconds, longer than the countdown
+	pause := 10 * time.Second
+	*game.PausedAt = game.PausedAt.Add(-pause)
+	game.LastTick = game.LastTick.Add(-pause)
+	round.PhaseStartedAt = round.PhaseStartedAt.Add(-pause)
+	phaseStarted = phaseStarted.Add(-pause)
+
+	for i := 0; i < 3; i++ {
+		h.processGameState(game)
+	}
+	if game.CurrentRound != round || round.Phase != phase || *game.Countdown != countdown {
+		t.Fatalf("paused game moved on: round %d in %s with %.2fs left", game.CurrentRound.Number, game.CurrentRound.Phase, *game.Countdown)
+	}
+
+	if rec := postPause(t, h, game.ID, "resume"); rec.Code != http.StatusOK {
+		t.Fatalf("resume: got %d %s", rec.Code, rec.Body)
+	}
+	if game.PausedAt != nil {
+		t.Error("game is still paused after resuming")
+	}
+	if shift := round.PhaseStartedAt.Sub(phaseStarted); shift < pause {
+		t.Errorf("phase start moved by %s, want at least %s", shift, pause)
+	}
+
+	h.processGameState(game)
+	if game.CurrentRound != round || *game.Countdown < countdown-1 {
+		t.Errorf("resumed with %.2fs left in round %d, want about %.0fs", *game.Countdown, game.CurrentRound.Number, countdown)
+	}
+	if rec := postPause(t, h, game.ID, "resume"); rec.Code != http.StatusConflict {
+		t.Errorf("resuming a running game: got %d, want %d", rec.Code, http.StatusConflict)
+	}
+}
@@ -0,0 +1,50 @@
+package game
+
+import (
+	"math"
+	"time"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+// positionHistorySize is how many positions are kept per player: enough to
+// span the longest lag compensation window at the position update rate, plus
+// the samples on either side of it
+func positionHistorySize(cfg schema.GameConfig) int {
+	window := max(cfg.LagCompensationMs, cfg.MaxLagCompensationMs)
+	hz := max(cfg.PositionUpdateHz, 1)
+	return int(math.Ceil(float64(window)/1000*float64(hz))) + 2
+}
+
+// positionHistory returns the player's position history, creating it on first use
+func positionHistory(game *schema.Game, name string) *schema.PositionHistory {
+	history, exists := game.PlayerPositionHistory[name]
+	if !exists {
+		history = schema.NewPositionHistory(positionHistorySize(game.Config))
+		game.PlayerPositionHistory[name] = history
+	}
+	return history
+}
+
+// positionAt returns where the player was at t according to their history,
+// falling back to their current position when the history doesn't reach back
+func positionAt(game *schema.Game, player *schema.Player, t time.Time) schema.Position {
+	if position, ok := positionHistory(game, player.Name).At(t); ok {
+		return position
+	}
+	return player.Position
+}
+
+// exceedsWindowSpeed reports whether the move to newPosition is too fast when
+// measured from the oldest position kept. Each update gets its own lag
+// allowance, so a teleport spread over several updates can pass them one by
+// one but not over the whole window.
+func exceedsWindowSpeed(game *schema.Game, player *schema.Player, newPosition schema.Position, lag time.Duration, now time.Time) bool {
+	oldest, ok := positionHistory(game, player.Name).Oldest()
+	if !ok {
+		return false
+	}
+	elapsed := now.Sub(oldest.At) + lag
+	distance := math.Hypot(newPosition.X-oldest.Position.X, newPosition.Y-oldest.Position.Y)
+	return distance > game.Config.MaxMovementSpeed*elapsed.Seconds()
+}
@@ -2,7 +2,8 @@ package game
 
 import (
 	"log"
-	"math/rand"
+	"math"
+	"sort"
 	"time"
 
 	"github.com/yorukot/blind-party/internal/config"
@@ -11,30 +12,108 @@ import (
 
 // handlePreGamePhase manages the pre-game waiting phase
 func (h *GameHandler) handlePreGamePhase(game *schema.Game) {
-	log.Printf("Game %s is in PreGame phase with %d players", game.ID, game.PlayerCount)
 	// Get player limits from configuration
 	cfg := config.Env()
+	if cfg.Debug {
+		log.Printf("Game %s is in PreGame phase with %d players", game.ID, game.PlayerCount)
+	}
+	h.flushLobbyUpdate(game)
 	minPlayers := cfg.MinPlayers
 	maxPlayers := cfg.MaxPlayers
 
 	// Validate player count is within bounds
 	if game.PlayerCount > maxPlayers {
-		log.Printf("Game %s exceeded maximum players (%d), rejecting new connections", game.ID, maxPlayers)
+		if cfg.Debug {
+			log.Printf("Game %s exceeded maximum players (%d), rejecting new connections", game.ID, maxPlayers)
+		}
 		return
 	}
 
 	// Start game if we have minimum players
 	if game.PlayerCount >= minPlayers {
-		log.Printf("Game %s starting with minimum players (%d)", game.ID, game.PlayerCount)
+		// Skip the countdown once the lobby is full enough
+		if h.shouldAutoStart(game, maxPlayers) {
+			log.Printf("Game %s reached %.0f%% capacity, starting immediately", game.ID, game.Config.AutoStartCapacityRatio*100)
+			h.startGame(game)
+			return
+		}
+
 		h.startGamePreparation(game)
+		return
+	}
+
+	// Players left during the countdown, wait for the lobby to fill up again
+	if game.Countdown != nil {
+		h.cancelGamePreparation(game, minPlayers)
+	}
+
+	// A lobby that never fills up is given up instead of waiting forever. Time
+	// an admin held the lobby paused doesn't count against it.
+	timeout := time.Duration(game.Config.PreGameTimeoutSeconds * float64(time.Second))
+	if timeout > 0 && time.Since(game.CreatedAt)-game.PausedFor > timeout {
+		h.abandonGame(game, minPlayers)
+	}
+}
+
+// abandonGame ends a pre-game room that stayed under minPlayers for
+// PreGameTimeoutSeconds. Its clients are told and disconnected, the game is
+// removed from memory and its lifecycle stopped.
+func (h *GameHandler) abandonGame(game *schema.Game, minPlayers int) {
+	log.Printf("Game %s abandoned after %.0fs with %d of %d players", game.ID, game.Config.PreGameTimeoutSeconds, game.PlayerCount, minPlayers)
+
+	// The lifecycle stops draining Broadcast, so deliver directly
+	message := map[string]interface{}{
+		"event": "game_abandoned",
+		"data": map[string]interface{}{
+			"game_id":         game.ID,
+			"player_count":    game.PlayerCount,
+			"min_players":     minPlayers,
+			"timeout_seconds": game.Config.PreGameTimeoutSeconds,
+		},
 	}
+	h.recordReplay(game, message)
+	for username, client := range game.Clients {
+		select {
+		case client.Send <- message:
+		default:
+		}
+		closeClient(client, closeGameAbandoned)
+		delete(game.Clients, username)
+	}
+	closeObservers(game, message, closeGameAbandoned)
+
+	h.removeGame(game.ID)
+	game.Stop()
 }
 
-// startGamePreparation begins the 5-second preparation phase
+// cancelGamePreparation stops the start countdown once the lobby drops below minPlayers
+func (h *GameHandler) cancelGamePreparation(game *schema.Game, minPlayers int) {
+	log.Printf("Game %s dropped to %d players, cancelling the start countdown", game.ID, game.PlayerCount)
+	game.Countdown = nil
+
+	game.Publish(map[string]interface{}{
+		"event": "preparation_cancelled",
+		"data": map[string]interface{}{
+			"player_count": game.PlayerCount,
+			"min_players":  minPlayers,
+		},
+	})
+}
+
+// shouldAutoStart reports whether the lobby has reached the configured
+// fraction of maxPlayers and should start without waiting out the countdown
+func (h *GameHandler) shouldAutoStart(game *schema.Game, maxPlayers int) bool {
+	if game.Config.AutoStartCapacityRatio <= 0 || maxPlayers <= 0 {
+		return false
+	}
+	return float64(game.PlayerCount) >= game.Config.AutoStartCapacityRatio*float64(maxPlayers)
+}
+
+// startGamePreparation begins the preparation countdown of AutoStartSeconds
 func (h *GameHandler) startGamePreparation(game *schema.Game) {
-	log.Printf("Game %s entering preparation phase with %d players", game.ID, game.PlayerCount)
 	if game.Countdown == nil {
-		countdown := float64(5)
+		log.Printf("Game %s entering preparation phase with %d players", game.ID, game.PlayerCount)
+		countdown := game.Config.AutoStartSeconds
 		game.Countdown = &countdown
 		game.LastTick = time.Now()
 	} else {
@@ -50,12 +129,22 @@ func (h *GameHandler) startGamePreparation(game *schema.Game) {
 	}
 }
 
-// startGame transitions from PreGame to InGame phase
+// startGame transitions from PreGame to InGame phase. Preparation is ticked by
+// the lifecycle under the game lock rather than scheduled, so a start can only
+// race another tick, and a game that already left PreGame is never started again.
 func (h *GameHandler) startGame(game *schema.Game) {
+	if game.Phase != schema.PreGame {
+		log.Printf("Game %s is already in phase %s, ignoring duplicate start", game.ID, game.Phase)
+		return
+	}
+
 	now := time.Now()
 	game.StartedAt = &now
 	game.Phase = schema.InGame
 
+	// Fit the map to the players who made it in, before anyone spawns
+	h.autoSizeMap(game)
+
 	// Assign spawn positions to all players
 	h.assignSpawnPositions(game)
 
@@ -64,52 +153,152 @@ func (h *GameHandler) startGame(game *schema.Game) {
 	log.Printf("Game %s started with %d players", game.ID, game.PlayerCount)
 
 	// Broadcast game start with full game state
-	game.Broadcast <- map[string]interface{}{
+	game.Publish(map[string]interface{}{
 		"event": "game_update",
 		"data": map[string]interface{}{
 			"phase":   game.Phase,
 			"game_id": game.ID,
-			"players": game.PlayersList,
+			"players": playersSnapshot(game.PlayersList),
 			"map":     game.MapArray,
 		},
+	})
+
+	players := make([]string, 0, len(game.Players))
+	for _, player := range game.Players {
+		players = append(players, player.Name)
 	}
+	h.notifyWebhook(game, "game_started", map[string]interface{}{
+		"players":      players,
+		"player_count": game.PlayerCount,
+	})
 
+	h.startFirstRoundGrace(game)
 }
 
-// assignSpawnPositions assigns random spawn positions to all players on valid colored blocks
+// startFirstRoundGrace gives players a moment to see the map before round one.
+// The countdown is ticked by handleInGamePhase like the break between rounds,
+// so a game torn down during the grace simply never starts its first round.
+func (h *GameHandler) startFirstRoundGrace(game *schema.Game) {
+	if game.Config.FirstRoundGraceSeconds <= 0 {
+		game.Countdown = nil
+		return
+	}
+
+	grace := game.Config.FirstRoundGraceSeconds
+	game.Countdown = &grace
+	game.LastTick = time.Now()
+
+	game.Publish(map[string]interface{}{
+		"event": "game_starting_soon",
+		"data": map[string]interface{}{
+			"game_id":       game.ID,
+			"map":           game.MapArray,
+			"grace_seconds": grace,
+		},
+	})
+}
+
+// assignSpawnPositions assigns random spawn positions to all players on valid
+// colored blocks, kept as far apart as possible in spread mode
 func (h *GameHandler) assignSpawnPositions(game *schema.Game) {
-	// Collect all valid spawn positions (any colored block, not Air)
+	validPositions := h.validSpawnPositions(game)
+	// Symmetric spawns already give everyone equivalent, evenly placed spots
+	if game.Config.SpawnMode == schema.SpawnSpread && game.Config.SymmetricMap == schema.MapSymmetryNone {
+		validPositions = spreadSpawnPositions(validPositions, len(game.Players))
+	}
+
+	// Assign positions to players in name order, so a fixed source always
+	// puts the same player on the same block
+	names := make([]string, 0, len(game.Players))
+	for name := range game.Players {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	positionIndex := 0
+	for _, name := range names {
+		player := game.Players[name]
+		if positionIndex < len(validPositions) {
+			placeAtSpawn(player, validPositions[positionIndex])
+			positionIndex++
+
+			log.Printf("Player %s (%s) spawned at position (%.1f, %.1f)",
+				player.Name, player.Name, player.Position.X, player.Position.Y)
+		}
+	}
+}
+
+// validSpawnPositions returns the centers of all colored blocks in random order.
+// On a symmetric map they come in groups of equivalent blocks instead.
+func (h *GameHandler) validSpawnPositions(game *schema.Game) []schema.Position {
+	if game.Config.SymmetricMap != schema.MapSymmetryNone {
+		return h.symmetricSpawnPositions(game)
+	}
+
+	// Collect all valid spawn positions (any colored block, not Air or the border)
 	validPositions := make([]schema.Position, 0)
 
 	for y := 0; y < game.Config.MapHeight; y++ {
 		for x := 0; x < game.Config.MapWidth; x++ {
-			if game.Map[y][x] != schema.Air { // Not Air block
-				// Use 1-based coordinate system (1-20 range) with 2 decimal precision
-				validPositions = append(validPositions, schema.Position{
-					X: float64(x+1) + 0.5, // Block coordinates: 1.5, 2.5, ..., 20.5
-					Y: float64(y+1) + 0.5,
-				})
+			if spawnable(game, x, y) {
+				validPositions = append(validPositions, spawnPoint(x, y))
 			}
 		}
 	}
 
 	// Shuffle positions for random assignment
-	rand.Shuffle(len(validPositions), func(i, j int) {
+	game.Rand.Shuffle(len(validPositions), func(i, j int) {
 		validPositions[i], validPositions[j] = validPositions[j], validPositions[i]
 	})
 
-	// Assign positions to players
-	positionIndex := 0
-	for _, player := range game.Players {
-		if positionIndex < len(validPositions) {
-			player.Position = validPositions[positionIndex]
-			player.LastValidPosition = player.Position
-			positionIndex++
+	return validPositions
+}
 
-			log.Printf("Player %s (%s) spawned at position (%.1f, %.1f)",
-				player.Name, player.Name, player.Position.X, player.Position.Y)
+// spreadSpawnPositions picks count of the candidates greedily by farthest
+// point: starting from the first, each next one is the candidate farthest from
+// all picked so far. Candidates come shuffled, so equally far ones and the
+// starting point are random.
+func spreadSpawnPositions(candidates []schema.Position, count int) []schema.Position {
+	if count >= len(candidates) || count < 1 {
+		return candidates
+	}
+
+	// nearest[i] is the distance from candidate i to the closest picked position
+	picked := make([]schema.Position, 0, count)
+	nearest := make([]float64, len(candidates))
+	for i := range nearest {
+		nearest[i] = math.Inf(1)
+	}
+	next := 0
+	for len(picked) < count {
+		chosen := candidates[next]
+		picked = append(picked, chosen)
+		nearest[next] = -1 // Never picked twice
+
+		next = -1
+		for i, candidate := range candidates {
+			if nearest[i] < 0 {
+				continue
+			}
+			nearest[i] = min(nearest[i], math.Hypot(candidate.X-chosen.X, candidate.Y-chosen.Y))
+			if next < 0 || nearest[i] > nearest[next] {
+				next = i
+			}
 		}
 	}
+	return picked
+}
+
+// spawnable reports whether a player may spawn on the block: any colored block, not Air or the border
+func spawnable(game *schema.Game, x, y int) bool {
+	color, _ := game.ColorAt(x, y)
+	return !inBorder(game, x, y) && color != schema.Air
+}
+
+// spawnPoint returns where a player spawning on the block is placed: its
+// center, the position worldToCell maps back to the same block
+func spawnPoint(x, y int) schema.Position {
+	return schema.Position{X: float64(x), Y: float64(y)}
 }
 
 // initializeAllPlayerStats initializes statistics and movement tracking for all players
@@ -121,12 +310,14 @@ func (h *GameHandler) initializeAllPlayerStats(game *schema.Game) {
 		player.LastUpdate = now
 		player.LastMoveTime = now
 		player.MovementSpeed = game.Config.BaseMovementSpeed
+		player.RevivesLeft = game.Config.RevivesPerPlayer
+		player.LivesLeft = game.Config.Lives
 
 		// Initialize statistics
 		player.Stats = schema.PlayerStats{
-			RoundsSurvived:      0,
-			TotalDistance:       0,
-			FinalPosition:       0,
+			RoundsSurvived: 0,
+			TotalDistance:  0,
+			FinalPosition:  0,
 		}
 
 		log.Printf("Initialized stats for player %s (%s)", player.Name, player.Name)
@@ -0,0 +1,234 @@
+package game
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yorukot/blind-party/internal/config"
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+func TestAutoStartFiresAfterConfiguredWait(t *testing.T) {
+	h, game := newTestGame(t, func(cfg *schema.GameConfig) {
+		cfg.AutoStartSeconds = 0.2
+		cfg.AutoStartCapacityRatio = 0
+	})
+	joinTestPlayers(t, h, game, "p1", "p2", "p3", "p4")
+
+	start := time.Now()
+	for game.Phase == schema.PreGame && time.Since(start) < 2*time.Second {
+		h.processGameState(game)
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if game.Phase != schema.InGame {
+		t.Fatal("game did not start")
+	}
+	if waited := game.StartedAt.Sub(start); waited < 200*time.Millisecond || waited > time.Second {
+		t.Errorf("started after %v, want about 200ms", waited)
+	}
+}
+
+func TestAutoStartOnCapacityRatio(t *testing.T) {
+	h, game := newTestGame(t, func(cfg *schema.GameConfig) {
+		cfg.AutoStartCapacityRatio = 0.5
+	})
+	joinTestPlayers(t, h, game, "p1", "p2", "p3", "p4")
+
+	tests := []struct {
+		maxPlayers int
+		want       bool
+	}{
+		{8, true},   // 4 of 8 is half full
+		{10, false}, // 4 of 10 isn't
+		{6, true},
+		{32, false},
+	}
+	for _, tt := range tests {
+		if got := h.shouldAutoStart(game, tt.maxPlayers); got != tt.want {
+			t.Errorf("4 players of %d: shouldAutoStart = %v, want %v", tt.maxPlayers, got, tt.want)
+		}
+	}
+
+	game.Config.AutoStartCapacityRatio = 0
+	if h.shouldAutoStart(game, 4) {
+		t.Error("a zero ratio started the game")
+	}
+}
+
+func TestFirstColorCallWaitsForGrace(t *testing.T) {
+	h, game := newTestGame(t, func(cfg *schema.GameConfig) {
+		cfg.FirstRoundGraceSeconds = 0.2
+	})
+	joinTestPlayers(t, h, game, "p1", "p2")
+	published(game)
+
+	h.startGame(game)
+	soon := withEvent(published(game), "game_starting_soon")
+	if len(soon) != 1 || soon[0]["grace_seconds"] != 0.2 || soon[0]["map"] == nil {
+		t.Fatalf("game_starting_soon = %v", soon)
+	}
+
+	start := *game.StartedAt
+	for game.CurrentRound == nil && time.Since(start) < 2*time.Second {
+		h.processGameState(game)
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if game.CurrentRound == nil || game.CurrentRound.Number != 1 {
+		t.Fatal("first round did not start")
+	}
+	if waited := game.CurrentRound.StartTime.Sub(start); waited < 200*time.Millisecond || waited > time.Second {
+		t.Errorf("first color called after %v, want about 200ms", waited)
+	}
+}
+
+func TestLeaversDontCountTowardsTheStart(t *testing.T) {
+	h, game := newTestGame(t, func(cfg *schema.GameConfig) {
+		cfg.AutoStartCapacityRatio = 0
+	})
+	minPlayers := config.Env().MinPlayers
+	names := make([]string, 0, minPlayers)
+	for i := 0; i < minPlayers; i++ {
+		names = append(names, fmt.Sprintf("p%d", i))
+	}
+	clients := joinTestPlayers(t, h, game, names...)
+
+	h.handlePreGamePhase(game)
+	if game.Countdown == nil {
+		t.Fatalf("%d players did not start the countdown", minPlayers)
+	}
+
+	h.handleClientUnregister(game, clients["p0"])
+	if game.PlayerCount != minPlayers-1 || len(game.Players) != minPlayers-1 || len(game.PlayersList) != minPlayers-1 {
+		t.Fatalf("after a leave: player count %d, %d players, %d listed, want %d",
+			game.PlayerCount, len(game.Players), len(game.PlayersList), minPlayers-1)
+	}
+	for _, player := range game.PlayersList {
+		if player.Name == "p0" {
+			t.Error("the leaver is still listed")
+		}
+	}
+
+	published(game)
+	h.handlePreGamePhase(game)
+	if game.Countdown != nil || game.Phase != schema.PreGame {
+		t.Errorf("the countdown kept running one player short, phase %s", game.Phase)
+	}
+	if cancelled := withEvent(published(game), "preparation_cancelled"); len(cancelled) != 1 {
+		t.Errorf("got %d preparation_cancelled events, want 1", len(cancelled))
+	}
+
+	// Rejoining brings the lobby back to the minimum
+	joinTestPlayers(t, h, game, "p0")
+	h.handlePreGamePhase(game)
+	if game.Countdown == nil {
+		t.Error("the countdown did not restart once the lobby was back at the minimum")
+	}
+}
+
+func TestUnfilledLobbyIsAbandoned(t *testing.T) {
+	if config.Env().MinPlayers < 2 {
+		t.Skip("needs MinPlayers of at least 2")
+	}
+	h, game := newTestGame(t, func(cfg *schema.GameConfig) {
+		cfg.PreGameTimeoutSeconds = 0.2
+	})
+	clients := joinTestPlayers(t, h, game, "alice")
+	runGame(t, h, game)
+
+	select {
+	case <-game.Done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the unfilled lobby is still running")
+	}
+	if _, exists := h.getGame(game.ID); exists {
+		t.Error("abandoned game is still stored")
+	}
+	alice := clients["alice"]
+	if abandoned := withEvent(received(alice), "game_abandoned"); len(abandoned) != 1 || abandoned[0]["player_count"] != 1 {
+		t.Errorf("alice got game_abandoned %v, want one with 1 player", abandoned)
+	}
+	if alice.CloseCode != closeGameAbandoned.Code {
+		t.Errorf("alice closed with %d, want %d", alice.CloseCode, closeGameAbandoned.Code)
+	}
+}
+
+func TestRapidTicksStartTheGameOnce(t *testing.T) {
+	h, game := newTestGame(t, func(cfg *schema.GameConfig) {
+		cfg.AutoStartSeconds = 0.05
+		cfg.AutoStartCapacityRatio = 0
+		cfg.FirstRoundGraceSeconds = 60
+	})
+	joinTestPlayers(t, h, game, "p1", "p2", "p3", "p4")
+
+	// Every start stamps a new StartedAt, collect those seen by the ticks
+	starts := make(map[*time.Time]bool)
+	h.tickHook = func(game *schema.Game) {
+		if game.StartedAt != nil {
+			starts[game.StartedAt] = true
+		}
+	}
+	go func() {
+		for range game.Broadcast {
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			deadline := time.Now().Add(300 * time.Millisecond)
+			for time.Now().Before(deadline) {
+				h.processGameState(game)
+				time.Sleep(time.Millisecond)
+			}
+		}()
+	}
+	wg.Wait()
+	close(game.Broadcast)
+
+	if game.Phase != schema.InGame {
+		t.Fatalf("game in %s, want in-game", game.Phase)
+	}
+	starts[game.StartedAt] = true
+	if len(starts) != 1 {
+		t.Errorf("game started %d times, want once", len(starts))
+	}
+}
+
+// closestSpawns returns the smallest distance between two players' spawns
+func closestSpawns(game *schema.Game) float64 {
+	closest := math.Inf(1)
+	for _, a := range game.Players {
+		for _, b := range game.Players {
+			if a != b {
+				closest = min(closest, math.Hypot(a.Position.X-b.Position.X, a.Position.Y-b.Position.Y))
+			}
+		}
+	}
+	return closest
+}
+
+func TestSpreadSpawnsFartherApartThanRandom(t *testing.T) {
+	names := []string{"p1", "p2", "p3", "p4", "p5", "p6", "p7", "p8"}
+	for seed := int64(1); seed <= 10; seed++ {
+		closest := make(map[schema.SpawnMode]float64)
+		for _, mode := range []schema.SpawnMode{schema.SpawnRandom, schema.SpawnSpread} {
+			h, game := newTestGame(t, func(cfg *schema.GameConfig) {
+				cfg.MapSeed = seed
+				cfg.SpawnMode = mode
+			})
+			joinTestPlayers(t, h, game, names...)
+			h.assignSpawnPositions(game)
+			closest[mode] = closestSpawns(game)
+		}
+		if closest[schema.SpawnSpread] <= closest[schema.SpawnRandom] {
+			t.Errorf("map seed %d: spread spawns %.1f apart, random %.1f", seed, closest[schema.SpawnSpread], closest[schema.SpawnRandom])
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package game
+
+import (
+	"encoding/json"
+	"log"
+	"math/rand"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/yorukot/blind-party/internal/schema"
+	"github.com/yorukot/blind-party/pkg/response"
+)
+
+// regenerateMapRequest is the body of RegenerateMap
+type regenerateMapRequest struct {
+	UserID  string `json:"user_id"`
+	MapSeed int64  `json:"map_seed,omitempty"` // 0 picks a random seed
+}
+
+// regenerateMapResponse is the response of RegenerateMap
+type regenerateMapResponse struct {
+	GameID  string `json:"game_id"`
+	MapSeed int64  `json:"map_seed"`
+}
+
+// RegenerateMap lets the host swap the map for a new one before the game starts
+func (h *GameHandler) RegenerateMap(w http.ResponseWriter, r *http.Request) {
+	gameID := chi.URLParam(r, "gameID")
+	if gameID == "" {
+		response.Fail(w, http.StatusBadRequest, "MISSING_GAME_ID", "Game ID is required")
+		return
+	}
+
+	game, exists := h.getGame(gameID)
+	if !exists {
+		response.Fail(w, http.StatusNotFound, "GAME_NOT_FOUND", "Game not found")
+		return
+	}
+
+	var req regenerateMapRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Fail(w, http.StatusBadRequest, "INVALID_REQUEST_BODY", "Request body must be valid JSON")
+		return
+	}
+
+	game.Mu.Lock()
+	defer game.Mu.Unlock()
+
+	if game.HostID == "" || req.UserID != game.HostID {
+		response.Fail(w, http.StatusForbidden, "HOST_ONLY", "Only the host can regenerate the map")
+		return
+	}
+	if game.Phase != schema.PreGame {
+		response.Fail(w, http.StatusConflict, "GAME_ALREADY_STARTED", "The map can only be regenerated before the game starts")
+		return
+	}
+
+	// Always hand out a different map than the current one
+	seed := req.MapSeed
+	for seed == 0 || seed == game.MapSeed {
+		seed = h.newMapSeed()
+	}
+
+	game.Map = generateRandomMap(seed)
+	game.MapSeed = seed
+	game.CustomMap = false
+	game.Rand = rand.New(rand.NewSource(seed))
+	h.symmetrizeMap(game)
+	h.applyBorder(game)
+	h.syncMapArray(game)
+	log.Printf("Host %s regenerated the map of game %s with seed %d", req.UserID, game.ID, seed)
+
+	game.Publish(map[string]interface{}{
+		"event": "map_changed",
+		"data": map[string]interface{}{
+			"game_id":  game.ID,
+			"map":      game.MapArray,
+			"map_seed": seed,
+		},
+	})
+
+	response.OK(w, regenerateMapResponse{GameID: game.ID, MapSeed: seed})
+}
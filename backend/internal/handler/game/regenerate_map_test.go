@@ -0,0 +1,99 @@
+package game
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+func regenerateMap(t *testing.T, h *GameHandler, gameID string, body map[string]any) *httptest.ResponseRecorder {
+	t.Helper()
+	return serveRoute(h.RegenerateMap, http.MethodPost, "/api/game/{gameID}/regenerate-map", "/api/game/"+gameID+"/regenerate-map", jsonBody(t, body))
+}
+
+func TestRegenerateMapInPreGame(t *testing.T) {
+	h, game := newTestGame(t, nil)
+	game.HostID = "host-id"
+	h.storeGame(game)
+
+	rec := regenerateMap(t, h, game.ID, map[string]any{"user_id": "host-id", "map_seed": 7})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	if game.MapSeed != 7 {
+		t.Errorf("map seed = %d, want 7", game.MapSeed)
+	}
+	changes := withEvent(published(game), "map_changed")
+	if len(changes) != 1 {
+		t.Fatalf("got %d map_changed events, want 1", len(changes))
+	}
+	if !reflect.DeepEqual(changes[0]["map"], game.MapArray) {
+		t.Error("map_changed does not carry the new map")
+	}
+
+	// The same seed gives the same map
+	first := game.MapArray
+	regenerateMap(t, h, game.ID, map[string]any{"user_id": "host-id", "map_seed": 8})
+	regenerateMap(t, h, game.ID, map[string]any{"user_id": "host-id", "map_seed": 7})
+	if !reflect.DeepEqual(game.MapArray, first) {
+		t.Error("regenerating with seed 7 again gave a different map")
+	}
+}
+
+func TestRegenerateMapRejected(t *testing.T) {
+	h, game := newTestGame(t, nil)
+	game.HostID = "host-id"
+	h.storeGame(game)
+
+	if rec := regenerateMap(t, h, game.ID, map[string]any{"user_id": "guest-id"}); rec.Code != http.StatusForbidden {
+		t.Errorf("guest: status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+
+	game.Phase = schema.InGame
+	if rec := regenerateMap(t, h, game.ID, map[string]any{"user_id": "host-id"}); rec.Code != http.StatusConflict {
+		t.Errorf("after start: status = %d, want %d", rec.Code, http.StatusConflict)
+	}
+	if len(withEvent(published(game), "map_changed")) != 0 {
+		t.Error("a rejected regeneration broadcast map_changed")
+	}
+}
+
+func TestHostIDIsNeverSentToClients(t *testing.T) {
+	h, game := newTestGame(t, nil)
+	game.HostID = "id-host"
+	h.storeGame(game)
+	joinTestPlayers(t, h, game, "guest", "host")
+
+	state := gameState(t, h, game.ID)
+	if bytes.Contains(state, []byte(game.HostID)) {
+		t.Errorf("game state carries the host's user_id: %s", state)
+	}
+	var body struct {
+		Data struct {
+			HostName string `json:"host_name"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(state, &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Data.HostName != "host" {
+		t.Errorf("host_name = %q, want host", body.Data.HostName)
+	}
+
+	for _, message := range published(game) {
+		encoded, _ := json.Marshal(message)
+		if bytes.Contains(encoded, []byte(game.HostID)) {
+			t.Errorf("%v carries the host's user_id", message["event"])
+		}
+	}
+	h.broadcastLobbyUpdate(game)
+	updates := withEvent(published(game), "lobby_update")
+	if len(updates) != 1 || updates[0]["host_name"] != "host" {
+		t.Errorf("lobby_update = %v, want host_name host", updates)
+	}
+}
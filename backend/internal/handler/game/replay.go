@@ -0,0 +1,68 @@
+package game
+
+import (
+	"log"
+	"net/http"
+	"path/filepath"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/yorukot/blind-party/internal/replay"
+	"github.com/yorukot/blind-party/internal/schema"
+	"github.com/yorukot/blind-party/pkg/response"
+)
+
+// startReplay creates the game's replay file in dir and records the initial map and config
+func (h *GameHandler) startReplay(game *schema.Game, dir string) {
+	recorder, err := replay.NewRecorder(dir, game.ID, game.CreatedAt)
+	if err != nil {
+		log.Printf("Failed to start replay for game %s: %v", game.ID, err)
+		return
+	}
+	game.Replay = recorder
+
+	h.recordReplay(game, map[string]interface{}{
+		"event": "replay_started",
+		"data": map[string]interface{}{
+			"game_id":    game.ID,
+			"created_at": game.CreatedAt,
+			"map":        game.MapArray,
+			"map_seed":   game.MapSeed,
+			"config":     game.Config,
+		},
+	})
+}
+
+// recordReplay tees a broadcast message into the replay file if recording is enabled
+func (h *GameHandler) recordReplay(game *schema.Game, message interface{}) {
+	if game.Replay == nil {
+		return
+	}
+	if err := game.Replay.Record(message); err != nil {
+		log.Printf("Failed to record replay for game %s: %v", game.ID, err)
+	}
+}
+
+// GetReplay downloads the NDJSON replay of a game
+func (h *GameHandler) GetReplay(w http.ResponseWriter, r *http.Request) {
+	gameID := chi.URLParam(r, "gameID")
+	if gameID == "" {
+		response.Fail(w, http.StatusBadRequest, "MISSING_GAME_ID", "Game ID is required")
+		return
+	}
+
+	game, exists := h.getGame(gameID)
+	if !exists {
+		response.Fail(w, http.StatusNotFound, "GAME_NOT_FOUND", "Game not found")
+		return
+	}
+
+	if game.Replay == nil {
+		response.Fail(w, http.StatusNotFound, "REPLAY_NOT_FOUND", "Replay recording is not enabled for this game")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Content-Disposition", "attachment; filename=\""+filepath.Base(game.Replay.Path())+"\"")
+	http.ServeFile(w, r, game.Replay.Path())
+}
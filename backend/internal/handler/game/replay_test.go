@@ -0,0 +1,122 @@
+package game
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+// playQuickGame plays a game between four idle players with tenth of a
+// second rounds until it is settled
+func playQuickGame(t *testing.T, h *GameHandler, game *schema.Game) {
+	t.Helper()
+	clients := joinTestPlayers(t, h, game, "p1", "p2", "p3", "p4")
+	for _, client := range clients {
+		go func(send <-chan interface{}) {
+			for range send {
+			}
+		}(client.Send)
+	}
+
+	runGame(t, h, game)
+	deadline := time.Now().Add(15 * time.Second)
+	for {
+		game.Mu.RLock()
+		phase := game.Phase
+		game.Mu.RUnlock()
+		if phase == schema.Settlement {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("game still in %s", phase)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// quickGameConfig shortens every wait of a game to a fraction of a second
+func quickGameConfig(cfg *schema.GameConfig) {
+	cfg.AutoStartSeconds = 0
+	cfg.FirstRoundGraceSeconds = 0
+	cfg.RoundBreatherSeconds = 0.05
+	cfg.TimingProgression = []schema.TimingRange{{StartRound: 1, EndRound: 1, Duration: 0.1}}
+}
+
+func TestReplayRecordsRoundsAndEliminationsInOrder(t *testing.T) {
+	h, game := newTestGame(t, quickGameConfig)
+	h.startReplay(game, t.TempDir())
+	playQuickGame(t, h, game)
+
+	// The settlement's messages are recorded as the lifecycle broadcasts them
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		recorded, _ := os.ReadFile(game.Replay.Path())
+		if bytes.Contains(recorded, []byte(`"game_ended"`)) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("game_ended was not recorded")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	rec := serveRoute(h.GetReplay, http.MethodGet, "/api/game/{gameID}/replay", "/api/game/"+game.ID+"/replay", nil)
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/x-ndjson" {
+		t.Fatalf("download: %d %s", rec.Code, rec.Header().Get("Content-Type"))
+	}
+
+	// Index of the first record of each kind
+	first := make(map[string]int)
+	index := 0
+	scanner := bufio.NewScanner(rec.Body)
+	scanner.Buffer(nil, 1<<20)
+	for ; scanner.Scan(); index++ {
+		var record struct {
+			Message struct {
+				Event string                 `json:"event"`
+				Data  map[string]interface{} `json:"data"`
+			} `json:"message"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("line %d: %v", index+1, err)
+		}
+		kind := record.Message.Event
+		if _, ok := record.Message.Data["countdown"]; ok && kind == "game_update" {
+			kind = "round_started"
+		}
+		if _, ok := record.Message.Data["eliminated_players"]; ok {
+			kind = "eliminations"
+		}
+		if _, seen := first[kind]; !seen {
+			first[kind] = index
+		}
+	}
+
+	if first["replay_started"] != 0 {
+		t.Errorf("first record is not replay_started")
+	}
+	order := []string{"replay_started", "round_started", "eliminations", "game_ended"}
+	for i := 1; i < len(order); i++ {
+		before, ok1 := first[order[i-1]]
+		after, ok2 := first[order[i]]
+		if !ok1 || !ok2 || before >= after {
+			t.Errorf("%s (%d, %v) does not come before %s (%d, %v)", order[i-1], before, ok1, order[i], after, ok2)
+		}
+	}
+}
+
+func TestReplayDisabled(t *testing.T) {
+	h, game := newTestGame(t, nil)
+	h.storeGame(game)
+
+	rec := serveRoute(h.GetReplay, http.MethodGet, "/api/game/{gameID}/replay", "/api/game/"+game.ID+"/replay", nil)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", rec.Code)
+	}
+}
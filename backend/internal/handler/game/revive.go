@@ -0,0 +1,53 @@
+package game
+
+import (
+	"log"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+// downPlayer spends one of the player's revive tokens instead of eliminating
+// them. The player sits out the rest of the round and respawns at the start of
+// the next one. It reports false if the player has no revives left.
+func (h *GameHandler) downPlayer(game *schema.Game, player *schema.Player, reason schema.EliminationReason) bool {
+	if player.RevivesLeft <= 0 {
+		return false
+	}
+
+	player.RevivesLeft--
+	player.IsDowned = true
+	player.Stats.DownedRounds++
+
+	log.Printf("Player %s downed (%s) in round %d, %d revives left",
+		player.Name, reason, game.CurrentRound.Number, player.RevivesLeft)
+	return true
+}
+
+// reviveDownedPlayers respawns downed players on a colored block of the new map
+func (h *GameHandler) reviveDownedPlayers(game *schema.Game) {
+	spawns := h.validSpawnPositions(game)
+	for _, player := range game.Players {
+		if !player.IsDowned {
+			continue
+		}
+
+		player.IsDowned = false
+		if len(spawns) > 0 {
+			placeAtSpawn(player, spawns[0])
+			spawns = spawns[1:]
+		}
+
+		log.Printf("Player %s revived in game %s at (%.1f, %.1f)",
+			player.Name, game.ID, player.Position.X, player.Position.Y)
+
+		game.Publish(map[string]any{
+			"event": "player_revived",
+			"data": map[string]any{
+				"player_name":  player.Name,
+				"position":     player.Position,
+				"revives_left": player.RevivesLeft,
+				"round_number": game.RoundNumber,
+			},
+		})
+	}
+}
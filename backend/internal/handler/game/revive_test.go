@@ -0,0 +1,52 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+func TestReviveTokensThenFinalElimination(t *testing.T) {
+	h, game, _ := startTestGame(t, func(cfg *schema.GameConfig) {
+		cfg.RevivesPerPlayer = 1
+	}, "alice", "bob", "carol")
+	h.startNewRound(game)
+	alice := game.Players["alice"]
+
+	// Caught with a token left, alice is downed instead of eliminated
+	allColorsSafe(game)
+	alice.Position = schema.Position{X: -5, Y: -5}
+	h.handleEliminationCheckPhase(game)
+	if alice.IsEliminated || !alice.IsDowned || alice.RevivesLeft != 0 {
+		t.Fatalf("alice eliminated %v, downed %v, %d revives left", alice.IsEliminated, alice.IsDowned, alice.RevivesLeft)
+	}
+	if alice.Stats.DownedRounds != 1 {
+		t.Errorf("downed rounds = %d, want 1", alice.Stats.DownedRounds)
+	}
+	if totalScore(alice) >= totalScore(game.Players["bob"]) {
+		t.Errorf("downed alice scored %d, as much as bob's %d", totalScore(alice), totalScore(game.Players["bob"]))
+	}
+	published(game)
+
+	// Alice respawns on the new map at the start of the next round
+	h.startNewRound(game)
+	if alice.IsDowned {
+		t.Fatal("alice still downed in the next round")
+	}
+	x, y := worldToCell(alice.Position, game.Config.CellEpsilon)
+	if color, ok := game.ColorAt(x, y); !ok || color == schema.Air {
+		t.Errorf("alice respawned on %v at (%d, %d)", color, x, y)
+	}
+	revived := withEvent(published(game), "player_revived")
+	if len(revived) != 1 || revived[0]["player_name"] != "alice" || revived[0]["revives_left"] != 0 {
+		t.Errorf("player_revived = %v", revived)
+	}
+
+	// Without tokens the next catch is final
+	allColorsSafe(game)
+	alice.Position = schema.Position{X: -5, Y: -5}
+	h.handleEliminationCheckPhase(game)
+	if !alice.IsEliminated || alice.IsDowned {
+		t.Errorf("alice eliminated %v, downed %v, want eliminated", alice.IsEliminated, alice.IsDowned)
+	}
+}
@@ -0,0 +1,83 @@
+package game
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+// roundPhaseTransitions lists the phases each round phase may move to. The
+// elimination check is the last phase, the round ends after it.
+var roundPhaseTransitions = map[schema.RoundPhase][]schema.RoundPhase{
+	schema.ColorCall:        {schema.EliminationCheck},
+	schema.EliminationCheck: {},
+}
+
+// advancePhase moves the round to the next phase, rejecting transitions the
+// round phase state machine doesn't allow
+func advancePhase(round *schema.Round, next schema.RoundPhase) error {
+	allowed, known := roundPhaseTransitions[round.Phase]
+	if !known {
+		return fmt.Errorf("round %d is in unknown phase %q", round.Number, round.Phase)
+	}
+	for _, phase := range allowed {
+		if phase == next {
+			round.Phase = next
+			round.PhaseStartedAt = time.Now()
+			return nil
+		}
+	}
+	return fmt.Errorf("round %d can't move from %s to %s", round.Number, round.Phase, next)
+}
+
+// roundPhaseExpired reports whether the current round has been in its phase
+// longer than MaxPhaseSeconds
+func (h *GameHandler) roundPhaseExpired(game *schema.Game) bool {
+	if game.Config.MaxPhaseSeconds <= 0 || game.CurrentRound.PhaseStartedAt.IsZero() {
+		return false
+	}
+	maxDuration := time.Duration(game.Config.MaxPhaseSeconds * float64(time.Second))
+	return time.Since(game.CurrentRound.PhaseStartedAt) > maxDuration
+}
+
+// forceFinishPhase is the watchdog for a round that stopped progressing. A
+// stuck color call is ended as if its countdown ran out; a round in any other
+// phase is abandoned without eliminations and the next one follows the usual break.
+func (h *GameHandler) forceFinishPhase(game *schema.Game) {
+	round := game.CurrentRound
+	log.Printf("Watchdog force-finishing round %d of game %s stuck in phase %q since %s",
+		round.Number, game.ID, round.Phase, round.PhaseStartedAt.Format(time.RFC3339))
+
+	if round.Phase == schema.ColorCall {
+		h.finishColorCall(game)
+		return
+	}
+
+	now := time.Now()
+	round.EndTime = &now
+	game.CurrentRound = nil
+	startRoundBreather(game, now)
+}
+
+// snapshotPositions captures every player's position as the rush ended. The
+// rush is held open for the longest lag compensation window, so each player is
+// judged where they were at the deadline plus their own window, not later.
+func (h *GameHandler) snapshotPositions(game *schema.Game) {
+	deadline := game.CurrentRound.StartTime.Add(time.Duration(game.CurrentRound.RushDuration * float64(time.Second)))
+	snapshot := make(map[string]schema.Position, len(game.Players))
+	for name, player := range game.Players {
+		snapshot[name] = positionAt(game, player, deadline.Add(h.lagCompensationFor(game, player)))
+	}
+	game.CurrentRound.PositionSnapshot = snapshot
+}
+
+// judgedPosition returns the position the elimination check judges the player
+// on: the snapshot taken when the rush ended, or the live position without one
+func judgedPosition(round *schema.Round, player *schema.Player) schema.Position {
+	if position, ok := round.PositionSnapshot[player.Name]; ok {
+		return position
+	}
+	return player.Position
+}
@@ -0,0 +1,179 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+func TestAdvancePhase(t *testing.T) {
+	tests := []struct {
+		from, to schema.RoundPhase
+		allowed  bool
+	}{
+		{schema.ColorCall, schema.EliminationCheck, true},
+		{schema.ColorCall, schema.ColorCall, false},
+		{schema.EliminationCheck, schema.ColorCall, false},
+		{"bogus", schema.EliminationCheck, false},
+	}
+	for _, tt := range tests {
+		round := &schema.Round{Number: 1, Phase: tt.from}
+		err := advancePhase(round, tt.to)
+		if (err == nil) != tt.allowed {
+			t.Errorf("%s -> %s: err = %v, want allowed %v", tt.from, tt.to, err, tt.allowed)
+		}
+		if tt.allowed && (round.Phase != tt.to || round.PhaseStartedAt.IsZero()) {
+			t.Errorf("%s -> %s: round is in %s since %v", tt.from, tt.to, round.Phase, round.PhaseStartedAt)
+		}
+		if !tt.allowed && round.Phase != tt.from {
+			t.Errorf("%s -> %s: rejected transition moved the round to %s", tt.from, tt.to, round.Phase)
+		}
+	}
+}
+
+// stuckRound starts a round and leaves it in phase, entered long before
+// MaxPhaseSeconds with a countdown that never runs out, as after a clock jump
+func stuckRound(t *testing.T, phase schema.RoundPhase) (*GameHandler, *schema.Game) {
+	t.Helper()
+	h, game, _ := startTestGame(t, func(cfg *schema.GameConfig) {
+		cfg.MaxPhaseSeconds = 30
+	}, "alice", "bob")
+	h.startNewRound(game)
+
+	forever := 1e9
+	game.Countdown = &forever
+	game.LastTick = time.Now()
+	game.CurrentRound.Phase = phase
+	game.CurrentRound.PhaseStartedAt = time.Now().Add(-31 * time.Second)
+	return h, game
+}
+
+func TestWatchdogFinishesStuckColorCall(t *testing.T) {
+	h, game := stuckRound(t, schema.ColorCall)
+
+	h.handleInGamePhase(game)
+
+	if game.CurrentRound == nil || game.CurrentRound.Phase != schema.EliminationCheck {
+		t.Fatalf("stuck color call was not moved to the elimination check: %+v", game.CurrentRound)
+	}
+	if game.Countdown != nil {
+		t.Errorf("countdown %v left running", *game.Countdown)
+	}
+}
+
+func TestWatchdogAbandonsRoundInUnknownPhase(t *testing.T) {
+	h, game := stuckRound(t, "bogus")
+	round := game.CurrentRound
+
+	h.handleInGamePhase(game)
+
+	if game.CurrentRound != nil {
+		t.Fatal("round in an unknown phase was kept")
+	}
+	if round.EndTime == nil || round.EliminatedCount != 0 {
+		t.Errorf("abandoned round ended at %v with %d eliminations", round.EndTime, round.EliminatedCount)
+	}
+	if game.Countdown == nil || *game.Countdown != game.Config.RoundBreatherSeconds {
+		t.Errorf("no breather before the next round, countdown %v", game.Countdown)
+	}
+}
+
+func TestWatchdogLeavesRoundWithinLimit(t *testing.T) {
+	h, game := stuckRound(t, schema.ColorCall)
+	game.CurrentRound.PhaseStartedAt = time.Now().Add(-29 * time.Second)
+
+	h.handleInGamePhase(game)
+
+	if game.CurrentRound.Phase != schema.ColorCall {
+		t.Errorf("round within MaxPhaseSeconds was moved to %s", game.CurrentRound.Phase)
+	}
+}
+
+func TestMovementAfterSnapshotDoesNotCount(t *testing.T) {
+	for _, snapshot := range []bool{true, false} {
+		h, game, _ := startTestGame(t, func(cfg *schema.GameConfig) {
+			cfg.EliminationSnapshot = snapshot
+		}, "alice", "bob", "carol")
+		safe, unsafe := startTestRound(t, h, game)
+
+		// At the deadline alice is on the safe color and bob isn't, then they swap
+		for _, player := range game.Players {
+			player.Position = safe
+		}
+		game.Players["bob"].Position = unsafe
+		h.finishColorCall(game)
+		game.Players["alice"].Position = unsafe
+		game.Players["bob"].Position = safe
+		h.handleEliminationCheckPhase(game)
+
+		alice, bob := game.Players["alice"], game.Players["bob"]
+		if snapshot && (alice.IsEliminated || !bob.IsEliminated) {
+			t.Errorf("with the snapshot alice eliminated %v, bob eliminated %v, want only bob", alice.IsEliminated, bob.IsEliminated)
+		}
+		if !snapshot && (!alice.IsEliminated || bob.IsEliminated) {
+			t.Errorf("without the snapshot alice eliminated %v, bob eliminated %v, want only alice", alice.IsEliminated, bob.IsEliminated)
+		}
+	}
+}
+
+// TestUpdatesRacingTheSnapshotAreRejected streams position updates while the
+// color call ends, none may move the player once positions are snapshot. Run
+// with -race.
+func TestUpdatesRacingTheSnapshotAreRejected(t *testing.T) {
+	h, game, clients := startTestGame(t, func(cfg *schema.GameConfig) {
+		cfg.MaxMovementSpeed = 1000 // Only the phase gate stops the updates
+	}, "alice", "bob")
+	alice := clients["alice"]
+	h.startNewRound(game)
+	player := game.Players["alice"]
+	player.SpawnGrace = false
+	player.Position = schema.Position{X: 5, Y: 5}
+	player.LastValidPosition = player.Position
+	start := time.Now()
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for seq := 1; ; seq++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			h.handlePlayerUpdate(game, "alice", playerUpdate(5+float64(seq%2), 5, seq))
+			time.Sleep(100 * time.Microsecond)
+		}
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	game.Mu.Lock()
+	h.finishColorCall(game)
+	snapshot := judgedPosition(game.CurrentRound, player)
+	atSnapshot := player.Position
+	movedInRush := player.LastUpdate.After(start)
+	game.Mu.Unlock()
+	time.Sleep(20 * time.Millisecond)
+	close(stop)
+	<-done
+
+	if !movedInRush {
+		t.Fatal("no update was accepted during the rush")
+	}
+	if snapshot != atSnapshot {
+		t.Errorf("snapshot %+v differs from the position %+v it was taken at", snapshot, atSnapshot)
+	}
+	if player.Position != atSnapshot {
+		t.Errorf("alice moved from %+v to %+v after the snapshot", atSnapshot, player.Position)
+	}
+	locked := 0
+	for _, rejection := range withEvent(received(alice), "update_rejected") {
+		if rejection["reason"] == "movement_locked" && rejection["phase"] == schema.EliminationCheck {
+			locked++
+		}
+	}
+	if locked == 0 {
+		t.Error("no update was rejected during the elimination check")
+	}
+}
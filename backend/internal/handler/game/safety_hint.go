@@ -0,0 +1,46 @@
+package game
+
+import (
+	"time"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+// sendSafetyHints privately tells each player still in the round whether the
+// block they stand on is safe, when SafetyHints is on. A hint goes out at the
+// start of the round and then only when it flips, at most once per
+// SafetyHintIntervalMs. Hints are sent to the player alone, never broadcast,
+// so nobody learns whether others are safe. Callers must hold game.Mu.
+func (h *GameHandler) sendSafetyHints(game *schema.Game) {
+	round := game.CurrentRound
+	if !game.Config.SafetyHints || round == nil {
+		return
+	}
+
+	now := time.Now()
+	interval := time.Duration(game.Config.SafetyHintIntervalMs) * time.Millisecond
+	for _, player := range game.Players {
+		if player.IsEliminated || player.IsSpectator || player.IsDowned {
+			continue
+		}
+
+		// Same block lookup as the elimination check
+		color, inBounds := game.ColorAt(worldToCell(player.Position, game.Config.CellEpsilon))
+		safe := inBounds && color != schema.Air && round.IsSafe(color)
+
+		firstOfRound := player.SafetyHintAt.Before(round.StartTime)
+		if !firstOfRound && (safe == player.SafetyHintSafe || now.Sub(player.SafetyHintAt) < interval) {
+			continue
+		}
+
+		player.SafetyHintAt = now
+		player.SafetyHintSafe = safe
+		sendToClient(game, player.Name, map[string]interface{}{
+			"event": "safety_hint",
+			"data": map[string]interface{}{
+				"round_number": round.Number,
+				"safe":         safe,
+			},
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+func TestSafetyHintFlipsWhenMovingOntoTheColor(t *testing.T) {
+	h, game, clients := startTestGame(t, func(cfg *schema.GameConfig) {
+		cfg.SafetyHints = true
+		cfg.SafetyHintIntervalMs = 0
+	}, "alice", "bob")
+	h.startNewRound(game)
+	round := game.CurrentRound
+	received(clients["alice"])
+	received(clients["bob"])
+	published(game)
+
+	alice := game.Players["alice"]
+	alice.Position = blockWhere(t, game, func(c schema.WoolColor) bool { return c != schema.Air && !round.IsSafe(c) })
+	game.Players["bob"].Position = blockWhere(t, game, round.IsSafe)
+
+	h.sendSafetyHints(game)
+	hints := withEvent(received(clients["alice"]), "safety_hint")
+	if len(hints) != 1 || hints[0]["safe"] != false {
+		t.Fatalf("alice on the wrong color got hints %v, want one unsafe", hints)
+	}
+
+	// Nothing changed, so nothing is repeated
+	h.sendSafetyHints(game)
+	if hints := withEvent(received(clients["alice"]), "safety_hint"); len(hints) != 0 {
+		t.Errorf("unchanged hint sent again: %v", hints)
+	}
+
+	alice.Position = blockWhere(t, game, round.IsSafe)
+	h.sendSafetyHints(game)
+	hints = withEvent(received(clients["alice"]), "safety_hint")
+	if len(hints) != 1 || hints[0]["safe"] != true {
+		t.Errorf("alice on the color got hints %v, want one safe", hints)
+	}
+
+	for _, hint := range withEvent(received(clients["bob"]), "safety_hint") {
+		if hint["safe"] != true {
+			t.Errorf("bob got alice's hint: %v", hint)
+		}
+	}
+	if hints := withEvent(published(game), "safety_hint"); len(hints) != 0 {
+		t.Errorf("safety hints were broadcast: %v", hints)
+	}
+}
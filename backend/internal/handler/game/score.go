@@ -0,0 +1,175 @@
+package game
+
+import (
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+// roundScoreMultiplier returns the multiplier for points earned in the round,
+// LateRoundScoreMultiplier once past LateRoundThreshold and 1 before
+func roundScoreMultiplier(cfg schema.GameConfig, roundNumber int) float64 {
+	if cfg.LateRoundThreshold <= 0 || roundNumber <= cfg.LateRoundThreshold {
+		return 1
+	}
+	return cfg.LateRoundScoreMultiplier
+}
+
+// calculateRoundScores awards SurvivalPointsPerRound to every player who made
+// it through the round cleanly, plus the speed bonus and whatever the game's
+// score hooks grant, all scaled by the round's multiplier so late survival is
+// worth more. Downed players, those who lost a life and those the elimination
+// cap spared earn nothing and lose their streak, and warmup rounds score
+// nobody. Survivors also earn their capped distance points for the round and,
+// when trailing, the catch-up bonus, both unscaled. Survivors' response times
+// are folded into their AverageResponseTime.
+func (h *GameHandler) calculateRoundScores(game *schema.Game, round *schema.Round) {
+	if round.Warmup {
+		return
+	}
+
+	survivors := make([]*schema.Player, 0, len(game.Players))
+	for _, player := range game.Players {
+		if player.IsSpectator {
+			continue
+		}
+		if player.IsEliminated || player.IsDowned || player.LostLifeIn == round.Number || player.SparedIn == round.Number {
+			player.Stats.CurrentStreak = 0
+			continue
+		}
+		survivors = append(survivors, player)
+		recordResponseTime(round, player)
+		player.Stats.CurrentStreak++
+		player.Stats.LongestStreak = max(player.Stats.LongestStreak, player.Stats.CurrentStreak)
+	}
+
+	multiplier := roundScoreMultiplier(game.Config, round.Number)
+	points := int(math.Round(float64(game.Config.SurvivalPointsPerRound) * multiplier))
+	if points > 0 {
+		for _, player := range survivors {
+			player.Stats.SurvivalPoints += points
+		}
+	}
+	awardSpeedBonuses(game, round, survivors, multiplier)
+	for _, player := range survivors {
+		player.Stats.DistancePoints += distancePoints(game.Config, player, round.Number)
+	}
+	awardCatchupBonuses(game, round, survivors)
+
+	h.applyScoreHooks(game, round, survivors, multiplier)
+}
+
+// awardSpeedBonuses gives SpeedBonusPoints, scaled by multiplier, to the
+// survivors who settled on their block within SpeedBonusThreshold seconds of
+// the color call
+func awardSpeedBonuses(game *schema.Game, round *schema.Round, survivors []*schema.Player, multiplier float64) {
+	points := int(math.Round(float64(game.Config.SpeedBonusPoints) * multiplier))
+	if points <= 0 || game.Config.SpeedBonusThreshold <= 0 {
+		return
+	}
+
+	threshold := time.Duration(game.Config.SpeedBonusThreshold * float64(time.Second))
+	for _, player := range survivors {
+		if responseTime(round, player) <= threshold {
+			player.Stats.SpeedBonuses += points
+		}
+	}
+}
+
+// awardCatchupBonuses gives CatchupBonus to the survivors whose score is below
+// the survivors' median, so a runaway leader can be caught. The median is
+// taken after this round's survival and distance points; with an even number
+// of survivors it is the mean of the middle two.
+func awardCatchupBonuses(game *schema.Game, round *schema.Round, survivors []*schema.Player) {
+	if game.Config.CatchupBonus <= 0 || len(survivors) < 2 {
+		return
+	}
+
+	scores := make([]int, len(survivors))
+	for i, player := range survivors {
+		scores[i] = totalScore(player)
+	}
+	sort.Ints(scores)
+	mid := len(scores) / 2
+	median := float64(scores[mid])
+	if len(scores)%2 == 0 {
+		median = float64(scores[mid-1]+scores[mid]) / 2
+	}
+
+	for _, player := range survivors {
+		if float64(totalScore(player)) >= median {
+			continue
+		}
+		player.Stats.CatchupBonuses += game.Config.CatchupBonus
+		log.Printf("Player %s is below the median score %.1f in round %d of game %s, catch-up bonus %d",
+			player.Name, median, round.Number, game.ID, game.Config.CatchupBonus)
+	}
+}
+
+// totalScore is the points a player earned over the rounds so far
+func totalScore(player *schema.Player) int {
+	score := player.Stats.SurvivalPoints + player.Stats.DistancePoints + player.Stats.CatchupBonuses + player.Stats.SpeedBonuses
+	for _, points := range player.Stats.HookBonuses {
+		score += points
+	}
+	return score
+}
+
+// finalScore is the player's score once the game ended, the round points plus
+// the elimination and winner bonuses awarded at settlement
+func finalScore(player *schema.Player) int {
+	return totalScore(player) + player.Stats.EliminationBonus + player.Stats.WinnerBonus
+}
+
+// scoreLeaders returns the survivors with the highest score once it reaches
+// ScoreToWin, nil if nobody has or the game isn't a score race. The other
+// survivors are ranked behind them by score.
+func scoreLeaders(game *schema.Game) []*schema.Player {
+	if game.Config.ScoreToWin <= 0 {
+		return nil
+	}
+
+	best := 0
+	for _, player := range game.Players {
+		if !player.IsEliminated && !player.IsSpectator {
+			best = max(best, totalScore(player))
+		}
+	}
+	if best < game.Config.ScoreToWin {
+		return nil
+	}
+
+	leaders := rankSurvivorsByScore(game)
+	log.Printf("Game %s: %d player(s) reached %d points to win", game.ID, len(leaders), best)
+	return leaders
+}
+
+// rankSurvivorsByScore ranks the players still standing by their score and
+// returns the top scorers, who share first place
+func rankSurvivorsByScore(game *schema.Game) []*schema.Player {
+	survivors := make([]*schema.Player, 0, len(game.Players))
+	for _, player := range game.Players {
+		if !player.IsEliminated && !player.IsSpectator {
+			survivors = append(survivors, player)
+		}
+	}
+
+	leaders := make([]*schema.Player, 0, 1)
+	for _, player := range survivors {
+		// Everyone who outscored the player ranks ahead of them
+		ahead := 0
+		for _, other := range survivors {
+			if totalScore(other) > totalScore(player) {
+				ahead++
+			}
+		}
+		player.Stats.FinalPosition = ahead
+		if ahead == 0 {
+			leaders = append(leaders, player)
+		}
+	}
+	return leaders
+}
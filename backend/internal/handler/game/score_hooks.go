@@ -0,0 +1,89 @@
+package game
+
+import (
+	"log"
+	"math"
+	"sort"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+// ScoreHook grants custom bonus points at the end of a round. It is called once
+// per survivor with every survivor of the round, and returns the points to
+// award and the label they are listed under in the player's breakdown.
+// Returning zero points awards nothing.
+type ScoreHook func(game *schema.Game, round *schema.Round, player *schema.Player, survivors []*schema.Player) (points int, label string)
+
+// scoreHooks are the hooks games can select by name in GameConfig.ScoreHooks
+var scoreHooks = map[string]ScoreHook{
+	"comeback": comebackBonus,
+	"streak":   streakBonus,
+}
+
+// RegisterScoreHook makes a hook selectable by name in GameConfig.ScoreHooks.
+// It is meant to be called at startup, before any game is created.
+func RegisterScoreHook(name string, hook ScoreHook) {
+	scoreHooks[name] = hook
+}
+
+// ScoreHookNames lists the registered hooks, sorted
+func ScoreHookNames() []string {
+	names := make([]string, 0, len(scoreHooks))
+	for name := range scoreHooks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// applyScoreHooks runs the game's selected hooks for every survivor of the
+// round and adds their points, scaled by multiplier, to the player's breakdown
+func (h *GameHandler) applyScoreHooks(game *schema.Game, round *schema.Round, survivors []*schema.Player, multiplier float64) {
+	for _, name := range game.Config.ScoreHooks {
+		hook, exists := scoreHooks[name]
+		if !exists {
+			continue
+		}
+
+		for _, player := range survivors {
+			points, label := hook(game, round, player, survivors)
+			points = int(math.Round(float64(points) * multiplier))
+			if points == 0 {
+				continue
+			}
+			if label == "" {
+				label = name
+			}
+
+			if player.Stats.HookBonuses == nil {
+				player.Stats.HookBonuses = make(map[string]int)
+			}
+			player.Stats.HookBonuses[label] += points
+			log.Printf("Score hook %s awarded %d points (%s) to %s in round %d of game %s",
+				name, points, label, player.Name, round.Number, game.ID)
+		}
+	}
+}
+
+// comebackBonus gives half a round's survival points to the survivors with the
+// fewest points, as long as someone is ahead of them, so trailing players can
+// catch up
+func comebackBonus(game *schema.Game, round *schema.Round, player *schema.Player, survivors []*schema.Player) (int, string) {
+	lowest, highest := player.Stats.SurvivalPoints, player.Stats.SurvivalPoints
+	for _, survivor := range survivors {
+		lowest = min(lowest, survivor.Stats.SurvivalPoints)
+		highest = max(highest, survivor.Stats.SurvivalPoints)
+	}
+
+	if player.Stats.SurvivalPoints > lowest || lowest == highest {
+		return 0, ""
+	}
+	return game.Config.SurvivalPointsPerRound / 2, "comeback"
+}
+
+// streakBonus pays the game's StreakBonuses when a player reaches one of its
+// counts of rounds survived in a row
+func streakBonus(game *schema.Game, round *schema.Round, player *schema.Player, survivors []*schema.Player) (int, string) {
+	survived := round.Number - roundsCountedFrom(player) + 1 - player.Stats.DownedRounds
+	return game.Config.StreakBonuses[survived], "streak"
+}
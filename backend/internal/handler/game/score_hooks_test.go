@@ -0,0 +1,55 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+func TestRegisteredScoreHookAddsItsBonus(t *testing.T) {
+	RegisterScoreHook("test_flat", func(game *schema.Game, round *schema.Round, player *schema.Player, survivors []*schema.Player) (int, string) {
+		return 5, "flat bonus"
+	})
+	t.Cleanup(func() { delete(scoreHooks, "test_flat") })
+
+	h, game := newTestGame(t, func(cfg *schema.GameConfig) {
+		cfg.ScoreHooks = []string{"test_flat", "missing"}
+		cfg.LateRoundThreshold = 0
+	})
+	joinTestPlayers(t, h, game, "alice", "bob")
+	game.Players["bob"].IsEliminated = true
+	alice := game.Players["alice"]
+	before := totalScore(alice)
+
+	h.calculateRoundScores(game, &schema.Round{Number: 1, StartTime: time.Now()})
+
+	if got := alice.Stats.HookBonuses["flat bonus"]; got != 5 {
+		t.Errorf("alice's flat bonus = %d, want 5", got)
+	}
+	if got := totalScore(alice) - before - alice.Stats.SurvivalPoints - alice.Stats.DistancePoints; got != 5 {
+		t.Errorf("hook added %d to alice's score, want 5", got)
+	}
+	if bonuses := game.Players["bob"].Stats.HookBonuses; len(bonuses) != 0 {
+		t.Errorf("eliminated bob got hook bonuses %v", bonuses)
+	}
+}
+
+func TestComebackHookPaysTheTrailingSurvivor(t *testing.T) {
+	h, game := newTestGame(t, func(cfg *schema.GameConfig) {
+		cfg.ScoreHooks = []string{"comeback"}
+		cfg.SurvivalPointsPerRound = 10
+		cfg.LateRoundThreshold = 0
+	})
+	joinTestPlayers(t, h, game, "alice", "bob")
+	game.Players["bob"].Stats.SurvivalPoints = 20
+
+	h.calculateRoundScores(game, &schema.Round{Number: 3, StartTime: time.Now()})
+
+	if got := game.Players["alice"].Stats.HookBonuses["comeback"]; got != 5 {
+		t.Errorf("trailing alice's comeback bonus = %d, want 5", got)
+	}
+	if got := game.Players["bob"].Stats.HookBonuses["comeback"]; got != 0 {
+		t.Errorf("leading bob's comeback bonus = %d, want 0", got)
+	}
+}
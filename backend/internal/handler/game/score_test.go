@@ -0,0 +1,202 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+func TestSpeedBonusAndStreaks(t *testing.T) {
+	h, game := newTestGame(t, func(cfg *schema.GameConfig) {
+		cfg.SpeedBonusThreshold = 1
+		cfg.SpeedBonusPoints = 2
+		cfg.LateRoundThreshold = 0
+	})
+	joinTestPlayers(t, h, game, "quick", "slow", "unlucky")
+	quick, slow, unlucky := game.Players["quick"], game.Players["slow"], game.Players["unlucky"]
+
+	start := time.Now()
+	for number := 1; number <= 3; number++ {
+		round := &schema.Round{Number: number, StartTime: start}
+		quick.SettledAt = start.Add(500 * time.Millisecond)
+		slow.SettledAt = start.Add(2 * time.Second)
+		unlucky.SettledAt = start
+		if number == 2 {
+			unlucky.LostLifeIn = number
+		}
+		h.calculateRoundScores(game, round)
+	}
+
+	if quick.Stats.SpeedBonuses != 6 {
+		t.Errorf("quick speed bonuses = %d, want 6", quick.Stats.SpeedBonuses)
+	}
+	if slow.Stats.SpeedBonuses != 0 {
+		t.Errorf("slow speed bonuses = %d, want 0", slow.Stats.SpeedBonuses)
+	}
+	if got := totalScore(quick) - totalScore(slow); got != 6 {
+		t.Errorf("quick leads slow by %d, want the 6 speed points", got)
+	}
+
+	if quick.Stats.LongestStreak != 3 || quick.Stats.CurrentStreak != 3 {
+		t.Errorf("quick streak = %d/%d, want 3/3", quick.Stats.CurrentStreak, quick.Stats.LongestStreak)
+	}
+	if unlucky.Stats.LongestStreak != 1 || unlucky.Stats.CurrentStreak != 1 {
+		t.Errorf("unlucky streak = %d/%d, want 1 current and 1 longest", unlucky.Stats.CurrentStreak, unlucky.Stats.LongestStreak)
+	}
+}
+
+func TestLateRoundPointsAreMultiplied(t *testing.T) {
+	h, game := newTestGame(t, func(cfg *schema.GameConfig) {
+		cfg.SurvivalPointsPerRound = 10
+		cfg.SpeedBonusThreshold = 1
+		cfg.SpeedBonusPoints = 2
+		cfg.LateRoundThreshold = 2
+		cfg.LateRoundScoreMultiplier = 3
+		cfg.CatchupBonus = 0
+	})
+	joinTestPlayers(t, h, game, "alice")
+	alice := game.Players["alice"]
+
+	start := time.Now()
+	wantSurvival := []int{10, 20, 50}
+	wantSpeed := []int{2, 4, 10}
+	for number := 1; number <= 3; number++ {
+		alice.SettledAt = start
+		h.calculateRoundScores(game, &schema.Round{Number: number, StartTime: start})
+
+		if got := alice.Stats.SurvivalPoints; got != wantSurvival[number-1] {
+			t.Errorf("survival points after round %d = %d, want %d", number, got, wantSurvival[number-1])
+		}
+		if got := alice.Stats.SpeedBonuses; got != wantSpeed[number-1] {
+			t.Errorf("speed bonuses after round %d = %d, want %d", number, got, wantSpeed[number-1])
+		}
+	}
+}
+
+func TestReachingScoreToWinEndsTheGame(t *testing.T) {
+	h, game, _ := startTestGame(t, func(cfg *schema.GameConfig) {
+		cfg.ScoreToWin = 30
+		cfg.SurvivalPointsPerRound = 10
+		cfg.LateRoundThreshold = 0
+		cfg.CatchupBonus = 0
+	}, "alice", "bob", "carol")
+	game.Players["alice"].Stats.SurvivalPoints = 15
+
+	// 25 points, not there yet
+	judgeRound(t, h, game)
+	if game.Phase != schema.InGame {
+		t.Fatalf("game in %s after alice reached %d points", game.Phase, totalScore(game.Players["alice"]))
+	}
+	published(game)
+	game.CurrentRound = nil
+
+	judgeRound(t, h, game)
+	if game.Phase != schema.Settlement {
+		t.Fatalf("game in %s after alice reached %d points", game.Phase, totalScore(game.Players["alice"]))
+	}
+	announcements := withEvent(published(game), "winner_announced")
+	if len(announcements) != 1 || announcements[0]["victory_type"] != VictoryScore {
+		t.Fatalf("winner_announced = %v, want a score victory", announcements)
+	}
+	winners := announcements[0]["winners"].([]map[string]any)
+	if len(winners) != 1 || winners[0]["name"] != "alice" {
+		t.Errorf("winners = %v, want alice", winners)
+	}
+	for _, name := range []string{"bob", "carol"} {
+		if game.Players[name].IsEliminated {
+			t.Errorf("%s was eliminated", name)
+		}
+	}
+}
+
+func TestCatchupBonusGoesBelowTheMedian(t *testing.T) {
+	tests := []struct {
+		name   string
+		scores map[string]int
+		bonus  map[string]int
+	}{
+		{
+			name:   "even survivors",
+			scores: map[string]int{"last": 0, "third": 10, "second": 20, "first": 30},
+			bonus:  map[string]int{"last": 3, "third": 3},
+		},
+		{
+			name:   "odd survivors",
+			scores: map[string]int{"last": 0, "middle": 10, "first": 20},
+			bonus:  map[string]int{"last": 3},
+		},
+		{
+			name:   "tied survivors",
+			scores: map[string]int{"a": 10, "b": 10, "c": 10},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, game := newTestGame(t, func(cfg *schema.GameConfig) {
+				cfg.CatchupBonus = 3
+				cfg.SurvivalPointsPerRound = 10
+				cfg.SpeedBonusPoints = 0
+				cfg.DistancePointsPer = 0
+				cfg.LateRoundThreshold = 0
+			})
+			names := make([]string, 0, len(tt.scores))
+			for name := range tt.scores {
+				names = append(names, name)
+			}
+			joinTestPlayers(t, h, game, names...)
+			for name, score := range tt.scores {
+				game.Players[name].Stats.SurvivalPoints = score
+			}
+
+			h.calculateRoundScores(game, &schema.Round{Number: 1, StartTime: time.Now()})
+
+			for name, score := range tt.scores {
+				player := game.Players[name]
+				if player.Stats.CatchupBonuses != tt.bonus[name] {
+					t.Errorf("%s with %d points got a catch-up bonus of %d, want %d", name, score, player.Stats.CatchupBonuses, tt.bonus[name])
+				}
+				if want := score + 10 + tt.bonus[name]; totalScore(player) != want {
+					t.Errorf("%s scored %d, want %d", name, totalScore(player), want)
+				}
+			}
+		})
+	}
+}
+
+func TestCatchupBonusOnlyForSurvivors(t *testing.T) {
+	h, game := newTestGame(t, func(cfg *schema.GameConfig) {
+		cfg.CatchupBonus = 3
+		cfg.SurvivalPointsPerRound = 10
+	})
+	joinTestPlayers(t, h, game, "trailing", "hit", "leader")
+	game.Players["hit"].LostLifeIn = 1
+	game.Players["leader"].Stats.SurvivalPoints = 50
+
+	h.calculateRoundScores(game, &schema.Round{Number: 1, StartTime: time.Now()})
+
+	if got := game.Players["hit"].Stats.CatchupBonuses; got != 0 {
+		t.Errorf("a player who lost a life got a catch-up bonus of %d", got)
+	}
+	if got := game.Players["trailing"].Stats.CatchupBonuses; got != 3 {
+		t.Errorf("trailing survivor got a catch-up bonus of %d, want 3", got)
+	}
+	if got := game.Players["leader"].Stats.CatchupBonuses; got != 0 {
+		t.Errorf("leader got a catch-up bonus of %d", got)
+	}
+}
+
+func TestNoCatchupBonusByDefault(t *testing.T) {
+	h, game := newTestGame(t, nil)
+	joinTestPlayers(t, h, game, "last", "middle", "first")
+	game.Players["middle"].Stats.SurvivalPoints = 10
+	game.Players["first"].Stats.SurvivalPoints = 20
+
+	h.calculateRoundScores(game, &schema.Round{Number: 1, StartTime: time.Now()})
+
+	for name, player := range game.Players {
+		if player.Stats.CatchupBonuses != 0 {
+			t.Errorf("%s got a catch-up bonus of %d with none configured", name, player.Stats.CatchupBonuses)
+		}
+	}
+}
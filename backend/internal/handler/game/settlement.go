@@ -1 +1,202 @@
 package game
+
+import (
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+// VictoryType describes how the winners of a game were decided
+type VictoryType string
+
+const (
+	VictorySolo       VictoryType = "solo"       // One player left standing
+	VictoryTiebreaker VictoryType = "tiebreaker" // Everyone left fell together, the earliest joiner won
+	VictoryShared     VictoryType = "shared"     // Everyone left fell together and tied on the tiebreak, or the game ended early
+	VictoryScore      VictoryType = "score"      // A player reached ScoreToWin points
+	VictoryTimeLimit  VictoryType = "time_limit" // The game ran past MaxGameDurationSeconds, the top scorers won
+	VictoryNone       VictoryType = "none"       // No one played to the end
+)
+
+// determineWinner returns the winners and how they won. lastEliminated are the
+// players eliminated in the final check, who share the win if no one survived
+// it, with ties broken in favor of the player who joined earlier.
+func (h *GameHandler) determineWinner(game *schema.Game, lastEliminated []*schema.Player) ([]*schema.Player, VictoryType) {
+	survivors := make([]*schema.Player, 0, 1)
+	for _, player := range game.Players {
+		if !player.IsEliminated && !player.IsSpectator {
+			survivors = append(survivors, player)
+		}
+	}
+	switch {
+	case len(survivors) == 1:
+		return survivors, VictorySolo
+	case len(survivors) > 1:
+		// The game was cut short with several players still standing
+		return survivors, VictoryShared
+	}
+
+	if len(lastEliminated) == 0 {
+		return nil, VictoryNone
+	}
+
+	earliest := lastEliminated[0].JoinedRound
+	for _, player := range lastEliminated {
+		if player.JoinedRound < earliest {
+			earliest = player.JoinedRound
+		}
+	}
+	winners := make([]*schema.Player, 0, len(lastEliminated))
+	for _, player := range lastEliminated {
+		if player.JoinedRound == earliest {
+			winners = append(winners, player)
+		}
+	}
+
+	switch {
+	case len(lastEliminated) == 1:
+		return winners, VictorySolo
+	case len(winners) == 1:
+		return winners, VictoryTiebreaker
+	default:
+		return winners, VictoryShared
+	}
+}
+
+// endGame decides the winners, announces them and moves the game to settlement
+func (h *GameHandler) endGame(game *schema.Game, now time.Time, lastEliminated []*schema.Player) {
+	winners, victoryType := h.determineWinner(game, lastEliminated)
+	h.finishGame(game, now, winners, victoryType)
+}
+
+// finishGame announces the given winners and moves the game to settlement
+func (h *GameHandler) finishGame(game *schema.Game, now time.Time, winners []*schema.Player, victoryType VictoryType) {
+	rankWinners(game, winners)
+	h.awardEliminationBonuses(game)
+
+	winnerNames := make([]string, 0, len(winners))
+	winnerDetails := make([]map[string]any, 0, len(winners))
+	for _, winner := range winners {
+		// Survivors are never eliminated, so their stats are settled here
+		if !winner.IsEliminated {
+			winner.Stats.RoundsSurvived = game.RoundNumber - roundsCountedFrom(winner) + 1 - winner.Stats.DownedRounds
+		}
+
+		winnerNames = append(winnerNames, winner.Name)
+		winnerDetails = append(winnerDetails, map[string]any{
+			"name":              winner.Name,
+			"rounds_survived":   winner.Stats.RoundsSurvived,
+			"joined_round":      winner.JoinedRound,
+			"elimination_bonus": winner.Stats.EliminationBonus,
+			"winner_bonus":      winner.Stats.WinnerBonus,
+			"survival_points":   winner.Stats.SurvivalPoints,
+			"hook_bonuses":      winner.Stats.HookBonuses,
+			"distance_points":   winner.Stats.DistancePoints,
+			"catchup_bonuses":   winner.Stats.CatchupBonuses,
+		})
+	}
+
+	// Announced the same way however the game ended, even with nobody left to win
+	standings := finalStandings(game)
+	game.FinalResults = map[string]any{
+		"winners":          winnerDetails,
+		"no_winner":        len(winners) == 0,
+		"standings":        standings,
+		"victory_type":     victoryType,
+		"total_rounds":     game.RoundNumber,
+		"deadliest_colors": deadliestColors(game),
+	}
+	game.Publish(map[string]any{
+		"event": "winner_announced",
+		"data":  game.FinalResults,
+	})
+
+	game.Phase = schema.Settlement
+	game.EndedAt = &now
+
+	var winnerID string
+	if len(winnerNames) > 0 {
+		winnerID = winnerNames[0]
+	}
+
+	game.Publish(map[string]any{
+		"event": "game_update",
+		"data": map[string]any{
+			"winner_id":    winnerID,
+			"end_time":     now,
+			"total_rounds": game.RoundNumber,
+			"alive_count":  game.AliveCount,
+		},
+	})
+
+	var duration float64
+	if game.StartedAt != nil {
+		duration = now.Sub(*game.StartedAt).Seconds()
+	}
+	game.Publish(map[string]any{
+		"event": "game_ended",
+		"data": map[string]any{
+			"game_id":      game.ID,
+			"winner_id":    winnerID,
+			"victory_type": victoryType,
+			"total_rounds": game.RoundNumber,
+			"duration":     duration,
+			"standings":    standings,
+		},
+	})
+
+	h.notifyWebhook(game, "game_ended", map[string]any{
+		"winners":      winnerNames,
+		"victory_type": victoryType,
+		"total_rounds": game.RoundNumber,
+	})
+
+	log.Printf("Game %s ended after %d rounds with winners: %v (%s)", game.ID, game.RoundNumber, winnerNames, victoryType)
+}
+
+// rankWinners puts every winner in first place and awards each the winner
+// bonus, so players sharing the win tie at 1 and the next player ranks after
+// all of them (1, 1, 3, ...)
+func rankWinners(game *schema.Game, winners []*schema.Player) {
+	for _, winner := range winners {
+		winner.Stats.FinalPosition = 0
+		winner.Stats.WinnerBonus = game.Config.FinalWinnerBonus
+	}
+}
+
+// finalStandings ranks every player who played, best placement first. Players
+// tied on placement, like those sharing a win or eliminated together in a game
+// nobody won, are ordered by join round and then name so every client shows
+// the same list.
+func finalStandings(game *schema.Game) []map[string]any {
+	players := make([]*schema.Player, 0, len(game.Players))
+	for _, player := range game.Players {
+		if !player.IsSpectator {
+			players = append(players, player)
+		}
+	}
+	sort.Slice(players, func(i, j int) bool {
+		if placement(players[i]) != placement(players[j]) {
+			return placement(players[i]) < placement(players[j])
+		}
+		if players[i].JoinedRound != players[j].JoinedRound {
+			return players[i].JoinedRound < players[j].JoinedRound
+		}
+		return players[i].Name < players[j].Name
+	})
+
+	standings := make([]map[string]any, 0, len(players))
+	for _, player := range players {
+		standings = append(standings, map[string]any{
+			"name":            player.Name,
+			"placement":       placement(player),
+			"rounds_survived": player.Stats.RoundsSurvived,
+			"is_eliminated":   player.IsEliminated,
+			"total_distance":  math.Round(player.Stats.TotalDistance*10) / 10,
+		})
+	}
+	return standings
+}
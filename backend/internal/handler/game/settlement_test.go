@@ -0,0 +1,161 @@
+package game
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+func TestWinnerAnnouncedVictoryType(t *testing.T) {
+	tests := []struct {
+		name           string
+		eliminated     []string // Eliminated before the final check
+		lastEliminated []string // Eliminated together in the final check
+		lateJoiner     string
+		want           VictoryType
+		winners        int
+	}{
+		{"solo", []string{"bob"}, []string{"carol"}, "", VictorySolo, 1},
+		{"shared", []string{"carol"}, []string{"alice", "bob"}, "", VictoryShared, 2},
+		{"tiebreaker", []string{"carol"}, []string{"alice", "bob"}, "bob", VictoryTiebreaker, 1},
+	}
+
+	for _, tt := range tests {
+		h, game, _ := startTestGame(t, nil, "alice", "bob", "carol")
+		game.RoundNumber = 4
+		if tt.lateJoiner != "" {
+			game.Players[tt.lateJoiner].JoinedRound = 2
+		}
+		var lastEliminated []*schema.Player
+		for _, name := range append(tt.eliminated, tt.lastEliminated...) {
+			game.Players[name].IsEliminated = true
+		}
+		for _, name := range tt.lastEliminated {
+			lastEliminated = append(lastEliminated, game.Players[name])
+		}
+		published(game)
+
+		h.endGame(game, time.Now(), lastEliminated)
+
+		messages := published(game)
+		announcements := withEvent(messages, "winner_announced")
+		if len(announcements) != 1 {
+			t.Fatalf("%s: got %d winner_announced events, want 1", tt.name, len(announcements))
+		}
+		if messages[0]["event"] != "winner_announced" {
+			t.Errorf("%s: %v sent before winner_announced", tt.name, messages[0]["event"])
+		}
+		if got := announcements[0]["victory_type"]; got != tt.want {
+			t.Errorf("%s: victory type %v, want %s", tt.name, got, tt.want)
+		}
+		winners := announcements[0]["winners"].([]map[string]any)
+		if len(winners) != tt.winners {
+			t.Errorf("%s: %d winners, want %d", tt.name, len(winners), tt.winners)
+		}
+		if len(winners) > 0 && tt.winners == 1 && winners[0]["name"] != "alice" {
+			t.Errorf("%s: %v won, want alice", tt.name, winners[0]["name"])
+		}
+		for _, winner := range winners {
+			if winner["winner_bonus"] != game.Config.FinalWinnerBonus {
+				t.Errorf("%s: %s got winner bonus %v", tt.name, winner["name"], winner["winner_bonus"])
+			}
+		}
+		if game.Phase != schema.Settlement {
+			t.Errorf("%s: phase %s, want settlement", tt.name, game.Phase)
+		}
+	}
+}
+
+func TestTiedWinnersShareFirstPlace(t *testing.T) {
+	h, game, _ := startTestGame(t, nil, "alice", "bob", "carol")
+	game.RoundNumber = 4
+	carol := game.Players["carol"]
+	carol.IsEliminated = true
+	carol.Stats.FinalPosition = 2
+	alice, bob := game.Players["alice"], game.Players["bob"]
+	alice.IsEliminated = true
+	bob.IsEliminated = true
+
+	h.endGame(game, time.Now(), []*schema.Player{alice, bob})
+
+	for _, player := range []*schema.Player{alice, bob} {
+		if placement(player) != 1 || player.Stats.WinnerBonus != game.Config.FinalWinnerBonus {
+			t.Errorf("%s placed %d with winner bonus %d, want 1 and %d",
+				player.Name, placement(player), player.Stats.WinnerBonus, game.Config.FinalWinnerBonus)
+		}
+	}
+	if placement(carol) != 3 || carol.Stats.WinnerBonus != 0 {
+		t.Errorf("carol placed %d with winner bonus %d, want 3 and none", placement(carol), carol.Stats.WinnerBonus)
+	}
+
+	var placements []any
+	for _, standing := range game.FinalResults["standings"].([]map[string]any) {
+		placements = append(placements, standing["placement"])
+	}
+	if len(placements) != 3 || placements[0] != 1 || placements[1] != 1 || placements[2] != 3 {
+		t.Errorf("standings placed %v, want [1 1 3]", placements)
+	}
+}
+
+func TestGameEndsWithOneOrNoSurvivors(t *testing.T) {
+	tests := []struct {
+		name    string
+		wrong   []string
+		want    VictoryType
+		winners []string
+	}{
+		{"one survivor", []string{"bob", "carol"}, VictorySolo, []string{"alice"}},
+		{"nobody survives", []string{"alice", "bob", "carol"}, VictoryShared, []string{"alice", "bob", "carol"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, game, _ := startTestGame(t, nil, "alice", "bob", "carol")
+
+			judgeRound(t, h, game, tt.wrong...)
+
+			if game.Phase != schema.Settlement {
+				t.Fatalf("phase %s, want settlement", game.Phase)
+			}
+			messages := published(game)
+			announcements := withEvent(messages, "winner_announced")
+			if len(announcements) != 1 || len(withEvent(messages, "game_ended")) != 1 {
+				t.Fatalf("got %d winner_announced and %d game_ended, want one each",
+					len(announcements), len(withEvent(messages, "game_ended")))
+			}
+			results := announcements[0]
+			if results["victory_type"] != tt.want || results["no_winner"] != false {
+				t.Errorf("victory type %v with no_winner %v, want %s with a winner", results["victory_type"], results["no_winner"], tt.want)
+			}
+			var names []string
+			for _, winner := range results["winners"].([]map[string]any) {
+				names = append(names, winner["name"].(string))
+			}
+			sort.Strings(names)
+			if strings.Join(names, ",") != strings.Join(tt.winners, ",") {
+				t.Errorf("winners %v, want %v", names, tt.winners)
+			}
+			for _, standing := range results["standings"].([]map[string]any) {
+				winner := slices.Contains(tt.winners, standing["name"].(string))
+				if (standing["placement"] == 1) != winner {
+					t.Errorf("%v placed %v", standing["name"], standing["placement"])
+				}
+			}
+		})
+	}
+}
+
+func TestGameWithoutPlayersEndsWithNoWinner(t *testing.T) {
+	h, game, _ := startTestGame(t, nil)
+
+	h.endGame(game, time.Now(), nil)
+
+	announcements := withEvent(published(game), "winner_announced")
+	if len(announcements) != 1 || announcements[0]["no_winner"] != true || announcements[0]["victory_type"] != VictoryNone {
+		t.Errorf("winner_announced = %v, want one with no winner", announcements)
+	}
+}
@@ -0,0 +1,37 @@
+package game
+
+import (
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+// Published messages are marshaled by the client writers without the game's
+// lock, so they carry copies of the state the game loop keeps changing.
+
+// roundForClients returns a copy of the current round as it may be shown to
+// clients, nil between rounds
+func roundForClients(game *schema.Game) any {
+	round := game.CurrentRound.Clone()
+	if round == nil || !hidesSafeColors(game) {
+		return round
+	}
+	return &hardModeRound{Round: round, UnsafeColors: unsafeColors(round)}
+}
+
+// countdownSnapshot returns a copy of the game's countdown, nil without one
+func countdownSnapshot(game *schema.Game) *float64 {
+	if game.Countdown == nil {
+		return nil
+	}
+	countdown := *game.Countdown
+	return &countdown
+}
+
+// playersSnapshot returns copies of the players, whose positions and flags
+// the loop and movement updates keep changing
+func playersSnapshot(players []*schema.Player) []schema.Player {
+	snapshot := make([]schema.Player, 0, len(players))
+	for _, player := range players {
+		snapshot = append(snapshot, *player)
+	}
+	return snapshot
+}
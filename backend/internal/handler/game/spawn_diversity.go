@@ -0,0 +1,83 @@
+package game
+
+import (
+	"log"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+// guaranteeSpawnDiversity recolors blocks around every player still in the
+// game so that each one has at least SpawnDiversityColors distinct colors
+// within SpawnDiversityRadius blocks of where the round starts them, and no
+// called color leaves them without a reachable safe block. Only colors that
+// repeat within the area are recolored, Air stays Air, and on a symmetric map
+// the recolored block's symmetric blocks follow. Players close together share
+// blocks, so a later pass can take a color from an earlier one's area.
+func (h *GameHandler) guaranteeSpawnDiversity(game *schema.Game) {
+	if !game.Config.GuaranteeSpawnDiversity {
+		return
+	}
+
+	recolored := 0
+	for _, player := range game.PlayersList {
+		if player.IsEliminated || player.IsSpectator || player.IsDowned {
+			continue
+		}
+		x, y := worldToCell(player.Position, game.Config.CellEpsilon)
+		recolored += h.diversifyAround(game, x, y)
+	}
+	if recolored > 0 {
+		h.syncMapArray(game)
+		log.Printf("Recolored %d blocks around the spawns of game %s", recolored, game.ID)
+	}
+}
+
+// diversifyAround recolors repeated colors within SpawnDiversityRadius of x, y
+// until the area has SpawnDiversityColors distinct colors or no repeats are
+// left, returning the number of blocks recolored
+func (h *GameHandler) diversifyAround(game *schema.Game, x, y int) int {
+	radius := game.Config.SpawnDiversityRadius
+	counts := make(map[schema.WoolColor]int)
+	var area []cell
+	for cy := y - radius; cy <= y+radius; cy++ {
+		for cx := x - radius; cx <= x+radius; cx++ {
+			if !spawnable(game, cx, cy) {
+				continue
+			}
+			color, _ := game.ColorAt(cx, cy)
+			counts[color]++
+			area = append(area, cell{cx, cy})
+		}
+	}
+
+	game.Rand.Shuffle(len(area), func(i, j int) {
+		area[i], area[j] = area[j], area[i]
+	})
+
+	recolored := 0
+	for _, c := range area {
+		if len(counts) >= game.Config.SpawnDiversityColors {
+			break
+		}
+		color, _ := game.ColorAt(c.x, c.y)
+		if counts[color] < 2 {
+			continue
+		}
+
+		missing := make([]schema.WoolColor, 0, schema.Air)
+		for candidate := schema.White; candidate < schema.Air; candidate++ {
+			if counts[candidate] == 0 {
+				missing = append(missing, candidate)
+			}
+		}
+		replacement := missing[game.Rand.Intn(len(missing))]
+
+		counts[color]--
+		counts[replacement]++
+		for _, sc := range symmetricCells(game, c.x, c.y) {
+			game.SetColorAt(sc.x, sc.y, replacement)
+		}
+		recolored++
+	}
+	return recolored
+}
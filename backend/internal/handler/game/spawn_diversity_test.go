@@ -0,0 +1,88 @@
+package game
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+// colorsAround counts the distinct colors of the spawnable blocks within radius of the player's block
+func colorsAround(game *schema.Game, player *schema.Player, radius int) int {
+	x, y := worldToCell(player.Position, game.Config.CellEpsilon)
+	colors := make(map[schema.WoolColor]bool)
+	for cy := y - radius; cy <= y+radius; cy++ {
+		for cx := x - radius; cx <= x+radius; cx++ {
+			if spawnable(game, cx, cy) {
+				color, _ := game.ColorAt(cx, cy)
+				colors[color] = true
+			}
+		}
+	}
+	return len(colors)
+}
+
+func TestEverySpawnHasDiverseColorsAround(t *testing.T) {
+	tests := []struct {
+		name      string
+		symmetry  schema.MapSymmetry
+		radius    int
+		minColors int
+	}{
+		{name: "radius 1", radius: 1, minColors: 4},
+		{name: "radius 2", radius: 2, minColors: 8},
+		{name: "mirror", symmetry: schema.MapSymmetryMirror, radius: 1, minColors: 4},
+	}
+	for _, tt := range tests {
+		for seed := int64(1); seed <= 5; seed++ {
+			t.Run(fmt.Sprintf("%s seed %d", tt.name, seed), func(t *testing.T) {
+				h, game := newTestGame(t, func(cfg *schema.GameConfig) {
+					cfg.MapSeed = seed
+					cfg.SymmetricMap = tt.symmetry
+					cfg.SpawnMode = schema.SpawnSpread
+					cfg.GuaranteeSpawnDiversity = true
+					cfg.SpawnDiversityRadius = tt.radius
+					cfg.SpawnDiversityColors = tt.minColors
+				})
+				joinTestPlayers(t, h, game, "p1", "p2", "p3", "p4")
+				h.startGame(game)
+
+				// Worst case: a map of a single color
+				fillMap(game, schema.Red)
+				h.guaranteeSpawnDiversity(game)
+				for _, player := range game.Players {
+					if n := colorsAround(game, player, tt.radius); n < tt.minColors {
+						t.Errorf("%s has %d colors within %d blocks, want at least %d", player.Name, n, tt.radius, tt.minColors)
+					}
+				}
+				if tt.symmetry != schema.MapSymmetryNone {
+					assertSymmetric(t, game, "diversified map")
+				}
+
+				// And on the rounds' generated maps
+				for round := 1; round <= 3; round++ {
+					h.startNewRound(game)
+					for _, player := range game.Players {
+						if n := colorsAround(game, player, tt.radius); n < tt.minColors {
+							t.Errorf("round %d: %s has %d colors within %d blocks, want at least %d", round, player.Name, n, tt.radius, tt.minColors)
+						}
+					}
+					game.CurrentRound = nil
+				}
+			})
+		}
+	}
+}
+
+func TestSpawnDiversityIsOffByDefault(t *testing.T) {
+	h, game := newTestGame(t, nil)
+	joinTestPlayers(t, h, game, "p1", "p2")
+	h.startGame(game)
+	fillMap(game, schema.Red)
+	h.guaranteeSpawnDiversity(game)
+	for _, player := range game.Players {
+		if n := colorsAround(game, player, 1); n != 1 {
+			t.Errorf("%s has %d colors around on a red map", player.Name, n)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package game
+
+import (
+	"log"
+	"math"
+	"sort"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+// targetColorRerolls is how many random picks are tried before falling back to
+// choosing among the colors actually on the map
+const targetColorRerolls = 3
+
+// pickTargetColor chooses the color to call for the round, making sure at least
+// one block of it is on the map so the round can be survived. It reports false
+// if the map has no colored blocks at all.
+func (h *GameHandler) pickTargetColor(game *schema.Game) (schema.WoolColor, bool) {
+	counts := countColorCells(game)
+
+	// Late rounds can favor the colors with the fewest blocks
+	if bias := scarcityBias(game.Config, game.RoundNumber); bias > 0 && len(counts) > 0 {
+		return pickScarceColor(game, counts, bias), true
+	}
+
+	for i := 0; i < targetColorRerolls; i++ {
+		color := getRandomColor(game.Rand)
+		if counts[color] > 0 {
+			return color, true
+		}
+		log.Printf("Re-rolling target color %s for game %s: no blocks on the map", color, game.ID)
+	}
+
+	available := sortedColors(counts)
+	if len(available) == 0 {
+		return schema.Air, false
+	}
+	return available[game.Rand.Intn(len(available))], true
+}
+
+// sortedColors returns the colors counted, in order. Map iteration order is
+// random, sorting keeps picks made from the result reproducible from the map seed.
+func sortedColors(counts map[schema.WoolColor]int) []schema.WoolColor {
+	colors := make([]schema.WoolColor, 0, len(counts))
+	for color := range counts {
+		colors = append(colors, color)
+	}
+	sort.Slice(colors, func(i, j int) bool { return colors[i] < colors[j] })
+	return colors
+}
+
+// scarcityBias returns how strongly the round's called color favors rare colors:
+// 0 before ScarcityRampStartRound, then growing over ScarcityRampRounds rounds
+// to 2, where a color's odds are inversely proportional to its block count squared
+func scarcityBias(cfg schema.GameConfig, roundNumber int) float64 {
+	if cfg.ScarcityRampStartRound <= 0 || roundNumber < cfg.ScarcityRampStartRound {
+		return 0
+	}
+	progress := 1.0
+	if cfg.ScarcityRampRounds > 0 {
+		progress = math.Min(float64(roundNumber-cfg.ScarcityRampStartRound+1)/float64(cfg.ScarcityRampRounds), 1)
+	}
+	return 2 * progress
+}
+
+// pickScarceColor picks among the colors on the map with odds weighted by
+// count^-bias, so scarcer colors, with fewer safe blocks, are called more often
+func pickScarceColor(game *schema.Game, counts map[schema.WoolColor]int, bias float64) schema.WoolColor {
+	available := sortedColors(counts)
+
+	weights := make([]float64, len(available))
+	total := 0.0
+	for i, color := range available {
+		weights[i] = math.Pow(float64(counts[color]), -bias)
+		total += weights[i]
+	}
+
+	pick := game.Rand.Float64() * total
+	for i, color := range available {
+		pick -= weights[i]
+		if pick < 0 {
+			return color
+		}
+	}
+	return available[len(available)-1]
+}
+
+// countColorCells counts the blocks of each color inside the configured map size, Air excluded
+func countColorCells(game *schema.Game) map[schema.WoolColor]int {
+	counts := make(map[schema.WoolColor]int)
+	for y := 0; y < game.Config.MapHeight; y++ {
+		for x := 0; x < game.Config.MapWidth; x++ {
+			if color, ok := game.ColorAt(x, y); ok && color != schema.Air {
+				counts[color]++
+			}
+		}
+	}
+	return counts
+}
+
+// safeColorCount returns how many safe colors are called in the round. It starts
+// at InitialSafeColors and drops by one every SafeColorDecay rounds, never below one.
+func safeColorCount(cfg schema.GameConfig, roundNumber int) int {
+	count := cfg.InitialSafeColors
+	if cfg.SafeColorDecay > 0 {
+		count -= (roundNumber - 1) / cfg.SafeColorDecay
+	}
+	if count < 1 {
+		return 1
+	}
+	return count
+}
+
+// pickSafeColors returns the target color followed by extra safe colors picked
+// from those on the map, up to count colors in total
+func (h *GameHandler) pickSafeColors(game *schema.Game, targetColor schema.WoolColor, count int) []schema.WoolColor {
+	safe := []schema.WoolColor{targetColor}
+	if count <= 1 {
+		return safe
+	}
+
+	extra := make([]schema.WoolColor, 0)
+	for _, color := range sortedColors(countColorCells(game)) {
+		if color != targetColor {
+			extra = append(extra, color)
+		}
+	}
+	game.Rand.Shuffle(len(extra), func(i, j int) { extra[i], extra[j] = extra[j], extra[i] })
+
+	for _, color := range extra {
+		if len(safe) == count {
+			break
+		}
+		safe = append(safe, color)
+	}
+	return safe
+}
@@ -0,0 +1,133 @@
+package game
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+// fillMap sets every block of the game's map to color
+func fillMap(game *schema.Game, color schema.WoolColor) {
+	for y := range game.Map {
+		for x := range game.Map[y] {
+			game.Map[y][x] = color
+		}
+	}
+}
+
+func TestCalledColorIsOnTheMap(t *testing.T) {
+	for seed := int64(1); seed <= 20; seed++ {
+		h, game, _ := startTestGame(t, func(cfg *schema.GameConfig) {
+			cfg.MapSeed = seed
+		}, "alice", "bob")
+		game.CustomMap = true
+		fillMap(game, schema.Air)
+		game.SetColorAt(3, 4, schema.Lime)
+
+		h.startNewRound(game)
+
+		if game.CurrentRound == nil || game.CurrentRound.ColorToShow != schema.Lime {
+			t.Fatalf("seed %d: called %v with only Lime on the map", seed, game.CurrentRound)
+		}
+	}
+}
+
+func TestNoColorLeftEndsTheGame(t *testing.T) {
+	h, game, _ := startTestGame(t, nil, "alice", "bob")
+	game.CustomMap = true
+	fillMap(game, schema.Air)
+
+	h.startNewRound(game)
+
+	if game.Phase != schema.Settlement {
+		t.Fatalf("phase %s, want settlement", game.Phase)
+	}
+	if game.CurrentRound != nil || game.RoundNumber != 0 {
+		t.Errorf("round %d was started on an empty map", game.RoundNumber)
+	}
+	for name, player := range game.Players {
+		if player.IsEliminated {
+			t.Errorf("%s was eliminated", name)
+		}
+	}
+	announcements := withEvent(published(game), "winner_announced")
+	if len(announcements) != 1 || announcements[0]["victory_type"] != VictoryShared {
+		t.Errorf("winner_announced = %v, want a shared win", announcements)
+	}
+}
+
+func TestSafeColorCountDecays(t *testing.T) {
+	h, game, _ := startTestGame(t, func(cfg *schema.GameConfig) {
+		cfg.InitialSafeColors = 3
+		cfg.SafeColorDecay = 2
+	}, "alice", "bob")
+
+	want := []int{3, 3, 2, 2, 1, 1, 1}
+	for i, count := range want {
+		h.startNewRound(game)
+		round := game.CurrentRound
+		if len(round.ColorsToShow) != count {
+			t.Errorf("round %d: %d safe colors, want %d", i+1, len(round.ColorsToShow), count)
+		}
+		if round.ColorsToShow[0] != round.ColorToShow {
+			t.Errorf("round %d: safe colors %v don't start with the target %v", i+1, round.ColorsToShow, round.ColorToShow)
+		}
+		seen := make(map[schema.WoolColor]bool)
+		for _, color := range round.ColorsToShow {
+			if seen[color] {
+				t.Errorf("round %d: %v called twice", i+1, color)
+			}
+			seen[color] = true
+		}
+		published(game)
+	}
+
+	// Without a decay the count holds
+	game.Config.SafeColorDecay = 0
+	if got := safeColorCount(game.Config, 50); got != 3 {
+		t.Errorf("without decay round 50 has %d safe colors, want 3", got)
+	}
+}
+
+func TestLateRoundsCallScarceColors(t *testing.T) {
+	h, game := newTestGame(t, func(cfg *schema.GameConfig) {
+		cfg.ScarcityRampStartRound = 5
+		cfg.ScarcityRampRounds = 5
+	})
+	game.Rand = rand.New(rand.NewSource(3))
+	fillMap(game, schema.Red)
+	for x := 0; x < game.Config.MapWidth; x++ {
+		for y := 0; y < 4; y++ {
+			game.SetColorAt(x, y, schema.Blue)
+		}
+		game.SetColorAt(x, 4, schema.Green)
+	}
+	counts := countColorCells(game)
+
+	// meanBlocks is the average block count of the colors called in 500 rounds numbered round
+	meanBlocks := func(round int) (float64, map[schema.WoolColor]int) {
+		game.RoundNumber = round
+		picked := make(map[schema.WoolColor]int)
+		total := 0
+		for i := 0; i < 500; i++ {
+			color, ok := h.pickTargetColor(game)
+			if !ok {
+				t.Fatal("no color picked")
+			}
+			picked[color]++
+			total += counts[color]
+		}
+		return float64(total) / 500, picked
+	}
+
+	early, _ := meanBlocks(1)
+	ramping, _ := meanBlocks(6)
+	late, picked := meanBlocks(10)
+	if !(late < ramping && ramping < early) {
+		t.Errorf("called colors averaged %.0f, %.0f and %.0f blocks in rounds 1, 6 and 10, want fewer each time", early, ramping, late)
+	}
+	if picked[schema.Green] <= picked[schema.Blue] || picked[schema.Blue] <= picked[schema.Red] {
+		t.Errorf("round 10 called %v, want the scarcest color most", picked)
+	}
+}
@@ -0,0 +1,33 @@
+package game
+
+import (
+	"log"
+	"time"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+// gameDurationExceeded reports whether the game has been running longer than
+// MaxGameDurationSeconds. Time spent paused counts, the cap bounds how long
+// the game holds the server.
+func (h *GameHandler) gameDurationExceeded(game *schema.Game) bool {
+	if game.Config.MaxGameDurationSeconds <= 0 || game.StartedAt == nil {
+		return false
+	}
+	maxDuration := time.Duration(game.Config.MaxGameDurationSeconds * float64(time.Second))
+	return time.Since(*game.StartedAt) > maxDuration
+}
+
+// endGameAtTimeLimit ends a game that ran past MaxGameDurationSeconds. The
+// round in progress is abandoned without eliminations and the players still
+// standing are ranked by score, the top scorers winning.
+func (h *GameHandler) endGameAtTimeLimit(game *schema.Game) {
+	now := time.Now()
+	if game.CurrentRound != nil && game.CurrentRound.EndTime == nil {
+		game.CurrentRound.EndTime = &now
+	}
+
+	log.Printf("Game %s reached its %.0fs time limit in round %d, ending it",
+		game.ID, game.Config.MaxGameDurationSeconds, game.RoundNumber)
+	h.finishGame(game, now, rankSurvivorsByScore(game), VictoryTimeLimit)
+}
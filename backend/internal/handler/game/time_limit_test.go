@@ -0,0 +1,61 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+func TestDurationCapEndsTheGameMidRound(t *testing.T) {
+	const limit = 0.3
+	h, game := newTestGame(t, func(cfg *schema.GameConfig) {
+		cfg.MaxGameDurationSeconds = limit
+		cfg.FirstRoundGraceSeconds = 0
+	})
+	alice := joinTestPlayers(t, h, game, "alice", "bob", "carol")["alice"]
+	h.startGame(game)
+	runGame(t, h, game)
+
+	// Well before the first round could be judged
+	deadline := game.StartedAt.Add(time.Duration(limit*float64(time.Second)) + time.Second)
+	var announcements []map[string]interface{}
+	for len(announcements) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("the game is still running past its time limit")
+		}
+		time.Sleep(10 * time.Millisecond)
+		announcements = withEvent(received(alice), "winner_announced")
+	}
+
+	game.Mu.RLock()
+	defer game.Mu.RUnlock()
+	if game.RoundNumber != 1 || game.CurrentRound == nil || game.CurrentRound.EndTime == nil {
+		t.Errorf("ended in round %d, want the first round cut short", game.RoundNumber)
+	}
+	for _, player := range game.Players {
+		if player.IsEliminated {
+			t.Errorf("%s was eliminated by the time limit", player.Name)
+		}
+	}
+	if game.Phase != schema.Settlement {
+		t.Errorf("announced in %s", game.Phase)
+	}
+	if len(announcements) != 1 || announcements[0]["victory_type"] != VictoryTimeLimit {
+		t.Fatalf("announcements %v, want one time_limit victory", announcements)
+	}
+	if winners := announcements[0]["winners"].([]map[string]any); len(winners) != 3 {
+		t.Errorf("%d winners, want the 3 tied survivors", len(winners))
+	}
+}
+
+func TestNoDurationCapByDefault(t *testing.T) {
+	h, game := newTestGame(t, nil)
+	joinTestPlayers(t, h, game, "alice", "bob")
+	h.startGame(game)
+	longAgo := time.Now().Add(-24 * time.Hour)
+	game.StartedAt = &longAgo
+	if h.gameDurationExceeded(game) {
+		t.Error("a game without MaxGameDurationSeconds hit its time limit")
+	}
+}
@@ -0,0 +1,239 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+// validateGameConfig checks that a game built from a custom config is playable.
+// It returns the invalid fields mapped to their problems, empty if valid.
+func validateGameConfig(game *schema.Game, maxPlayers int) map[string]string {
+	cfg := game.Config
+	fields := mapSizeProblems(cfg)
+
+	if cfg.PreGameTimeoutSeconds < 0 {
+		fields["pre_game_timeout_seconds"] = "must not be negative"
+	}
+	if cfg.HazardsPerSpectator < 0 {
+		fields["hazards_per_spectator"] = "must not be negative"
+	}
+	if cfg.HazardCooldownMs < 0 {
+		fields["hazard_cooldown_ms"] = "must not be negative"
+	}
+	if cfg.FinalTwoSlowMo != 0 && cfg.FinalTwoSlowMo < 1 {
+		fields["final_two_slow_mo"] = "must be 0 to disable or at least 1"
+	}
+	if cfg.CatchupBonus < 0 {
+		fields["catchup_bonus"] = "must not be negative"
+	}
+	if cfg.DistancePointsPer < 0 {
+		fields["distance_points_per"] = "must not be negative"
+	}
+	if cfg.DistancePointsRoundCap < 0 {
+		fields["distance_points_round_cap"] = "must not be negative"
+	}
+	if cfg.MaxGameDurationSeconds < 0 {
+		fields["max_game_duration_seconds"] = "must not be negative"
+	}
+	if cfg.RoundBreatherSeconds < 0 {
+		fields["round_breather_seconds"] = "must not be negative"
+	}
+	if cfg.AutoStartSeconds < 0 {
+		fields["auto_start_seconds"] = "must not be negative"
+	}
+	if cfg.AutoStartCapacityRatio < 0 || cfg.AutoStartCapacityRatio > 1 {
+		fields["auto_start_capacity_ratio"] = "must be between 0 and 1, 0 disables"
+	}
+	if cfg.WarmupRounds < 0 {
+		fields["warmup_rounds"] = "must not be negative"
+	}
+
+	if cfg.CellEpsilon < 0 || cfg.CellEpsilon >= 0.5 {
+		fields["cell_epsilon"] = "must be at least 0 and below 0.5"
+	}
+
+	switch cfg.SymmetricMap {
+	case schema.MapSymmetryNone, schema.MapSymmetryMirror, schema.MapSymmetryRotational:
+	default:
+		fields["symmetric_map"] = fmt.Sprintf("must be empty, %s or %s",
+			schema.MapSymmetryMirror, schema.MapSymmetryRotational)
+	}
+
+	if cfg.GuaranteeSpawnDiversity {
+		if cfg.SpawnDiversityColors < 1 || cfg.SpawnDiversityColors > int(schema.Air) {
+			fields["spawn_diversity_colors"] = fmt.Sprintf("must be between 1 and %d", int(schema.Air))
+		}
+		if cfg.SpawnDiversityRadius < 1 {
+			fields["spawn_diversity_radius"] = "must be at least 1 when guarantee_spawn_diversity is set"
+		}
+	}
+	if cfg.MaxEliminationsPerRound < 0 {
+		fields["max_eliminations_per_round"] = "must not be negative"
+	}
+	switch cfg.EliminationCapMode {
+	case schema.EliminationCapSlowest, schema.EliminationCapRandom:
+	default:
+		fields["elimination_cap_mode"] = fmt.Sprintf("must be %s or %s", schema.EliminationCapSlowest, schema.EliminationCapRandom)
+	}
+
+	switch cfg.SpawnMode {
+	case schema.SpawnRandom, schema.SpawnSpread:
+	default:
+		fields["spawn_mode"] = fmt.Sprintf("must be %s or %s", schema.SpawnRandom, schema.SpawnSpread)
+	}
+
+	if cfg.AutoSizeMap && cfg.MapCellsPerPlayer < 1 {
+		fields["map_cells_per_player"] = "must be at least 1 when auto_size_map is set"
+	}
+
+	if cfg.FirstRoundGraceSeconds < 0 {
+		fields["first_round_grace_seconds"] = "must not be negative"
+	}
+
+	if !validBonusFormula(cfg.EliminationBonusFormula) {
+		fields["elimination_bonus_formula"] = fmt.Sprintf("must be one of %s, %s or %s",
+			schema.BonusLinear, schema.BonusPlacementSquared, schema.BonusFlat)
+	}
+	if cfg.ScoreToWin < 0 {
+		fields["score_to_win"] = "must not be negative"
+	}
+	if cfg.LateRoundScoreMultiplier < 0 {
+		fields["late_round_score_multiplier"] = "must not be negative"
+	}
+	for _, name := range cfg.ScoreHooks {
+		if _, exists := scoreHooks[name]; !exists {
+			fields["score_hooks"] = fmt.Sprintf("unknown hook %q, must be one of %s", name, strings.Join(ScoreHookNames(), ", "))
+			break
+		}
+	}
+	if cfg.EliminationBonusMultiplier < 0 {
+		fields["elimination_bonus_multiplier"] = "must not be negative"
+	}
+
+	switch cfg.AntiCheatMode {
+	case schema.AntiCheatReset, schema.AntiCheatFreeze, schema.AntiCheatKick:
+	default:
+		fields["anti_cheat_mode"] = fmt.Sprintf("must be one of %s, %s or %s",
+			schema.AntiCheatReset, schema.AntiCheatFreeze, schema.AntiCheatKick)
+	}
+	if cfg.AntiCheatMode == schema.AntiCheatKick && cfg.KickAfterViolations < 1 {
+		fields["kick_after_violations"] = "must be at least 1 in kick mode"
+	}
+
+	if cfg.InitialSafeColors < 1 || cfg.InitialSafeColors > len(schema.ColorPalette)-1 {
+		fields["initial_safe_colors"] = fmt.Sprintf("must be between 1 and %d", len(schema.ColorPalette)-1)
+	}
+	if cfg.ScarcityRampStartRound < 0 {
+		fields["scarcity_ramp_start_round"] = "must not be negative"
+	}
+	if cfg.ScarcityRampRounds < 0 {
+		fields["scarcity_ramp_rounds"] = "must not be negative"
+	}
+	if cfg.SafeColorDecay < 0 {
+		fields["safe_color_decay"] = "must not be negative"
+	}
+
+	if cfg.MaxSpectators < 0 {
+		fields["max_spectators"] = "must not be negative"
+	}
+
+	if cfg.BorderThickness < 0 || 2*cfg.BorderThickness >= min(cfg.MapWidth, cfg.MapHeight) {
+		fields["border_thickness"] = "must be non-negative and leave room inside the border"
+	}
+
+	// The watchdog must not cut short the longest legitimate color call, a
+	// first round slowed down because only two players joined
+	longestColorCall := longestRushDuration(cfg)*max(1, cfg.FinalTwoSlowMo) + float64(cfg.MaxLagCompensationMs)/1000
+	if cfg.MaxPhaseSeconds > 0 && cfg.MaxPhaseSeconds <= longestColorCall {
+		fields["max_phase_seconds"] = fmt.Sprintf("must be above %.1f, the longest color call, or 0 to disable the watchdog", longestColorCall)
+	}
+
+	if cfg.SafetyHints && cfg.SafetyHintIntervalMs < 0 {
+		fields["safety_hint_interval_ms"] = "must not be negative"
+	}
+
+	if cfg.ClientSendBuffer < 1 {
+		fields["client_send_buffer"] = "must be at least 1"
+	}
+
+	if cfg.Lives < 1 {
+		fields["lives"] = "must be at least 1"
+	}
+
+	if problem := timingProgressionProblem(cfg.TimingProgression); problem != "" {
+		fields["timing_progression"] = problem
+	}
+
+	// Every player needs a colored block to spawn on
+	if spawnable := countSpawnableCells(game); spawnable < maxPlayers {
+		fields["map"] = fmt.Sprintf("has %d spawnable (non-Air) blocks but up to %d players can join", spawnable, maxPlayers)
+	}
+
+	return fields
+}
+
+// mapSizeProblems checks the map dimensions against the map array. The game is
+// built from them, so NewGame checks them before building it. It returns the
+// invalid fields mapped to their problems, empty if valid.
+func mapSizeProblems(cfg schema.GameConfig) map[string]string {
+	fields := make(map[string]string)
+	var mapData schema.MapData
+	if cfg.MapWidth < 1 || cfg.MapWidth > len(mapData[0]) {
+		fields["map_width"] = fmt.Sprintf("must be between 1 and %d", len(mapData[0]))
+	}
+	if cfg.MapHeight < 1 || cfg.MapHeight > len(mapData) {
+		fields["map_height"] = fmt.Sprintf("must be between 1 and %d", len(mapData))
+	}
+	return fields
+}
+
+// timingProgressionProblem describes what is wrong with the timing ranges, empty
+// if they are sane: none, or in order from round 1, each starting right after
+// the previous one ends, with a positive duration
+func timingProgressionProblem(ranges []schema.TimingRange) string {
+	if len(ranges) == 0 {
+		return ""
+	}
+	if ranges[0].StartRound != 1 {
+		return "must start at round 1"
+	}
+	for i, r := range ranges {
+		if r.EndRound < r.StartRound {
+			return fmt.Sprintf("range %d ends at round %d before it starts at round %d", i+1, r.EndRound, r.StartRound)
+		}
+		if r.Duration <= 0 {
+			return fmt.Sprintf("range %d must have a positive duration", i+1)
+		}
+		if i > 0 && r.StartRound != ranges[i-1].EndRound+1 {
+			return fmt.Sprintf("range %d must start at round %d, right after range %d", i+1, ranges[i-1].EndRound+1, i)
+		}
+	}
+	return ""
+}
+
+// longestRushDuration returns the longest rush of any round, before slow-mo
+func longestRushDuration(cfg schema.GameConfig) float64 {
+	if len(cfg.TimingProgression) == 0 {
+		return baseRushDuration
+	}
+	longest := 0.0
+	for _, r := range cfg.TimingProgression {
+		longest = max(longest, r.Duration)
+	}
+	return longest
+}
+
+// countSpawnableCells counts the non-Air blocks inside the configured map size
+func countSpawnableCells(game *schema.Game) int {
+	count := 0
+	for y := 0; y < game.Config.MapHeight; y++ {
+		for x := 0; x < game.Config.MapWidth; x++ {
+			if color, ok := game.ColorAt(x, y); ok && color != schema.Air {
+				count++
+			}
+		}
+	}
+	return count
+}
@@ -0,0 +1,110 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+func timing(start, end int, duration float64) schema.TimingRange {
+	return schema.TimingRange{StartRound: start, EndRound: end, Duration: duration}
+}
+
+func TestTimingProgressionValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		ranges []schema.TimingRange
+		valid  bool
+	}{
+		{"empty uses the built-in decay", nil, true},
+		{"contiguous", []schema.TimingRange{timing(1, 3, 6), timing(4, 6, 4)}, true},
+		{"missing round 1", []schema.TimingRange{timing(2, 3, 6)}, false},
+		{"gapped", []schema.TimingRange{timing(1, 3, 6), timing(5, 6, 4)}, false},
+		{"overlapping", []schema.TimingRange{timing(1, 3, 6), timing(3, 6, 4)}, false},
+		{"ends before it starts", []schema.TimingRange{timing(1, 0, 6)}, false},
+		{"zero duration", []schema.TimingRange{timing(1, 3, 0)}, false},
+	}
+
+	for _, tt := range tests {
+		_, game := newTestGame(t, func(cfg *schema.GameConfig) {
+			cfg.TimingProgression = tt.ranges
+		})
+		problem, invalid := validateGameConfig(game, 16)["timing_progression"]
+		if invalid == tt.valid {
+			t.Errorf("%s: valid = %v (%q), want %v", tt.name, !invalid, problem, tt.valid)
+		}
+	}
+}
+
+func TestRoundDurationFollowsTimingProgression(t *testing.T) {
+	h := &GameHandler{}
+	cfg := schema.GameConfig{TimingProgression: []schema.TimingRange{timing(1, 2, 8), timing(3, 4, 5)}}
+	for round, want := range map[int]float64{1: 8, 2: 8, 3: 5, 4: 5, 9: 5} {
+		if got := h.calculateRoundDuration(cfg, round); got != want {
+			t.Errorf("round %d: duration = %.1f, want %.1f", round, got, want)
+		}
+	}
+
+	decay := map[int]float64{1: 20, 2: 16, 3: 12.8, 50: 1.2}
+	for round, want := range decay {
+		if got := h.calculateRoundDuration(schema.GameConfig{}, round); got < want-1e-9 || got > want+1e-9 {
+			t.Errorf("round %d without ranges: duration = %.2f, want %.2f", round, got, want)
+		}
+	}
+}
+
+func TestWatchdogBoundFollowsTimingProgression(t *testing.T) {
+	_, game := newTestGame(t, func(cfg *schema.GameConfig) {
+		cfg.TimingProgression = []schema.TimingRange{timing(1, 3, 40)}
+		cfg.MaxPhaseSeconds = 30
+	})
+	if _, ok := validateGameConfig(game, 16)["max_phase_seconds"]; !ok {
+		t.Error("a 30s watchdog was accepted with 40s rounds")
+	}
+}
+
+func TestAirCountsAgainstSpawnableBlocks(t *testing.T) {
+	_, game := newTestGame(t, func(cfg *schema.GameConfig) {
+		cfg.MapWidth = 4
+		cfg.MapHeight = 4
+	})
+	if problem, ok := validateGameConfig(game, 16)["map"]; ok {
+		t.Fatalf("a full 4x4 map was rejected for 16 players: %s", problem)
+	}
+
+	game.SetColorAt(2, 2, schema.Air)
+	if _, ok := validateGameConfig(game, 16)["map"]; !ok {
+		t.Error("a 4x4 map with an Air block was accepted for 16 players")
+	}
+	if problem, ok := validateGameConfig(game, 15)["map"]; ok {
+		t.Errorf("15 blocks were rejected for 15 players: %s", problem)
+	}
+}
+
+func TestAutoStartValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		seconds float64
+		ratio   float64
+		field   string
+	}{
+		{name: "defaults", seconds: 5, ratio: 0.75},
+		{name: "immediate at full lobby", seconds: 0, ratio: 1},
+		{name: "capacity start disabled", seconds: 5, ratio: 0},
+		{name: "negative countdown", seconds: -1, ratio: 0.75, field: "auto_start_seconds"},
+		{name: "ratio above 1", seconds: 5, ratio: 1.5, field: "auto_start_capacity_ratio"},
+		{name: "negative ratio", seconds: 5, ratio: -0.1, field: "auto_start_capacity_ratio"},
+	}
+	for _, tt := range tests {
+		_, game := newTestGame(t, func(cfg *schema.GameConfig) {
+			cfg.AutoStartSeconds = tt.seconds
+			cfg.AutoStartCapacityRatio = tt.ratio
+		})
+		fields := validateGameConfig(game, 16)
+		for _, field := range []string{"auto_start_seconds", "auto_start_capacity_ratio"} {
+			if _, invalid := fields[field]; invalid != (field == tt.field) {
+				t.Errorf("%s: %s invalid = %v (%q)", tt.name, field, invalid, fields[field])
+			}
+		}
+	}
+}
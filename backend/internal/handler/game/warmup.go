@@ -0,0 +1,38 @@
+package game
+
+import (
+	"log"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+// broadcastWarmupRound tells clients the round that just started is practice
+func (h *GameHandler) broadcastWarmupRound(game *schema.Game) {
+	game.Publish(map[string]any{
+		"event": "warmup_round",
+		"data": map[string]any{
+			"round_number":  game.CurrentRound.Number,
+			"warmup_rounds": game.Config.WarmupRounds,
+		},
+	})
+}
+
+// spareWarmupPlayer keeps a player who would have been eliminated in a warmup
+// round in the game and privately tells them why they would have been out. It
+// reports whether the player was spared.
+func (h *GameHandler) spareWarmupPlayer(game *schema.Game, player *schema.Player, reason schema.EliminationReason) bool {
+	if !game.CurrentRound.Warmup {
+		return false
+	}
+
+	log.Printf("Player %s would have been eliminated (%s) in warmup round %d of game %s",
+		player.Name, reason, game.CurrentRound.Number, game.ID)
+	sendToClient(game, player.Name, map[string]any{
+		"event": "would_be_eliminated",
+		"data": map[string]any{
+			"round_number": game.CurrentRound.Number,
+			"reason":       reason,
+		},
+	})
+	return true
+}
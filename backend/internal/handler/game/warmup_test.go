@@ -0,0 +1,43 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+func TestWarmupRoundSparesWrongPlayers(t *testing.T) {
+	h, game, clients := startTestGame(t, func(cfg *schema.GameConfig) {
+		cfg.WarmupRounds = 1
+		cfg.SurvivalPointsPerRound = 10
+	}, "alice", "bob", "carol")
+
+	judgeRound(t, h, game, "bob")
+
+	if warmups := withEvent(published(game), "warmup_round"); len(warmups) != 1 || warmups[0]["round_number"] != 1 {
+		t.Errorf("warmup_round = %v, want one for round 1", warmups)
+	}
+	bob := game.Players["bob"]
+	if bob.IsEliminated || game.AliveCount != 3 {
+		t.Errorf("bob eliminated %v in a warmup round, %d alive", bob.IsEliminated, game.AliveCount)
+	}
+	notices := withEvent(received(clients["bob"]), "would_be_eliminated")
+	if len(notices) != 1 || notices[0]["round_number"] != 1 || notices[0]["reason"] != schema.EliminatedWrongColor {
+		t.Errorf("bob got notices %v, want one for the wrong color in round 1", notices)
+	}
+	if notices := withEvent(received(clients["alice"]), "would_be_eliminated"); len(notices) != 0 {
+		t.Errorf("alice on the color got notices %v", notices)
+	}
+	for name, player := range game.Players {
+		if player.Stats.SurvivalPoints != 0 {
+			t.Errorf("%s scored %d in a warmup round", name, player.Stats.SurvivalPoints)
+		}
+	}
+
+	// The round after the warmup counts
+	game.CurrentRound = nil
+	judgeRound(t, h, game, "bob")
+	if !bob.IsEliminated {
+		t.Error("bob on the wrong color survived after the warmup")
+	}
+}
@@ -0,0 +1,29 @@
+package game
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/yorukot/blind-party/internal/schema"
+	"github.com/yorukot/blind-party/internal/webhook"
+)
+
+// notifyWebhook sends a game event to the game's webhook, if it has one. The
+// delivery runs in the background so a slow receiver never holds up the game.
+func (h *GameHandler) notifyWebhook(game *schema.Game, event string, data any) {
+	h.Webhooks.Notify(game.WebhookURL, webhook.Event{
+		Event:     event,
+		GameID:    game.ID,
+		Timestamp: time.Now(),
+		Data:      data,
+	})
+}
+
+// validWebhookURL reports whether raw is an absolute http or https URL
+func validWebhookURL(raw string) bool {
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Host == "" {
+		return false
+	}
+	return parsed.Scheme == "http" || parsed.Scheme == "https"
+}
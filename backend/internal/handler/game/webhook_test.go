@@ -0,0 +1,77 @@
+package game
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/yorukot/blind-party/internal/schema"
+	"github.com/yorukot/blind-party/internal/webhook"
+)
+
+func TestWebhookReceivesStartAndEnd(t *testing.T) {
+	events := make(chan webhook.Event, 8)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event webhook.Event
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("decode webhook body: %v", err)
+		}
+		events <- event
+	}))
+	defer server.Close()
+
+	h, game := newTestGame(t, nil)
+	h.Webhooks = webhook.NewNotifier(time.Second, 0, 4, true)
+	game.WebhookURL = server.URL
+	joinTestPlayers(t, h, game, "alice", "bob", "carol", "dave")
+
+	h.startGame(game)
+	started := waitWebhook(t, events)
+	if started.Event != "game_started" || started.GameID != game.ID {
+		t.Fatalf("first event = %+v, want game_started", started)
+	}
+	if count := started.Data.(map[string]any)["player_count"]; count != 4.0 {
+		t.Errorf("player_count = %v, want 4", count)
+	}
+
+	h.finishGame(game, time.Now(), []*schema.Player{game.Players["carol"]}, VictorySolo)
+	ended := waitWebhook(t, events)
+	if ended.Event != "game_ended" {
+		t.Fatalf("second event = %+v, want game_ended", ended)
+	}
+	data := ended.Data.(map[string]any)
+	if data["victory_type"] != string(VictorySolo) {
+		t.Errorf("victory_type = %v", data["victory_type"])
+	}
+	if winners := data["winners"].([]any); len(winners) != 1 || winners[0] != "carol" {
+		t.Errorf("winners = %v, want [carol]", winners)
+	}
+}
+
+func TestNewGameRejectsPrivateWebhook(t *testing.T) {
+	h := &GameHandler{Webhooks: webhook.NewNotifier(time.Second, 0, 4, false)}
+	rec := httptest.NewRecorder()
+	h.NewGame(rec, httptest.NewRequest(http.MethodPost, "/api/game/", jsonBody(t, map[string]any{
+		"webhook_url": "http://169.254.169.254/latest/meta-data",
+	})))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", rec.Code)
+	}
+	if len(h.allGames()) != 0 {
+		t.Error("a game was created")
+	}
+}
+
+func waitWebhook(t *testing.T, events <-chan webhook.Event) webhook.Event {
+	t.Helper()
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook not delivered")
+		return webhook.Event{}
+	}
+}
@@ -1,33 +1,153 @@
 package game
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"net"
 	"strconv"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"golang.org/x/net/websocket"
 
+	"github.com/yorukot/blind-party/internal/config"
 	"github.com/yorukot/blind-party/internal/schema"
 )
 
+// closeReason is the code and reason sent to a client right before the server
+// closes its connection, so clients can tell a kick from a crash
+type closeReason struct {
+	Code   int
+	Reason string
+}
+
+var (
+	closeMissingGameID   = closeReason{Code: 4000, Reason: "missing_game_id"}
+	closeMissingUsername = closeReason{Code: 4001, Reason: "missing_username"}
+	closeInvalidAvatar   = closeReason{Code: 4002, Reason: "invalid_avatar"}
+	closeAvatarTaken     = closeReason{Code: 4003, Reason: "avatar_taken"}
+	closeGameNotFound    = closeReason{Code: 4004, Reason: "game_not_found"}
+	closeUnresponsive    = closeReason{Code: 4008, Reason: "unresponsive"}
+	closeUsernameTaken   = closeReason{Code: 4009, Reason: "username_taken"}
+	closeIdleTimeout     = closeReason{Code: 4010, Reason: "idle_timeout"}
+	closeKicked          = closeReason{Code: 4011, Reason: "kicked_for_cheating"}
+	closeSpectatorsFull  = closeReason{Code: 4012, Reason: "spectator_limit_reached"}
+	closeGameAbandoned   = closeReason{Code: 4013, Reason: "game_abandoned"}
+	closeBadObserverKey  = closeReason{Code: 4014, Reason: "invalid_observer_key"}
+	closeGameClosed      = closeReason{Code: 4015, Reason: "game_closed"}
+	closeGameNotReady    = closeReason{Code: 4017, Reason: "game_not_ready"}
+	closeDuplicateName   = closeReason{Code: 4018, Reason: "duplicate_name"}
+	closeReplaced        = closeReason{Code: 4019, Reason: "replaced_by_new_connection"}
+	closeGameError       = closeReason{Code: 4500, Reason: "game_error"}
+)
+
+// gameReadyTimeout is how long a connection waits for a just created game's
+// lifecycle to start before it is turned away with closeGameNotReady
+const gameReadyTimeout = 2 * time.Second
+
+// sendCloseMessage writes a final connection_closing message to the connection
+func sendCloseMessage(ws *websocket.Conn, reason closeReason) {
+	message := map[string]interface{}{
+		"event": "connection_closing",
+		"data": map[string]interface{}{
+			"code":   reason.Code,
+			"reason": reason.Reason,
+		},
+	}
+	if err := sendWithDeadline(ws, message); err != nil {
+		log.Printf("Error sending close message (%s): %v", reason.Reason, err)
+	}
+}
+
+// sendWithDeadline writes a message, giving up after the configured write timeout
+// so a half-open connection can't block the writer forever
+func sendWithDeadline(ws *websocket.Conn, message interface{}) error {
+	timeout := time.Duration(config.Env().WSWriteTimeoutSeconds) * time.Second
+	if err := ws.SetWriteDeadline(time.Now().Add(timeout)); err != nil {
+		return err
+	}
+	return codecFor(ws).Send(ws, message)
+}
+
+// receiveWithDeadline reads a message, failing if none arrives within timeout.
+// Clients answer server pings, so an idle connection is a dead one.
+func receiveWithDeadline(ws *websocket.Conn, message *map[string]interface{}, timeout time.Duration) error {
+	if err := ws.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+		return err
+	}
+	return codecFor(ws).Receive(ws, message)
+}
+
+// isTimeout reports whether err is a deadline expiry
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// closeClient closes the client's send channel, recording why the server is
+// disconnecting it. The writer goroutine delivers the reason before closing.
+func closeClient(client *schema.WebSocketClient, reason closeReason) {
+	client.CloseCode = reason.Code
+	client.CloseReason = reason.Reason
+	close(client.Send)
+}
+
+// writeClientMessages sends the client's messages until Send is closed, then
+// tells the client why if the server initiated it. Register is buffered, so a
+// client may be queued when the lifecycle ends and never be taken in, nothing
+// would close its Send, so it is closed with closeGameClosed instead.
+func writeClientMessages(ws *websocket.Conn, game *schema.Game, client *schema.WebSocketClient) {
+	done := game.Done
+	for {
+		var message interface{}
+		var open bool
+		select {
+		case message, open = <-client.Send:
+		case <-done:
+			// The lifecycle closed Send of every client it took in before it
+			// was done, an open and empty Send means it never saw this one
+			done = nil
+			select {
+			case message, open = <-client.Send:
+			default:
+				sendCloseMessage(ws, closeGameClosed)
+				return
+			}
+		}
+
+		if !open {
+			if client.CloseReason != "" {
+				sendCloseMessage(ws, closeReason{Code: client.CloseCode, Reason: client.CloseReason})
+			}
+			return
+		}
+		if err := sendWithDeadline(ws, message); err != nil {
+			log.Printf("Error sending message to client %s: %v", client.Username, err)
+			return
+		}
+	}
+}
+
 // ConnectWebSocket handles WebSocket connections for a specific game
 func (h *GameHandler) ConnectWebSocket(ws *websocket.Conn) {
 	defer ws.Close()
+	ws.MaxPayloadBytes = maxClientMessageBytes
 
 	// Get gameID from URL path
 	req := ws.Request()
 	gameID := chi.URLParam(req, "gameID")
 	if gameID == "" {
 		log.Println("No gameID provided in WebSocket connection")
+		sendCloseMessage(ws, closeMissingGameID)
 		return
 	}
 
 	// Get game instance
-	game, exists := h.GameData[gameID]
+	game, exists := h.getGame(gameID)
 	if !exists {
 		log.Printf("Game %s not found", gameID)
+		sendCloseMessage(ws, closeGameNotFound)
 		return
 	}
 
@@ -35,50 +155,80 @@ func (h *GameHandler) ConnectWebSocket(ws *websocket.Conn) {
 	username := req.URL.Query().Get("username")
 	if username == "" {
 		log.Println("No username provided in WebSocket connection")
+		sendCloseMessage(ws, closeMissingUsername)
 		return
 	}
 
-	// Make sure the username is unique in the game
-	for _, player := range game.Players {
-		if player.Name == username {
-			log.Printf("Username %s already taken in game %s", username, gameID)
-			return
-		}
+	// The avatar is optional, clients render a default without one
+	avatar := req.URL.Query().Get("avatar")
+	if avatar != "" && !schema.ValidAvatar(avatar) {
+		log.Printf("Invalid avatar %q from user %s", avatar, username)
+		sendCloseMessage(ws, closeInvalidAvatar)
+		return
 	}
 
 	// Create WebSocket client
 	client := &schema.WebSocketClient{
 		Conn:      ws,
 		Username:  username,
+		UserID:    req.URL.Query().Get("user_id"),
 		Token:     "", // No token needed
-		Send:      make(chan interface{}, 256),
+		Avatar:    avatar,
+		Gzip:      req.URL.Query().Get("compress") == "gzip",
+		Send:      make(chan interface{}, game.Config.ClientSendBuffer),
 		Connected: time.Now(),
 	}
 
-	// Register client with the game
-	game.Register <- client
+	// A game that was just created may not be consuming Register yet
+	select {
+	case <-game.Ready:
+	case <-time.After(gameReadyTimeout):
+		log.Printf("Game %s not ready, turning away user %s", gameID, username)
+		sendCloseMessage(ws, closeGameNotReady)
+		return
+	}
 
-	// Handle client disconnection
+	// Register client with the game, unless it ended in the meantime
+	select {
+	case game.Register <- client:
+	case <-game.Done:
+		log.Printf("Game %s already ended, turning away user %s", gameID, username)
+		sendCloseMessage(ws, closeGameClosed)
+		return
+	}
+
+	// Handle client disconnection. Once the lifecycle is done nobody else
+	// unregisters the client, so release its writer here.
 	defer func() {
-		game.Unregister <- client
+		select {
+		case game.Unregister <- client:
+		case <-game.Done:
+			game.Mu.Lock()
+			if current, exists := game.Clients[client.Username]; exists && current == client {
+				delete(game.Clients, client.Username)
+				close(client.Send)
+			}
+			game.Mu.Unlock()
+		}
 	}()
 
 	// Start goroutine to handle sending messages to client
 	go func() {
 		defer ws.Close()
-		for message := range client.Send {
-			if err := websocket.JSON.Send(ws, message); err != nil {
-				log.Printf("Error sending message to client %s: %v", username, err)
-				return
-			}
-		}
+		writeClientMessages(ws, game, client)
 	}()
 
 	// Read messages from client (handle player updates)
 	for {
 		var message map[string]interface{}
-		err := websocket.JSON.Receive(ws, &message)
+		err := receiveWithDeadline(ws, &message, h.readTimeout())
 		if err != nil {
+			if isTimeout(err) {
+				log.Printf("WebSocket read deadline expired for user %s, closing idle connection", username)
+				client.TimedOut = true
+				sendCloseMessage(ws, closeIdleTimeout)
+				break
+			}
 			log.Printf("WebSocket read error for user %s (username: %s): %v", username, username, err)
 			break
 		}
@@ -89,11 +239,17 @@ func (h *GameHandler) ConnectWebSocket(ws *websocket.Conn) {
 			case "player_update":
 				log.Printf("Received player update from user %s", username)
 				h.handlePlayerUpdate(game, username, message)
+			case "forfeit":
+				log.Printf("Received forfeit from user %s", username)
+				h.handleForfeit(game, username)
+			case "drop_hazard":
+				h.handleDropHazard(game, username, message)
+			case "pong":
+				// Echo of a server ping, used to measure RTT
+				h.handlePong(game, username, message)
 			case "ping":
 				// Respond to ping with pong
-				client.Send <- map[string]interface{}{
-					"event": "pong",
-				}
+				h.handlePing(game, client)
 			default:
 				log.Printf("Unknown message type from user %s: %s", username, msgType)
 			}
@@ -101,7 +257,11 @@ func (h *GameHandler) ConnectWebSocket(ws *websocket.Conn) {
 	}
 }
 
-// handlePlayerUpdate processes player position updates from WebSocket clients
+// handlePlayerUpdate processes player position updates from WebSocket clients.
+// The whole update, from the phase check to the new position, runs under
+// game.Mu, the lock the lifecycle holds while it snapshots positions and
+// moves the round to the elimination check, so an update either lands before
+// the snapshot or is rejected as movement_locked.
 func (h *GameHandler) handlePlayerUpdate(game *schema.Game, username string, message map[string]interface{}) {
 	game.Mu.Lock()
 	defer game.Mu.Unlock()
@@ -115,15 +275,24 @@ func (h *GameHandler) handlePlayerUpdate(game *schema.Game, username string, mes
 	if player.IsEliminated || player.IsSpectator {
 		log.Printf("Skipping position update for user %s: player is %s", username,
 			func() string {
-				if player.IsEliminated { return "eliminated" }
+				if player.IsEliminated {
+					return "eliminated"
+				}
 				return "spectator"
 			}())
 		return
 	}
 
-	// Don't allow position updates during elimination phase
-	if game.CurrentRound != nil && game.CurrentRound.Phase == schema.EliminationCheck {
-		log.Printf("Skipping position update for user %s: game is in elimination phase", username)
+	// Don't allow position updates while movement is locked for the round phase
+	if locked, phase := movementLocked(game); locked {
+		log.Printf("Skipping position update for user %s: movement is locked during %s", username, phase)
+		sendToClient(game, username, map[string]interface{}{
+			"event": "update_rejected",
+			"data": map[string]interface{}{
+				"reason": "movement_locked",
+				"phase":  phase,
+			},
+		})
 		return
 	}
 
@@ -135,6 +304,29 @@ func (h *GameHandler) handlePlayerUpdate(game *schema.Game, username string, mes
 	}
 	log.Printf("Received position data from user %s: %+v", username, data)
 
+	// Drop updates that arrive out of order so a delayed packet can't
+	// overwrite a newer position. Updates without a seq are always accepted.
+	if rawSeq, hasSeq := message["seq"]; hasSeq {
+		seq, err := parseFloat(rawSeq)
+		if err != nil {
+			log.Printf("Invalid seq from user %s: %v (error: %v)", username, rawSeq, err)
+			return
+		}
+		if int64(seq) <= player.LastSeq {
+			log.Printf("Dropping out-of-order update from user %s: seq %d <= last %d", username, int64(seq), player.LastSeq)
+			sendToClient(game, username, map[string]interface{}{
+				"event": "update_rejected",
+				"data": map[string]interface{}{
+					"reason":   "out_of_order",
+					"seq":      int64(seq),
+					"last_seq": player.LastSeq,
+				},
+			})
+			return
+		}
+		player.LastSeq = int64(seq)
+	}
+
 	newPosition := player.Position
 
 	// Extract new position coordinates
@@ -157,15 +349,79 @@ func (h *GameHandler) handlePlayerUpdate(game *schema.Game, username string, mes
 	}
 	log.Printf("Handling position update for user %s, x: %.1f, y: %.1f", username, newPosition.X, newPosition.Y)
 
-	// Update player position (validation moved to game lifecycle)
+	// Movement is only checked once the game is running, the lobby is free roam
+	if game.Phase == schema.InGame && !h.validateMovement(game, player, newPosition) {
+		return
+	}
+
+	// Response time runs to the last move onto another block during the color call
+	if game.Phase == schema.InGame && game.CurrentRound != nil && game.CurrentRound.Phase == schema.ColorCall {
+		oldX, oldY := worldToCell(player.Position, game.Config.CellEpsilon)
+		newX, newY := worldToCell(newPosition, game.Config.CellEpsilon)
+		if oldX != newX || oldY != newY {
+			player.SettledAt = time.Now()
+		}
+	}
+
+	if game.Phase == schema.InGame {
+		recordDistance(game, player, newPosition)
+	}
+
+	// Update player position
 	player.Position = newPosition
 
 	// Update last update time
 	player.LastUpdate = time.Now()
+	positionHistory(game, username).Add(newPosition, player.LastUpdate)
 
 	game.Players[username] = player
 }
 
+// movementLocked reports whether position updates are rejected in the current
+// round phase: always during the elimination check, and during the color call
+// when LockDuringColorCall is set
+func movementLocked(game *schema.Game) (bool, schema.RoundPhase) {
+	if game.CurrentRound == nil {
+		return false, ""
+	}
+	switch game.CurrentRound.Phase {
+	case schema.EliminationCheck:
+		return true, schema.EliminationCheck
+	case schema.ColorCall:
+		return game.Config.LockDuringColorCall, schema.ColorCall
+	default:
+		return false, game.CurrentRound.Phase
+	}
+}
+
+// handlePing answers a client's ping with a pong. The client may have been
+// replaced or disconnected by the server since, its Send closed, so it only
+// answers the client still registered under the name.
+func (h *GameHandler) handlePing(game *schema.Game, client *schema.WebSocketClient) {
+	game.Mu.Lock()
+	defer game.Mu.Unlock()
+	if game.Clients[client.Username] != client {
+		return
+	}
+	sendToClient(game, client.Username, map[string]interface{}{
+		"event": "pong",
+	})
+}
+
+// sendToClient queues a message for a single user without blocking. Callers must hold game.Mu.
+func sendToClient(game *schema.Game, username string, message interface{}) {
+	client, exists := game.Clients[username]
+	if !exists {
+		return
+	}
+	game.SendMetrics.RecordDepth(len(client.Send))
+	select {
+	case client.Send <- message:
+	default:
+		game.SendBufferFull++
+	}
+}
+
 // parseFloat attempts to convert various numeric types to float64
 func parseFloat(value interface{}) (float64, error) {
 	switch v := value.(type) {
@@ -175,10 +431,22 @@ func parseFloat(value interface{}) (float64, error) {
 		return float64(v), nil
 	case int:
 		return float64(v), nil
+	case int8:
+		return float64(v), nil
+	case int16:
+		return float64(v), nil
 	case int32:
 		return float64(v), nil
 	case int64:
 		return float64(v), nil
+	case uint8:
+		return float64(v), nil
+	case uint16:
+		return float64(v), nil
+	case uint32:
+		return float64(v), nil
+	case uint64:
+		return float64(v), nil
 	case string:
 		return strconv.ParseFloat(v, 64)
 	default:
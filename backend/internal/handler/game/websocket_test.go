@@ -0,0 +1,281 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+func TestServerClosesWithCodeAndReason(t *testing.T) {
+	h, game := newTestGame(t, nil)
+	server := serveGames(t, h)
+	runGame(t, h, game)
+
+	tests := []struct {
+		name   string
+		gameID string
+		path   string
+		want   closeReason
+	}{
+		{"unknown game", "000000", "/ws?username=alice", closeGameNotFound},
+		{"no username", game.ID, "/ws", closeMissingUsername},
+		{"unknown avatar", game.ID, "/ws?username=alice&avatar=nope", closeInvalidAvatar},
+	}
+
+	for _, tt := range tests {
+		conn := dialGame(t, server, tt.gameID, tt.path)
+		closing := receiveClose(t, conn)
+		if closing == nil {
+			t.Errorf("%s: closed without a connection_closing message", tt.name)
+			continue
+		}
+		if closing["code"] != float64(tt.want.Code) || closing["reason"] != tt.want.Reason {
+			t.Errorf("%s: closed with %v %v, want %d %s", tt.name, closing["code"], closing["reason"], tt.want.Code, tt.want.Reason)
+		}
+	}
+}
+
+func TestReplacedConnectionIsToldWhy(t *testing.T) {
+	h, game := newTestGame(t, nil)
+	server := serveGames(t, h)
+	runGame(t, h, game)
+
+	first := dialGame(t, server, game.ID, "/ws?username=alice&user_id=u1")
+	receiveUntil(t, first, "game_update")
+	dialGame(t, server, game.ID, "/ws?username=alice&user_id=u1")
+
+	closing := receiveClose(t, first)
+	if closing == nil || closing["reason"] != closeReplaced.Reason {
+		t.Errorf("first connection closed with %v, want %s", closing, closeReplaced.Reason)
+	}
+}
+
+// playerUpdate is a player_update message moving to x, y, without a seq if seq is 0
+func playerUpdate(x, y float64, seq int) map[string]interface{} {
+	message := map[string]interface{}{
+		"event":  "player_update",
+		"player": map[string]interface{}{"pos_x": x, "pos_y": y},
+	}
+	if seq != 0 {
+		message["seq"] = float64(seq)
+	}
+	return message
+}
+
+func TestOutOfOrderUpdatesAreIgnored(t *testing.T) {
+	h, game := newTestGame(t, nil)
+	alice := joinTestPlayers(t, h, game, "alice")["alice"]
+	received(alice)
+
+	h.handlePlayerUpdate(game, "alice", playerUpdate(1.5, 1.5, 1))
+	h.handlePlayerUpdate(game, "alice", playerUpdate(3.5, 3.5, 3))
+	h.handlePlayerUpdate(game, "alice", playerUpdate(2.5, 2.5, 2))
+	h.handlePlayerUpdate(game, "alice", playerUpdate(2.5, 2.5, 3))
+
+	player := game.Players["alice"]
+	if player.Position.X != 3.5 || player.LastSeq != 3 {
+		t.Errorf("position %.1f with last seq %d, want 3.5 from seq 3", player.Position.X, player.LastSeq)
+	}
+	rejections := withEvent(received(alice), "update_rejected")
+	if len(rejections) != 2 {
+		t.Fatalf("got %d rejections, want 2", len(rejections))
+	}
+	for _, rejection := range rejections {
+		if rejection["reason"] != "out_of_order" || rejection["last_seq"] != int64(3) {
+			t.Errorf("rejection = %v", rejection)
+		}
+	}
+
+	h.handlePlayerUpdate(game, "alice", playerUpdate(4.5, 4.5, 4))
+	h.handlePlayerUpdate(game, "alice", playerUpdate(5.5, 5.5, 0))
+	if player.Position.X != 5.5 || player.LastSeq != 4 {
+		t.Errorf("position %.1f with last seq %d, want 5.5 and seq 4", player.Position.X, player.LastSeq)
+	}
+}
+
+func TestNewConnectionRestartsSequence(t *testing.T) {
+	h, game := newTestGame(t, nil)
+	joinTestPlayers(t, h, game, "alice")
+	h.handlePlayerUpdate(game, "alice", playerUpdate(3.5, 3.5, 40))
+
+	h.handleClientRegister(game, newTestClient("alice", "id-alice"))
+	h.handlePlayerUpdate(game, "alice", playerUpdate(1.5, 1.5, 1))
+
+	if player := game.Players["alice"]; player.Position.X != 1.5 || player.LastSeq != 1 {
+		t.Errorf("position %.1f with last seq %d after reconnecting, want 1.5 from seq 1", player.Position.X, player.LastSeq)
+	}
+}
+
+func TestMovementLockedByRoundPhase(t *testing.T) {
+	tests := []struct {
+		name   string
+		phase  schema.RoundPhase // empty between rounds
+		lock   bool
+		locked bool
+	}{
+		{"color call", schema.ColorCall, false, false},
+		{"color call with lock", schema.ColorCall, true, true},
+		{"elimination check", schema.EliminationCheck, false, true},
+		{"between rounds", "", true, false},
+	}
+
+	for _, tt := range tests {
+		h, game, clients := startTestGame(t, func(cfg *schema.GameConfig) {
+			cfg.LockDuringColorCall = tt.lock
+		}, "alice")
+		alice := clients["alice"]
+		if tt.phase != "" {
+			game.CurrentRound = &schema.Round{Number: 1, Phase: tt.phase}
+		}
+		player := game.Players["alice"]
+		player.Position = schema.Position{X: 10.5, Y: 10.5}
+		player.LastMoveTime = time.Now().Add(-time.Second)
+		received(alice)
+
+		h.handlePlayerUpdate(game, "alice", playerUpdate(11.5, 10.5, 0))
+
+		rejections := withEvent(received(alice), "update_rejected")
+		if tt.locked {
+			if len(rejections) != 1 || rejections[0]["reason"] != "movement_locked" || rejections[0]["phase"] != tt.phase {
+				t.Errorf("%s: rejections %v, want movement_locked", tt.name, rejections)
+			}
+			if player.Position.X != 10.5 {
+				t.Errorf("%s: player moved to %.1f", tt.name, player.Position.X)
+			}
+		} else if len(rejections) != 0 || player.Position.X != 11.5 {
+			t.Errorf("%s: rejections %v, position %.1f, want the move applied", tt.name, rejections, player.Position.X)
+		}
+	}
+}
+
+func TestIdleConnectionIsClosed(t *testing.T) {
+	h, game := newTestGame(t, nil)
+	h.ReadTimeout = 200 * time.Millisecond
+	server := serveGames(t, h)
+	runGame(t, h, game)
+
+	conn := dialGame(t, server, game.ID, "/ws?username=alice&user_id=u1")
+	receiveUntil(t, conn, "game_update")
+	start := time.Now()
+
+	closing := receiveClose(t, conn)
+	if closing == nil || closing["reason"] != closeIdleTimeout.Reason {
+		t.Errorf("closed with %v, want %s", closing, closeIdleTimeout.Reason)
+	}
+	if idle := time.Since(start); idle > 2*time.Second {
+		t.Errorf("closed after %v idle, want about 200ms", idle)
+	}
+
+	// The client is unregistered once the connection is gone
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		game.Mu.RLock()
+		_, registered := game.Clients["alice"]
+		game.Mu.RUnlock()
+		if !registered {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("idle client still registered")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestConnectingRightAfterCreation(t *testing.T) {
+	h := newGameHandler(t, time.Minute)
+	server := serveGames(t, h)
+
+	for i := 0; i < 5; i++ {
+		created := createGame(t, h, "", map[string]any{"user_id": "alice"})
+		conn := dialGame(t, server, created.GameID, "/ws?username=alice&user_id=id-alice")
+		if state := receiveUntil(t, conn, "game_update"); state["game_id"] != created.GameID {
+			t.Errorf("connected to %v, want %s", state["game_id"], created.GameID)
+		}
+	}
+}
+
+func TestGameThatNeverStartsTurnsClientsAway(t *testing.T) {
+	h, game := newTestGame(t, nil)
+	h.storeGame(game) // Its lifecycle never runs
+	server := serveGames(t, h)
+
+	start := time.Now()
+	conn := dialGame(t, server, game.ID, "/ws?username=alice&user_id=id-alice")
+	closing := receiveClose(t, conn)
+	if closing["reason"] != closeGameNotReady.Reason || closing["code"] != float64(closeGameNotReady.Code) {
+		t.Errorf("closed with %v, want %s", closing, closeGameNotReady.Reason)
+	}
+	if waited := time.Since(start); waited < gameReadyTimeout {
+		t.Errorf("turned away after %v, want a wait of %v", waited, gameReadyTimeout)
+	}
+	if len(game.Register) != 0 || len(game.Clients) != 0 {
+		t.Error("the client was registered")
+	}
+}
+
+func TestPingNeverBlocksOrPanics(t *testing.T) {
+	h, game := newTestGame(t, nil)
+	clients := joinTestPlayers(t, h, game, "alice")
+
+	h.handlePing(game, clients["alice"])
+	if pongs := withEvent(received(clients["alice"]), "pong"); len(pongs) != 1 {
+		t.Fatalf("got %d pongs, want 1", len(pongs))
+	}
+
+	// A ping read from a connection the server already replaced, its Send closed
+	replaced := clients["alice"]
+	current := newTestClient("alice", "id-alice")
+	h.handleClientRegister(game, current)
+	received(current)
+	h.handlePing(game, replaced)
+	if pongs := withEvent(received(current), "pong"); len(pongs) != 0 {
+		t.Errorf("the new connection got %d pongs for the old one's ping", len(pongs))
+	}
+
+	// A ping from a client whose buffer is full
+	slow := &schema.WebSocketClient{Username: "bob", UserID: "id-bob", Send: make(chan interface{}, 1)}
+	h.handleClientRegister(game, slow)
+	for len(slow.Send) < cap(slow.Send) {
+		slow.Send <- map[string]interface{}{"event": "filler"}
+	}
+	full := game.SendBufferFull
+	answered := make(chan struct{})
+	go func() {
+		h.handlePing(game, slow)
+		close(answered)
+	}()
+	select {
+	case <-answered:
+	case <-time.After(2 * time.Second):
+		t.Fatal("a ping blocked on a full send buffer")
+	}
+	if game.SendBufferFull != full+1 {
+		t.Errorf("the dropped pong wasn't counted, %d full sends, want %d", game.SendBufferFull, full+1)
+	}
+}
+
+func TestCloseCodesKeepTheirMeaning(t *testing.T) {
+	reasons := []closeReason{
+		closeMissingGameID, closeMissingUsername, closeInvalidAvatar, closeAvatarTaken,
+		closeGameNotFound, closeUnresponsive, closeUsernameTaken, closeIdleTimeout,
+		closeKicked, closeSpectatorsFull, closeGameAbandoned, closeBadObserverKey,
+		closeGameClosed, closeGameNotReady, closeDuplicateName, closeReplaced, closeGameError,
+	}
+	seen := make(map[int]string)
+	for _, reason := range reasons {
+		if other, taken := seen[reason.Code]; taken {
+			t.Errorf("%d is both %s and %s", reason.Code, other, reason.Reason)
+		}
+		seen[reason.Code] = reason.Reason
+	}
+
+	// Codes clients already act on never change their reason
+	published := map[int]string{4009: "username_taken", 4019: "replaced_by_new_connection"}
+	for code, reason := range published {
+		if seen[code] != reason {
+			t.Errorf("%d is %q, want %q", code, seen[code], reason)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package health
+
+import (
+	"net/http"
+	"runtime"
+
+	"github.com/yorukot/blind-party/internal/config"
+	"github.com/yorukot/blind-party/pkg/response"
+)
+
+// HealthHandler serves the liveness and readiness probes
+type HealthHandler struct {
+	// AppName identifies the service in the readiness response
+	AppName string
+
+	// GameCounts returns the number of running games and games kept in memory
+	GameCounts func() (active, total int)
+
+	// SendBufferFull returns how many messages found a client's send buffer full
+	SendBufferFull func() int
+}
+
+// readyResponse is the response of Ready
+type readyResponse struct {
+	Status            string `json:"status"`
+	App               string `json:"app"`
+	ConfigInitialized bool   `json:"config_initialized"`
+	ActiveGames       int    `json:"active_games"`
+	TotalGames        int    `json:"total_games"`
+	Goroutines        int    `json:"goroutines"`       // Steady growth with a flat game count points to a leak
+	SendBufferFull    int    `json:"send_buffer_full"` // Messages that found a client's send buffer full
+}
+
+// Live always responds 200 while the process can serve requests, for load balancers
+func (h *HealthHandler) Live(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("OK"))
+}
+
+// Ready reports whether the server can run games, with basic subsystem signals
+func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	resp := readyResponse{
+		Status:            "ok",
+		App:               h.AppName,
+		ConfigInitialized: config.Initialized(),
+		Goroutines:        runtime.NumGoroutine(),
+	}
+	if h.GameCounts != nil {
+		resp.ActiveGames, resp.TotalGames = h.GameCounts()
+	}
+	if h.SendBufferFull != nil {
+		resp.SendBufferFull = h.SendBufferFull()
+	}
+
+	if !resp.ConfigInitialized {
+		response.Fail(w, http.StatusServiceUnavailable, "NOT_READY", "Config is not initialized")
+		return
+	}
+
+	response.OK(w, resp)
+}
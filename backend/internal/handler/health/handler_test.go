@@ -0,0 +1,52 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/yorukot/blind-party/internal/config"
+)
+
+func TestReadyReportsActiveGames(t *testing.T) {
+	if _, err := config.InitConfig(); err != nil {
+		t.Fatal(err)
+	}
+	active, total := 0, 0
+	h := &HealthHandler{
+		AppName:    "blind-party",
+		GameCounts: func() (int, int) { return active, total },
+	}
+
+	ready := func() readyResponse {
+		rec := httptest.NewRecorder()
+		h.Ready(rec, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+		}
+		var body struct {
+			Data readyResponse `json:"data"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatal(err)
+		}
+		return body.Data
+	}
+
+	if resp := ready(); resp.ActiveGames != 0 || !resp.ConfigInitialized || resp.Goroutines == 0 {
+		t.Errorf("with no games got %+v", resp)
+	}
+	active, total = 2, 3
+	if resp := ready(); resp.ActiveGames != 2 || resp.TotalGames != 3 {
+		t.Errorf("active/total = %d/%d, want 2/3", resp.ActiveGames, resp.TotalGames)
+	}
+}
+
+func TestLiveIsPlain200(t *testing.T) {
+	rec := httptest.NewRecorder()
+	(&HealthHandler{}).Live(rec, httptest.NewRequest(http.MethodGet, "/health/live", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "OK" {
+		t.Errorf("got %d %q", rec.Code, rec.Body)
+	}
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/yorukot/blind-party/pkg/response"
+)
+
+// AdminOnlyMiddleware only lets through requests carrying the admin token as
+// "Authorization: Bearer <token>". With an empty token the routes are disabled.
+func AdminOnlyMiddleware(token string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if token == "" {
+				response.Fail(w, http.StatusForbidden, "ADMIN_DISABLED", "Admin endpoints are disabled")
+				return
+			}
+
+			provided, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+				response.Fail(w, http.StatusUnauthorized, "UNAUTHORIZED", "A valid admin token is required")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/yorukot/blind-party/internal/config"
+	"github.com/yorukot/blind-party/pkg/response"
 )
 
 // ZapLoggerMiddleware is a middleware that logs the incoming request and the response time
@@ -17,6 +18,8 @@ func ZapLoggerMiddleware(logger *zap.Logger) func(http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			requestID := uuid.New().String()
 
+			w.Header().Set(response.RequestIDHeader, requestID)
+
 			start := time.Now()
 
 			next.ServeHTTP(w, r)
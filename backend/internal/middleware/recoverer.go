@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/yorukot/blind-party/pkg/response"
+)
+
+// ZapRecovererMiddleware recovers from panics in handlers, logs them with the
+// stack trace and responds with a 500 error envelope
+func ZapRecovererMiddleware(logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				rec := recover()
+				if rec == nil {
+					return
+				}
+				// Let net/http abort the response as intended
+				if rec == http.ErrAbortHandler {
+					panic(rec)
+				}
+
+				logger.Error("Handler panicked",
+					zap.String("request_id", w.Header().Get(response.RequestIDHeader)),
+					zap.String("path", r.URL.Path),
+					zap.Any("panic", rec),
+					zap.Stack("stack"),
+				)
+
+				if r.Header.Get("Connection") != "Upgrade" {
+					response.Fail(w, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", "Internal Server Error")
+				}
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
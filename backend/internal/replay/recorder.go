@@ -0,0 +1,75 @@
+package replay
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Record is a single line of a replay file
+type Record struct {
+	Timestamp time.Time   `json:"timestamp"`
+	Message   interface{} `json:"message"`
+}
+
+// Recorder tees broadcast messages of a game into an NDJSON file
+type Recorder struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+	enc  *json.Encoder
+}
+
+// NewRecorder creates the replay file for the game inside dir
+func NewRecorder(dir, gameID string, createdAt time.Time) (*Recorder, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create replay dir: %w", err)
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("%s_%d.ndjson", gameID, createdAt.Unix()))
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("create replay file: %w", err)
+	}
+
+	return &Recorder{
+		path: path,
+		file: file,
+		enc:  json.NewEncoder(file),
+	}, nil
+}
+
+// Record appends a message to the replay file
+func (r *Recorder) Record(message interface{}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return os.ErrClosed
+	}
+	return r.enc.Encode(Record{
+		Timestamp: time.Now(),
+		Message:   message,
+	})
+}
+
+// Path returns the location of the replay file
+func (r *Recorder) Path() string {
+	return r.path
+}
+
+// Close closes the replay file, further records are rejected
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
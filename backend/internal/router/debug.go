@@ -0,0 +1,15 @@
+package router
+
+import (
+	"github.com/go-chi/chi/v5"
+	chiMiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+// DebugRouter mounts the pprof profiler under /debug, profiling exposes
+// internals so it is only mounted when enabled
+func DebugRouter(r chi.Router, enabled bool) {
+	if !enabled {
+		return
+	}
+	r.Mount("/debug", chiMiddleware.Profiler())
+}
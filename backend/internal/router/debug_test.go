@@ -0,0 +1,27 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestDebugRouterMountsProfilerOnlyWhenEnabled(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		r := chi.NewRouter()
+		DebugRouter(r, enabled)
+
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/pprof/", nil))
+
+		want := http.StatusNotFound
+		if enabled {
+			want = http.StatusOK
+		}
+		if rec.Code != want {
+			t.Errorf("enabled=%v: GET /debug/pprof/ = %d, want %d", enabled, rec.Code, want)
+		}
+	}
+}
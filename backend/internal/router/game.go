@@ -1,25 +1,89 @@
 package router
 
 import (
+	"time"
+
 	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
 	"golang.org/x/net/websocket"
 
+	"github.com/yorukot/blind-party/internal/config"
 	"github.com/yorukot/blind-party/internal/handler/game"
-	"github.com/yorukot/blind-party/internal/schema"
+	"github.com/yorukot/blind-party/internal/middleware"
+	"github.com/yorukot/blind-party/internal/violation"
+	"github.com/yorukot/blind-party/internal/webhook"
+	"github.com/yorukot/blind-party/pkg/ttlcache"
 )
 
-// GameRouter sets up the game routes
-func GameRouter(r chi.Router) {
+// GameRouter sets up the game routes and returns the handler serving them
+func GameRouter(r chi.Router) *game.GameHandler {
 
 	gameHandler := &game.GameHandler{
-		GameData: make(map[string]*schema.Game),
+		IdempotencyKeys: ttlcache.New(1024, 10*time.Minute),
+		Violations:      newViolationReporter(),
+		Webhooks: webhook.NewNotifier(
+			time.Duration(config.Env().WebhookTimeoutSeconds)*time.Second,
+			config.Env().WebhookMaxRetries,
+			config.Env().WebhookMaxPending,
+			config.Env().WebhookAllowPrivate,
+		),
 	}
 
+	r.Get("/colors", gameHandler.GetColorPalette)
+	r.Get("/ws-schema", gameHandler.GetWSSchema)
+	r.With(middleware.AdminOnlyMiddleware(config.Env().AdminToken)).
+		Get("/metrics", gameHandler.Metrics)
+
 	r.Route("/game", func(r chi.Router) {
 		r.Post("/", gameHandler.NewGame)
 		r.Get("/{gameID}/state", gameHandler.GetGameState)
+		r.Get("/{gameID}/round", gameHandler.GetCurrentRound)
+		r.Get("/{gameID}/round/{number}", gameHandler.GetRound)
+		r.Get("/{gameID}/replay", gameHandler.GetReplay)
+		r.Post("/{gameID}/regenerate-map", gameHandler.RegenerateMap)
+		r.Post("/{gameID}/map", gameHandler.UploadMap)
+		r.Get("/{gameID}/export/players.csv", gameHandler.ExportPlayersCSV)
+		r.Get("/{gameID}/export/rounds.csv", gameHandler.ExportRoundsCSV)
+		r.With(middleware.AdminOnlyMiddleware(config.Env().AdminToken)).
+			Get("/{gameID}/violations", gameHandler.GetViolations)
+		r.With(middleware.AdminOnlyMiddleware(config.Env().AdminToken)).
+			Get("/{gameID}/diagnostics", gameHandler.GetDiagnostics)
+		r.With(middleware.AdminOnlyMiddleware(config.Env().AdminToken)).
+			Get("/{gameID}/connections", gameHandler.GetConnectionLog)
+		r.With(middleware.AdminOnlyMiddleware(config.Env().AdminToken)).
+			Post("/{gameID}/pause", gameHandler.PauseGame)
+		r.With(middleware.AdminOnlyMiddleware(config.Env().AdminToken)).
+			Post("/{gameID}/resume", gameHandler.ResumeGame)
 		r.Route("/{gameID}", func(r chi.Router) {
-			r.Handle("/ws", websocket.Handler(gameHandler.ConnectWebSocket))
+			r.Handle("/ws", websocket.Server{
+				Handler:   gameHandler.ConnectWebSocket,
+				Handshake: game.NegotiateSubprotocol,
+			})
+			r.Handle("/observe", websocket.Server{
+				Handler:   gameHandler.ObserveWebSocket,
+				Handshake: game.NegotiateSubprotocol,
+			})
 		})
 	})
+
+	return gameHandler
+}
+
+// newViolationReporter creates the configured violation sink, falling back to
+// memory if the file can't be opened
+func newViolationReporter() violation.Reporter {
+	memory := violation.NewMemoryReporter(
+		config.Env().ViolationMaxPerGame,
+		time.Duration(config.Env().ViolationRetentionMinutes)*time.Minute,
+	)
+	if config.Env().ViolationSink != "file" {
+		return memory
+	}
+
+	reporter, err := violation.NewFileReporter(config.Env().ViolationFile)
+	if err != nil {
+		zap.L().Error("Failed to open violations file, keeping violations in memory", zap.Error(err))
+		return memory
+	}
+	return reporter
 }
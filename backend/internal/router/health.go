@@ -0,0 +1,24 @@
+package router
+
+import (
+	"github.com/go-chi/chi/v5"
+
+	"github.com/yorukot/blind-party/internal/config"
+	"github.com/yorukot/blind-party/internal/handler/health"
+)
+
+// HealthRouter sets up the health probe routes
+func HealthRouter(r chi.Router, gameCounts func() (active, total int), sendBufferFull func() int) {
+
+	healthHandler := &health.HealthHandler{
+		AppName:        config.Env().AppName,
+		GameCounts:     gameCounts,
+		SendBufferFull: sendBufferFull,
+	}
+
+	r.Route("/health", func(r chi.Router) {
+		r.Get("/", healthHandler.Live)
+		r.Get("/live", healthHandler.Live)
+		r.Get("/ready", healthHandler.Ready)
+	})
+}
@@ -0,0 +1,25 @@
+package schema
+
+// Avatars are the skins a player may pick when joining, rendered by clients
+var Avatars = []string{
+	"steve",
+	"alex",
+	"creeper",
+	"zombie",
+	"skeleton",
+	"enderman",
+	"villager",
+	"pig",
+	"sheep",
+	"chicken",
+}
+
+// ValidAvatar reports whether the avatar is in the allowlist
+func ValidAvatar(avatar string) bool {
+	for _, allowed := range Avatars {
+		if avatar == allowed {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package schema
+
+// ColorInfo describes how a wool color is presented to clients. Symbol is a
+// pattern id so color-blind players can tell colors apart without hue.
+type ColorInfo struct {
+	ID     WoolColor `json:"id"`
+	Name   string    `json:"name"`
+	Hex    string    `json:"hex"`
+	Symbol string    `json:"symbol"`
+	Label  string    `json:"label"`
+}
+
+// ColorPalette is the single source of truth for color presentation, indexed by WoolColor
+var ColorPalette = [...]ColorInfo{
+	White:     {ID: White, Name: "White", Hex: "#E9ECEC", Symbol: "solid", Label: "WHT"},
+	Orange:    {ID: Orange, Name: "Orange", Hex: "#F07613", Symbol: "stripes", Label: "ORG"},
+	Magenta:   {ID: Magenta, Name: "Magenta", Hex: "#BD44B3", Symbol: "dots", Label: "MAG"},
+	LightBlue: {ID: LightBlue, Name: "Light Blue", Hex: "#3AAFD9", Symbol: "waves", Label: "LBL"},
+	Yellow:    {ID: Yellow, Name: "Yellow", Hex: "#F8C627", Symbol: "stars", Label: "YEL"},
+	Lime:      {ID: Lime, Name: "Lime", Hex: "#70B919", Symbol: "checker", Label: "LIM"},
+	Pink:      {ID: Pink, Name: "Pink", Hex: "#ED8DAC", Symbol: "hearts", Label: "PNK"},
+	Gray:      {ID: Gray, Name: "Gray", Hex: "#3E4447", Symbol: "grid", Label: "GRY"},
+	LightGray: {ID: LightGray, Name: "Light Gray", Hex: "#8E8E86", Symbol: "dashes", Label: "LGR"},
+	Cyan:      {ID: Cyan, Name: "Cyan", Hex: "#158991", Symbol: "circles", Label: "CYN"},
+	Purple:    {ID: Purple, Name: "Purple", Hex: "#792AAC", Symbol: "diamonds", Label: "PUR"},
+	Blue:      {ID: Blue, Name: "Blue", Hex: "#35399D", Symbol: "cross", Label: "BLU"},
+	Brown:     {ID: Brown, Name: "Brown", Hex: "#724728", Symbol: "bricks", Label: "BRN"},
+	Green:     {ID: Green, Name: "Green", Hex: "#546D1B", Symbol: "triangles", Label: "GRN"},
+	Red:       {ID: Red, Name: "Red", Hex: "#A12722", Symbol: "zigzag", Label: "RED"},
+	Black:     {ID: Black, Name: "Black", Hex: "#141519", Symbol: "diagonal", Label: "BLK"},
+	Air:       {ID: Air, Name: "Air", Hex: "", Symbol: "none", Label: "AIR"},
+}
+
+// Info returns the palette entry for the color, or an Unknown entry if it is out of range
+func (c WoolColor) Info() ColorInfo {
+	if c < 0 || int(c) >= len(ColorPalette) {
+		return ColorInfo{ID: c, Name: "Unknown", Symbol: "none", Label: "???"}
+	}
+	return ColorPalette[c]
+}
+
+// String returns the palette name of the color, so logs read "Red" instead of 14
+func (c WoolColor) String() string {
+	return c.Info().Name
+}
@@ -0,0 +1,41 @@
+package schema
+
+import "testing"
+
+func TestColorPaletteIsConsistent(t *testing.T) {
+	symbols := make(map[string]WoolColor)
+	labels := make(map[string]WoolColor)
+	for i, info := range ColorPalette {
+		if info.ID != WoolColor(i) {
+			t.Errorf("entry %d has ID %d", i, info.ID)
+		}
+		if info.Name == "" || info.Symbol == "" || info.Label == "" {
+			t.Errorf("entry %d is incomplete: %+v", i, info)
+		}
+		if info.ID != Air && info.Hex == "" {
+			t.Errorf("%s has no hex color", info.Name)
+		}
+		if other, taken := symbols[info.Symbol]; taken {
+			t.Errorf("%s and %s share the symbol %s", other, info.ID, info.Symbol)
+		}
+		if other, taken := labels[info.Label]; taken {
+			t.Errorf("%s and %s share the label %s", other, info.ID, info.Label)
+		}
+		symbols[info.Symbol] = info.ID
+		labels[info.Label] = info.ID
+	}
+}
+
+func TestColorInfo(t *testing.T) {
+	if info := Red.Info(); info.Name != "Red" || info.Symbol != "zigzag" {
+		t.Errorf("Red.Info() = %+v", info)
+	}
+	if Red.String() != "Red" {
+		t.Errorf("Red.String() = %q", Red.String())
+	}
+	for _, color := range []WoolColor{-1, WoolColor(len(ColorPalette))} {
+		if info := color.Info(); info.Name != "Unknown" || info.ID != color {
+			t.Errorf("WoolColor(%d).Info() = %+v, want Unknown", color, info)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package schema
+
+import "time"
+
+// ConnectionEvent is what happened to a client's connection
+type ConnectionEvent string
+
+const (
+	ConnectionConnect    ConnectionEvent = "connect"
+	ConnectionDisconnect ConnectionEvent = "disconnect"
+	ConnectionTimeout    ConnectionEvent = "timeout" // Nothing was received within the read timeout
+	ConnectionKick       ConnectionEvent = "kick"    // Kicked by anti-cheat
+)
+
+// ConnectionLogEntry is one connection event of a game's audit trail
+type ConnectionLogEntry struct {
+	UserID    string          `json:"user_id"` // Empty for clients connecting without one
+	Username  string          `json:"username"`
+	Event     ConnectionEvent `json:"event"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+// ConnectionLog is a bounded ring buffer of a game's connection events, oldest
+// evicted first. Like PositionHistory it is not safe for concurrent use,
+// callers hold the game's lock.
+type ConnectionLog struct {
+	entries []ConnectionLogEntry
+	next    int // Where the next entry is written
+	count   int
+}
+
+// NewConnectionLog creates a log keeping the last capacity events, at least one
+func NewConnectionLog(capacity int) *ConnectionLog {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &ConnectionLog{entries: make([]ConnectionLogEntry, capacity)}
+}
+
+// Add records an event for the client, evicting the oldest one once the log is full
+func (l *ConnectionLog) Add(client *WebSocketClient, event ConnectionEvent, at time.Time) {
+	l.entries[l.next] = ConnectionLogEntry{UserID: client.UserID, Username: client.Username, Event: event, Timestamp: at}
+	l.next = (l.next + 1) % len(l.entries)
+	if l.count < len(l.entries) {
+		l.count++
+	}
+}
+
+// Entries returns a copy of the events kept, oldest first
+func (l *ConnectionLog) Entries() []ConnectionLogEntry {
+	entries := make([]ConnectionLogEntry, 0, l.count)
+	start := (l.next - l.count + len(l.entries)) % len(l.entries)
+	for i := 0; i < l.count; i++ {
+		entries = append(entries, l.entries[(start+i)%len(l.entries)])
+	}
+	return entries
+}
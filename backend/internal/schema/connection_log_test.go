@@ -0,0 +1,38 @@
+package schema
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConnectionLogKeepsTheLatestInOrder(t *testing.T) {
+	log := NewConnectionLog(3)
+	if entries := log.Entries(); len(entries) != 0 {
+		t.Fatalf("new log has %d entries", len(entries))
+	}
+
+	start := time.Now()
+	events := []ConnectionEvent{ConnectionConnect, ConnectionTimeout, ConnectionConnect, ConnectionKick, ConnectionDisconnect}
+	for i, event := range events {
+		log.Add(&WebSocketClient{Username: "alice", UserID: "id-alice"}, event, start.Add(time.Duration(i)*time.Second))
+	}
+
+	entries := log.Entries()
+	if len(entries) != 3 {
+		t.Fatalf("%d entries, want 3", len(entries))
+	}
+	for i, entry := range entries {
+		if entry.Event != events[i+2] || !entry.Timestamp.Equal(start.Add(time.Duration(i+2)*time.Second)) {
+			t.Errorf("entry %d = %+v, want the %s at %ds", i, entry, events[i+2], i+2)
+		}
+		if entry.UserID != "id-alice" || entry.Username != "alice" {
+			t.Errorf("entry %d is for %s (%s)", i, entry.Username, entry.UserID)
+		}
+	}
+
+	// Entries is a copy
+	entries[0].Event = ConnectionKick
+	if log.Entries()[0].Event != ConnectionConnect {
+		t.Error("changing the returned entries changed the log")
+	}
+}
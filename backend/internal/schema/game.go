@@ -1,10 +1,13 @@
 package schema
 
 import (
+	"math/rand"
 	"sync"
 	"time"
 
 	"golang.org/x/net/websocket"
+
+	"github.com/yorukot/blind-party/internal/replay"
 )
 
 // WoolColor represents the 16 wool colors in Minecraft
@@ -47,6 +50,17 @@ const (
 	EliminationCheck RoundPhase = "elimination-check"
 )
 
+// EliminationReason describes why a player was eliminated
+type EliminationReason string
+
+const (
+	EliminatedOutOfBounds EliminationReason = "out_of_bounds"
+	EliminatedOnAir       EliminationReason = "air"
+	EliminatedWrongColor  EliminationReason = "wrong_color"
+	EliminatedForfeit     EliminationReason = "forfeit"
+	EliminatedSlowest     EliminationReason = "slowest"
+)
+
 // Position represents x,y coordinates
 type Position struct {
 	X float64 `json:"pos_x"`
@@ -56,16 +70,45 @@ type Position struct {
 // Player represents a player in the game
 type Player struct {
 	Name         string    `json:"name"`
-	Position     Position  `json:"position"` // For JSON marshaling
+	DisplayName  string    `json:"display_name"`     // Name, numbered when another player's name differs only in case or spacing
+	Avatar       string    `json:"avatar,omitempty"` // One of Avatars, empty for the client default
+	UserID       string    `json:"-"`                // Set when the player connected with a user_id, only that user can take over the name
+	Position     Position  `json:"position"`         // For JSON marshaling
 	IsSpectator  bool      `json:"is_spectator"`
 	IsEliminated bool      `json:"is_eliminated"`
 	JoinedRound  int       `json:"joined_round"`
+	ImmuneRound  int       `json:"-"`            // Round the player can't be eliminated in, set for late joiners
+	IsDowned     bool      `json:"is_downed"`    // Used a revive this round, respawns next round
+	RevivesLeft  int       `json:"revives_left"` // Revive tokens remaining
+	LivesLeft    int       `json:"lives_left"`   // Lives remaining, the last one is lost on elimination
+	LostLifeIn   int       `json:"lost_life_in"` // Last round a life was lost in, 0 if none
+	SparedIn     int       `json:"-"`            // Last round the elimination cap spared the player in, 0 if none
 	LastUpdate   time.Time `json:"-"`
 
 	// Movement validation
 	LastValidPosition Position  `json:"-"`
 	LastMoveTime      time.Time `json:"-"`
 	MovementSpeed     float64   `json:"-"` // blocks per second
+	LastSeq           int64     `json:"-"` // Sequence number of the last accepted position update
+	ViolationCount    int       `json:"-"` // Movement updates rejected by anti-cheat
+	FrozenUntil       time.Time `json:"-"` // Input is ignored until then in freeze mode
+	SpawnGrace        bool      `json:"-"` // Just (re)spawned, the next update may correct the spawn point
+	SettledAt         time.Time `json:"-"` // Last accepted move during the color call, when the player settled on their block
+	RoundDistance     float64   `json:"-"` // Blocks traveled in DistanceRound, capped into distance points
+	DistanceRound     int       `json:"-"`
+
+	// Connection quality
+	SmoothedRTT time.Duration `json:"-"`
+	RTTMs       int           `json:"rtt_ms"`       // Smoothed round-trip time
+	StalenessMs int           `json:"staleness_ms"` // Time since the last position update
+
+	// Coaching hints
+	SafetyHintAt   time.Time `json:"-"` // When the last safety_hint was sent
+	SafetyHintSafe bool      `json:"-"` // Whether the last safety_hint said safe
+
+	// Spectator hazards
+	HazardsDropped int       `json:"hazards_dropped"`
+	LastHazardAt   time.Time `json:"-"` // For the cooldown between drops
 
 	// Stats for settlement
 	Stats PlayerStats `json:"-"`
@@ -77,16 +120,137 @@ type PlayerStats struct {
 	TotalDistance  float64    `json:"total_distance"`
 	EliminatedAt   *time.Time `json:"eliminated_at,omitempty"`
 	FinalPosition  int        `json:"final_position"`
+	DownedRounds   int        `json:"downed_rounds"` // Rounds lost but revived, not counted as survived
+
+	EliminationBonus int            `json:"elimination_bonus"`      // Awarded at game end for the players outlasted
+	WinnerBonus      int            `json:"winner_bonus"`           // FinalWinnerBonus, for every player sharing the win
+	SurvivalPoints   int            `json:"survival_points"`        // SurvivalPointsPerRound for every round survived, scaled late in the game
+	HookBonuses      map[string]int `json:"hook_bonuses,omitempty"` // Points from the game's score hooks, by label
+	DistancePoints   int            `json:"distance_points"`        // DistancePointsPer for every block traveled in a round survived, capped per round
+	CatchupBonuses   int            `json:"catchup_bonuses"`        // CatchupBonus for every round survived below the survivors' median score
+	SpeedBonuses     int            `json:"speed_bonuses"`          // SpeedBonusPoints for every round survived settling within SpeedBonusThreshold seconds
+
+	CurrentStreak int `json:"current_streak"` // Rounds survived cleanly in a row, reset by losing a life or being spared
+	LongestStreak int `json:"longest_streak"`
+
+	AverageResponseTime float64 `json:"average_response_time"` // Seconds from the color call to settling on a block, over the rounds scored
+	ResponseRounds      int     `json:"-"`
+}
+
+// BonusFormula selects how the elimination bonus grows with the players outlasted
+type BonusFormula string
+
+const (
+	BonusLinear           BonusFormula = "linear"            // multiplier per player outlasted
+	BonusPlacementSquared BonusFormula = "placement_squared" // multiplier times players outlasted squared, favors the top spots
+	BonusFlat             BonusFormula = "flat"              // multiplier for outlasting anyone at all
+)
+
+// MapSymmetry selects how generated maps are mirrored so no spawn area is luckier
+type MapSymmetry string
+
+const (
+	MapSymmetryNone       MapSymmetry = ""           // Fully random
+	MapSymmetryMirror     MapSymmetry = "mirror"     // Mirrored across both center lines, four equivalent quadrants
+	MapSymmetryRotational MapSymmetry = "rotational" // Unchanged by a half turn, two equivalent halves
+)
+
+// SpawnMode selects how players are placed at the start of a game
+type SpawnMode string
+
+const (
+	SpawnRandom SpawnMode = "random" // Any spawnable blocks, picked at random
+	SpawnSpread SpawnMode = "spread" // Blocks as far from each other as possible
+)
+
+// EliminationCapMode selects who is eliminated first when more players are
+// caught in a round than MaxEliminationsPerRound allows
+type EliminationCapMode string
+
+const (
+	EliminationCapSlowest EliminationCapMode = "slowest" // The slowest responders, as in party mode
+	EliminationCapRandom  EliminationCapMode = "random"  // Picked at random
+)
+
+// AntiCheatMode selects the penalty for an invalid movement update
+type AntiCheatMode string
+
+const (
+	AntiCheatReset  AntiCheatMode = "reset"  // Snap the player back to their last valid position
+	AntiCheatFreeze AntiCheatMode = "freeze" // Ignore the player's input for FreezeDurationMs
+	AntiCheatKick   AntiCheatMode = "kick"   // Reset, and disconnect after KickAfterViolations
+)
+
+// EliminationRecord captures where a player was eliminated and their
+// connection quality at that moment, for settling lag disputes
+type EliminationRecord struct {
+	Name        string            `json:"name"`
+	RoundNumber int               `json:"round_number"`
+	Reason      EliminationReason `json:"reason"`
+	Position    Position          `json:"position"`
+	RTTMs       int               `json:"rtt_ms"`
+	StalenessMs int               `json:"staleness_ms"`
+	CalledColor WoolColor         `json:"called_color"` // The round's called color, the first safe one
+}
+
+// SpectatorSwitch is an eliminated player whose switch_to_spectator notice
+// hasn't been sent yet
+type SpectatorSwitch struct {
+	Player *Player
+	Reason EliminationReason
 }
 
 // Round represents a single round in the game
 type Round struct {
-	Number          int        `json:"round_number"`
-	Phase           RoundPhase `json:"phase"`
-	StartTime       time.Time  `json:"start_time"`
-	EndTime         *time.Time `json:"end_time,omitempty"`
-	ColorToShow     WoolColor  `json:"color_to_show"`
-	RushDuration    float64    `json:"rush_duration"` // Variable timing by round
+	Number       int         `json:"round_number"`
+	Phase        RoundPhase  `json:"phase"`
+	StartTime    time.Time   `json:"start_time"`
+	EndTime      *time.Time  `json:"end_time,omitempty"`
+	ColorToShow  WoolColor   `json:"color_to_show"`
+	ColorsToShow []WoolColor `json:"colors_to_show"` // Every safe color, ColorToShow first
+	RushDuration float64     `json:"rush_duration"`  // Variable timing by round
+	Warmup       bool        `json:"warmup"`         // Practice round, nobody is eliminated or scores
+
+	PhaseStartedAt   time.Time           `json:"-"` // When the current phase began, for the watchdog
+	PositionSnapshot map[string]Position `json:"-"` // Positions when the rush ended, judged by the elimination check
+
+	EliminatedCount    int                       `json:"eliminated_count"`
+	EliminationReasons map[EliminationReason]int `json:"elimination_reasons"`
+
+	// Blocks eliminated players turned to Air for this round
+	Hazards []Hazard `json:"hazards,omitempty"`
+}
+
+// IsSafe reports whether standing on the color survives the round
+func (r *Round) IsSafe(color WoolColor) bool {
+	for _, safe := range r.ColorsToShow {
+		if color == safe {
+			return true
+		}
+	}
+	return false
+}
+
+// Clone returns a copy of the round that shares nothing the game loop
+// changes, for messages marshaled by client writers outside the game's lock.
+// The position snapshot is server-only and left out. A nil round stays nil.
+func (r *Round) Clone() *Round {
+	if r == nil {
+		return nil
+	}
+	clone := *r
+	if r.EndTime != nil {
+		endTime := *r.EndTime
+		clone.EndTime = &endTime
+	}
+	clone.ColorsToShow = append([]WoolColor(nil), r.ColorsToShow...)
+	clone.Hazards = append([]Hazard(nil), r.Hazards...)
+	clone.PositionSnapshot = nil
+	clone.EliminationReasons = make(map[EliminationReason]int, len(r.EliminationReasons))
+	for reason, count := range r.EliminationReasons {
+		clone.EliminationReasons[reason] = count
+	}
+	return &clone
 }
 
 // MapData represents the 20x20 game map
@@ -96,31 +260,106 @@ type MapData [20][20]WoolColor
 type WebSocketClient struct {
 	Conn      *websocket.Conn
 	Username  string
+	UserID    string // Optional ?user_id, ties the username to one user
 	Token     string
+	Avatar    string // Requested on connect, validated against Avatars
+	Gzip      bool   // Client opted in to gzipped large messages with ?compress=gzip
 	Send      chan interface{}
 	Connected time.Time
+
+	// Set before Send is closed by the server so the writer can tell the
+	// client why it is being disconnected
+	CloseCode   int
+	CloseReason string
+
+	// Why the connection ended, for the connection log. TimedOut is set by
+	// the client's reader before it unregisters, Kicked under the game's lock.
+	TimedOut bool
+	Kicked   bool
 }
 
 // GameConfig holds configuration for the game
 type GameConfig struct {
 	MapWidth            int   `json:"map_width"`             // 20
 	MapHeight           int   `json:"map_height"`            // 20
-	CountdownSequence   []int `json:"countdown_sequence"`    // [30, 25, 20, 15, 10, 8, 6, 4, 3, 2]
+	MapSeed             int64 `json:"map_seed,omitempty"`    // 0 picks a random seed
+	AutoSizeMap         bool  `json:"auto_size_map"`         // false, fit the map size to the player count at game start
+	MapCellsPerPlayer   int   `json:"map_cells_per_player"`  // 25, blocks per player inside the border when auto sizing
 	SpectatorOnlyRounds int   `json:"spectator_only_rounds"` // Last 2 rounds
+	BorderThickness     int   `json:"border_thickness"`      // 0, outer rings of cells that are always Air
+	LateJoinRounds      int   `json:"late_join_rounds"`      // 3, players joining up to this round play instead of spectating
+	MaxSpectators       int   `json:"max_spectators"`        // 20, spectators allowed per game on top of the players, 0 for no cap
+	RevivesPerPlayer    int   `json:"revives_per_player"`    // 0, casual mode revive tokens per player
+	Lives               int   `json:"lives"`                 // 1, wrong colors a player can stand on before being eliminated
+	UniqueAvatars       bool  `json:"unique_avatars"`        // false, reject an avatar already picked in the room
+	UniqueNames         bool  `json:"unique_names"`          // false, reject a name differing from a player's only in case or spacing instead of numbering it
+	InitialSafeColors   int   `json:"initial_safe_colors"`   // 1, safe colors called in the first round
+	SafeColorDecay      int   `json:"safe_color_decay"`      // 3, rounds between each drop of one safe color, down to 1
+
+	// Symmetric maps give every spawn the same surroundings, for ranked play
+	SymmetricMap MapSymmetry `json:"symmetric_map,omitempty"` // Empty for fully random maps
+
+	// Spawn placement
+	SpawnMode SpawnMode `json:"spawn_mode"` // random, spread keeps players apart at the start
+
+	// Spawn diversity: no player starts a round surrounded by a single color
+	GuaranteeSpawnDiversity bool `json:"guarantee_spawn_diversity"` // false, recolor blocks around each player on a generated map
+	SpawnDiversityColors    int  `json:"spawn_diversity_colors"`    // 4, distinct colors each player gets within the radius
+	SpawnDiversityRadius    int  `json:"spawn_diversity_radius"`    // 1, blocks around the player's block that count
+
+	// Scarcity ramp: late rounds call colors with fewer blocks on the map
+	ScarcityRampStartRound int `json:"scarcity_ramp_start_round"` // 0 disables, first round that favors rare colors
+	ScarcityRampRounds     int `json:"scarcity_ramp_rounds"`      // 5, rounds until the bias reaches full strength
+
+	// Party mode: keep the game moving when nobody slips up
+	ForceEliminationEachRound bool `json:"force_elimination_each_round"` // false, a round everyone survives eliminates the slowest responder
+
+	// Elimination cap: keep a brutal round from wiping out most of the players
+	MaxEliminationsPerRound int                `json:"max_eliminations_per_round"` // 0 disables, the caught players past it are spared with a warning
+	EliminationCapMode      EliminationCapMode `json:"elimination_cap_mode"`       // slowest, who is eliminated first when the cap applies
+
+	// Revenge of the eliminated: eliminated players can knock blocks out of the next round
+	SpectatorHazards    bool `json:"spectator_hazards"`     // false
+	HazardsPerSpectator int  `json:"hazards_per_spectator"` // 3, drops each eliminated player gets
+	HazardCooldownMs    int  `json:"hazard_cooldown_ms"`    // 2000, minimum time between a player's drops
+
+	// Hard cap on a game's length, so a game can't hold the server indefinitely
+	MaxGameDurationSeconds float64 `json:"max_game_duration_seconds"` // 0 disables, once exceeded the game ends ranked by score
+
+	// Breather between two rounds
+	RoundBreatherSeconds float64 `json:"round_breather_seconds"` // 2, announced as next_round_in when a round ends
+
+	// Notices to eliminated players
+	BatchEliminationNotices bool `json:"batch_elimination_notices"` // true, send the notices of a mass elimination in one pass once it's judged
 
-	// Timing Progression (rush phase duration by round ranges)
-	TimingProgression []TimingRange `json:"timing_progression"`
+	// Lobby auto-start
+	AutoStartSeconds       float64 `json:"auto_start_seconds"`        // Countdown once MinPlayers have joined
+	AutoStartCapacityRatio float64 `json:"auto_start_capacity_ratio"` // Start immediately at this fraction of MaxPlayers, 0 disables
+	LobbyUpdateIntervalMs  int     `json:"lobby_update_interval_ms"`  // 250ms, joins and leaves are coalesced into one lobby_update per interval
+	FirstRoundGraceSeconds float64 `json:"first_round_grace_seconds"` // 3, pause between game start and the first round
+	WarmupRounds           int     `json:"warmup_rounds"`             // 0, practice rounds at the start that don't eliminate or score
+	PreGameTimeoutSeconds  float64 `json:"pre_game_timeout_seconds"`  // 600, a lobby still short of MinPlayers after this is abandoned, 0 disables
+
+	// Timing Progression (rush phase duration by round ranges), empty for 20s
+	// decaying by 20% each round down to 1.2s
+	TimingProgression []TimingRange `json:"timing_progression,omitempty"`
 
 	// Scoring Configuration
-	SurvivalPointsPerRound     int         `json:"survival_points_per_round"`    // 10
-	EliminationBonusMultiplier int         `json:"elimination_bonus_multiplier"` // 5
-	SpeedBonusThreshold        float64     `json:"speed_bonus_threshold"`        // 1.0 second
-	PerfectBonusThreshold      float64     `json:"perfect_bonus_threshold"`      // 2.0 seconds
-	SpeedBonusPoints           int         `json:"speed_bonus_points"`           // 2
-	PerfectBonusPoints         int         `json:"perfect_bonus_points"`         // 50
-	FinalWinnerBonus           int         `json:"final_winner_bonus"`           // 100
-	EnduranceBonus             int         `json:"endurance_bonus"`              // 200
-	StreakBonuses              map[int]int `json:"streak_bonuses"`               // {3: 30, 5: 75, 10: 200}
+	SurvivalPointsPerRound     int          `json:"survival_points_per_round"`    // 10
+	LateRoundThreshold         int          `json:"late_round_threshold"`         // 10, rounds after this earn LateRoundScoreMultiplier, 0 disables
+	LateRoundScoreMultiplier   float64      `json:"late_round_score_multiplier"`  // 2.0
+	EliminationBonusMultiplier int          `json:"elimination_bonus_multiplier"` // 5
+	EliminationBonusFormula    BonusFormula `json:"elimination_bonus_formula"`    // linear
+	SpeedBonusThreshold        float64      `json:"speed_bonus_threshold"`        // 1.0 second
+	PerfectBonusThreshold      float64      `json:"perfect_bonus_threshold"`      // 2.0 seconds
+	SpeedBonusPoints           int          `json:"speed_bonus_points"`           // 2
+	PerfectBonusPoints         int          `json:"perfect_bonus_points"`         // 50
+	FinalWinnerBonus           int          `json:"final_winner_bonus"`           // 100
+	ScoreToWin                 int          `json:"score_to_win"`                 // 0 disables, the first player to reach this many points wins
+	EnduranceBonus             int          `json:"endurance_bonus"`              // 200
+	StreakBonuses              map[int]int  `json:"streak_bonuses"`               // {3: 30, 5: 75, 10: 200}
+	ScoreHooks                 []string     `json:"score_hooks,omitempty"`        // Named custom bonuses run at the end of every round, e.g. comeback, streak
+	CatchupBonus               int          `json:"catchup_bonus"`                // 0 disables, points each round for survivors below the median score
 
 	// Movement & Anti-cheat
 	BaseMovementSpeed float64 `json:"base_movement_speed"` // 4.0 blocks/second
@@ -128,6 +367,40 @@ type GameConfig struct {
 	LagCompensationMs int     `json:"lag_compensation_ms"` // 100ms
 	PositionUpdateHz  int     `json:"position_update_hz"`  // 10 Hz
 	TimerUpdateHz     int     `json:"timer_update_hz"`     // 20 Hz
+	CellEpsilon       float64 `json:"cell_epsilon"`        // 1e-6, positions this close below a cell boundary count as on it
+
+	LockDuringColorCall bool    `json:"lock_during_color_call"` // false, reject movement while the color is being called
+	MaxPhaseSeconds     float64 `json:"max_phase_seconds"`      // 30, a round phase running longer is force-finished
+	EliminationSnapshot bool    `json:"elimination_snapshot"`   // true, judge positions captured the moment the rush ends
+
+	// Anti-cheat penalty for movement faster than MaxMovementSpeed
+	AntiCheatMode       AntiCheatMode `json:"anti_cheat_mode"`       // reset
+	FreezeDurationMs    int           `json:"freeze_duration_ms"`    // 1000ms, input ignored after a violation in freeze mode
+	KickAfterViolations int           `json:"kick_after_violations"` // 5, violations before a kick in kick mode
+
+	// Connection quality
+	PingIntervalMs       int `json:"ping_interval_ms"`        // 2000ms, how often clients are pinged for RTT
+	StalenessThresholdMs int `json:"staleness_threshold_ms"`  // 250ms, staleness above this extends lag compensation
+	MaxLagCompensationMs int `json:"max_lag_compensation_ms"` // 300ms, cap for the extended window
+	ClientSendBuffer     int `json:"client_send_buffer"`      // 256, messages queued per client before it is dropped as unresponsive
+
+	// Photo finish: give the last two players a longer, announced showdown
+	FinalTwoSlowMo float64 `json:"final_two_slow_mo"` // 0 disables, rush duration multiplier while exactly two players remain
+
+	// Hard mode: the color call lists the colors to avoid instead of the safe ones
+	HardMode bool `json:"hard_mode"` // false
+
+	// Active play: reward moving around, capped so jittering in place doesn't farm points
+	DistancePointsPer      float64 `json:"distance_points_per"`       // 0 disables, points per block traveled in a round survived
+	DistancePointsRoundCap int     `json:"distance_points_round_cap"` // 5, most distance points a player can earn in one round
+
+	// Coaching, keep off for competitive games
+	SafetyHints          bool `json:"safety_hints"`            // false, privately tell each player during the rush whether their block is safe
+	SafetyHintIntervalMs int  `json:"safety_hint_interval_ms"` // 500ms, minimum time between two hints to a player
+	ShowColorOdds        bool `json:"show_color_odds"`         // false, broadcast each color's share of the map when it changes
+
+	// Spectacle
+	MilestoneThresholds []int `json:"milestone_thresholds"` // [10, 5, 3, 2], alive counts that trigger a milestone event
 
 	// Map Changes
 	MapChangeRounds    []int `json:"map_change_rounds"`     // Rounds when colors are removed
@@ -144,39 +417,146 @@ type TimingRange struct {
 // Game represents the main game structure
 type Game struct {
 	// Basic Information
-	ID        string     `json:"game_id"`
-	CreatedAt time.Time  `json:"created_at"`
-	StartedAt *time.Time `json:"started_at,omitempty"`
-	EndedAt   *time.Time `json:"ended_at,omitempty"`
+	ID        string        `json:"game_id"`
+	CreatedAt time.Time     `json:"created_at"`
+	StartedAt *time.Time    `json:"started_at,omitempty"`
+	EndedAt   *time.Time    `json:"ended_at,omitempty"`
+	PausedAt  *time.Time    `json:"paused_at,omitempty"` // Set while an admin has the game paused
+	PausedFor time.Duration `json:"-"`                   // Total time spent paused, kept off lobby timeouts
+	HostID    string        `json:"-"`                   // user_id of the creator, allowed to manage the room. Private, it's the host's only credential
+
+	// Where game events are POSTed, kept private since it may carry a secret
+	WebhookURL string `json:"-"`
+
+	// The winner_announced results, sent as final_results to clients connecting in settlement
+	FinalResults map[string]interface{} `json:"-"`
+
+	// Eliminated players waiting for their switch_to_spectator, see BatchEliminationNotices
+	PendingSpectatorSwitches []SpectatorSwitch `json:"-"`
+
+	// Hazards dropped by eliminated players, applied to the next round's map
+	PendingHazards []Hazard `json:"-"`
+
+	// Send buffer depths and broadcast latency, for diagnosing laggy games
+	SendMetrics SendMetrics `json:"-"`
 
 	// Game State
-	Phase        GamePhase `json:"phase"`
-	CurrentRound *Round    `json:"current_round,omitempty"`
+	Phase        GamePhase  `json:"phase"`
+	CurrentRound *Round     `json:"current_round,omitempty"`
 	RoundNumber  int        `json:"round_number"`
-	Map          MapData   `json:"-"`   // Use MapToArray() for JSON
-	MapArray     [][]int   `json:"map"` // Flattened map for JSON
-	Countdown    *float64      `json:"countdown_seconds,omitempty"`
+	Map          MapData    `json:"-"`        // Use MapToArray() for JSON
+	MapSeed      int64      `json:"map_seed"` // Seed of the initial map and the game's Rand
+	Rand         *rand.Rand `json:"-"`        // Seeded from MapSeed, drives round maps and colors
+	MapArray     [][]int    `json:"map"`      // Flattened map for JSON
+	Countdown    *float64   `json:"countdown_seconds,omitempty"`
+
+	// The host uploaded the map, it is played in the first round
+	CustomMap bool `json:"custom_map"`
 
 	// Players
-	Players               map[string]*Player  `json:"-"`
-	PlayersList           []*Player           `json:"players"` // For JSON marshaling
-	PlayerPositionHistory map[string]Position `json:"-"`       // For movement validation
-	PlayerCount           int                 `json:"player_count"`
-	AliveCount            int                 `json:"alive_count"`
+	Players               map[string]*Player          `json:"-"`
+	PlayersList           []*Player                   `json:"players"` // For JSON marshaling
+	PlayerPositionHistory map[string]*PositionHistory `json:"-"`       // Recent accepted positions, for anti-cheat and lag compensation
+	PlayerCount           int                         `json:"player_count"`
+	SpectatorCount        int                         `json:"spectator_count"`
+	AliveCount            int                         `json:"alive_count"`
+	Eliminations          []EliminationRecord         `json:"eliminations"` // In elimination order
+	Rounds                []*Round                    `json:"-"`            // Every round played, for exports
+	MilestonesReached     map[int]bool                `json:"-"`            // Milestone thresholds already broadcast
 
 	// WebSocket Management
 	Clients    map[string]*WebSocketClient `json:"-"`
-	Broadcast  chan interface{}            `json:"-"`
+	Observers  map[*WebSocketClient]bool   `json:"-"` // Read-only streams, not players
+	Broadcast  chan QueuedMessage          `json:"-"`
 	Register   chan *WebSocketClient       `json:"-"`
 	Unregister chan *WebSocketClient       `json:"-"`
 
+	// Initial state shared by clients connecting before the state changes, nil once stale
+	InitialState *InitialStateCache `json:"-"`
+
+	// Audit trail of connects and disconnects, created on the first event
+	ConnectionLog *ConnectionLog `json:"-"`
+
+	// Replay recording, nil when disabled
+	Replay *replay.Recorder `json:"-"`
+
 	// Configuration
 	Config GameConfig `json:"config"`
 
 	// Synchronization
-	Mu                    sync.RWMutex
-	Ticker                *time.Ticker
-	StopTicker            chan bool
+	Mu                    sync.RWMutex `json:"-"`
+	Ticker                *time.Ticker `json:"-"`
+	StopTicker            chan bool    `json:"-"` // Closed by Stop, never sent on
+	stopOnce              sync.Once
+	Ready                 chan struct{} `json:"-"` // Closed by MarkReady once the lifecycle consumes Register
+	readyOnce             sync.Once
+	Done                  chan struct{} `json:"-"` // Closed by MarkDone once the lifecycle returned, nothing consumes Register, Unregister or Broadcast anymore
+	doneOnce              sync.Once
 	LastTick              time.Time `json:"-"`
 	LastPositionBroadcast time.Time `json:"-"` // Tracks when positions were last broadcast
+	LastPing              time.Time `json:"-"` // Tracks when clients were last pinged
+	LastLobbyUpdate       time.Time `json:"-"` // Tracks when the lobby roster was last broadcast
+	LastLobbyState        time.Time `json:"-"` // Tracks when the full state was last broadcast from the lobby
+	LobbyDirty            bool      `json:"-"` // The roster changed since the last lobby update
+	SendBufferFull        int       `json:"-"` // Messages that found a client's send buffer full
+}
+
+// Publish queues a message for every client and observer of the game, stamped
+// so its broadcast latency can be measured. Like any send on Broadcast it
+// blocks while the queue is full, and once the lifecycle is done the message
+// is dropped instead.
+func (g *Game) Publish(message interface{}) {
+	select {
+	case g.Broadcast <- QueuedMessage{Message: message, EnqueuedAt: time.Now()}:
+	case <-g.Done:
+	}
+}
+
+// Stop asks the game's lifecycle to end by closing StopTicker. It never blocks
+// and is safe to call any number of times, also after the lifecycle returned.
+func (g *Game) Stop() {
+	g.stopOnce.Do(func() {
+		close(g.StopTicker)
+	})
+}
+
+// MarkReady closes Ready, telling connecting clients the lifecycle is now
+// consuming Register. Like Stop it is safe to call more than once.
+func (g *Game) MarkReady() {
+	g.readyOnce.Do(func() {
+		close(g.Ready)
+	})
+}
+
+// MarkDone closes Done, releasing anyone waiting to send to a lifecycle that
+// has returned. Like Stop it is safe to call more than once.
+func (g *Game) MarkDone() {
+	g.doneOnce.Do(func() {
+		close(g.Done)
+	})
+}
+
+// inBounds reports whether x, y is inside both the configured map size and the map array
+func (g *Game) inBounds(x, y int) bool {
+	return x >= 0 && y >= 0 &&
+		x < g.Config.MapWidth && y < g.Config.MapHeight &&
+		y < len(g.Map) && x < len(g.Map[y])
+}
+
+// ColorAt returns the color of the block at x, y (0-based). ok is false, and the
+// color Air, when the coordinates are out of bounds.
+func (g *Game) ColorAt(x, y int) (color WoolColor, ok bool) {
+	if !g.inBounds(x, y) {
+		return Air, false
+	}
+	return g.Map[y][x], true
+}
+
+// SetColorAt sets the color of the block at x, y (0-based), reporting whether it was in bounds
+func (g *Game) SetColorAt(x, y int, color WoolColor) bool {
+	if !g.inBounds(x, y) {
+		return false
+	}
+	g.Map[y][x] = color
+	return true
 }
@@ -0,0 +1,26 @@
+package schema
+
+import "testing"
+
+func TestColorAtOutOfRange(t *testing.T) {
+	game := &Game{Config: GameConfig{MapWidth: 12, MapHeight: 10}}
+	game.Map[9][11] = Red
+
+	if color, ok := game.ColorAt(11, 9); !ok || color != Red {
+		t.Errorf("ColorAt(11, 9) = %v, %v, want Red in bounds", color, ok)
+	}
+	for _, c := range [][2]int{{-1, 0}, {0, -1}, {12, 0}, {0, 10}, {19, 19}, {20, 0}, {256, 256}, {-1 << 31, 1 << 31}} {
+		if color, ok := game.ColorAt(c[0], c[1]); ok || color != Air {
+			t.Errorf("ColorAt(%d, %d) = %v, %v, want Air out of bounds", c[0], c[1], color, ok)
+		}
+		if game.SetColorAt(c[0], c[1], Blue) {
+			t.Errorf("SetColorAt(%d, %d) reported in bounds", c[0], c[1])
+		}
+	}
+
+	// A config larger than the map itself is bounded by the map
+	game.Config = GameConfig{MapWidth: 64, MapHeight: 64}
+	if _, ok := game.ColorAt(20, 20); ok {
+		t.Error("ColorAt(20, 20) past the map reported in bounds")
+	}
+}
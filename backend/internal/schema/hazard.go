@@ -0,0 +1,9 @@
+package schema
+
+// Hazard is a block an eliminated player turned to Air for the next round,
+// by column and row of the map, 0-based
+type Hazard struct {
+	X         int    `json:"x"`
+	Y         int    `json:"y"`
+	DroppedBy string `json:"dropped_by"`
+}
@@ -0,0 +1,9 @@
+package schema
+
+// InitialStateCache is the initial game state built for a connecting client,
+// reused for everyone connecting until the game state changes. Callers hold
+// the game's lock.
+type InitialStateCache struct {
+	Plain   interface{} // The game_update with the color palette
+	Gzipped interface{} // Plain for ?compress=gzip clients, built on first use
+}
@@ -0,0 +1,80 @@
+package schema
+
+import "time"
+
+// PositionSample is a position the server accepted from a player and when
+type PositionSample struct {
+	Position Position
+	At       time.Time
+}
+
+// PositionHistory is a bounded ring buffer of a player's recent accepted
+// positions, oldest evicted first. It is not safe for concurrent use, callers
+// hold the game's lock like for the rest of the player state.
+type PositionHistory struct {
+	samples []PositionSample
+	next    int // Where the next sample is written
+	count   int
+}
+
+// NewPositionHistory creates a history keeping the last capacity positions, at least one
+func NewPositionHistory(capacity int) *PositionHistory {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &PositionHistory{samples: make([]PositionSample, capacity)}
+}
+
+// Add records a position, evicting the oldest one once the buffer is full
+func (h *PositionHistory) Add(position Position, at time.Time) {
+	h.samples[h.next] = PositionSample{Position: position, At: at}
+	h.next = (h.next + 1) % len(h.samples)
+	if h.count < len(h.samples) {
+		h.count++
+	}
+}
+
+// Len returns the number of positions kept
+func (h *PositionHistory) Len() int {
+	return h.count
+}
+
+// Oldest returns the oldest position kept, false if there is none
+func (h *PositionHistory) Oldest() (PositionSample, bool) {
+	if h.count == 0 {
+		return PositionSample{}, false
+	}
+	return h.sample(0), true
+}
+
+// At returns where the player was at t: the newest position recorded at or
+// before t. It reports false if t is before everything still kept.
+func (h *PositionHistory) At(t time.Time) (Position, bool) {
+	for i := h.count - 1; i >= 0; i-- {
+		if sample := h.sample(i); !sample.At.After(t) {
+			return sample.Position, true
+		}
+	}
+	return Position{}, false
+}
+
+// Shift moves every timestamp forward by d, e.g. to skip time the game was paused
+func (h *PositionHistory) Shift(d time.Duration) {
+	for i := range h.samples {
+		if !h.samples[i].At.IsZero() {
+			h.samples[i].At = h.samples[i].At.Add(d)
+		}
+	}
+}
+
+// Reset forgets every position, e.g. after the player was moved to a spawn point
+func (h *PositionHistory) Reset() {
+	h.next = 0
+	h.count = 0
+}
+
+// sample returns the i-th kept sample, 0 being the oldest
+func (h *PositionHistory) sample(i int) PositionSample {
+	start := (h.next - h.count + len(h.samples)) % len(h.samples)
+	return h.samples[(start+i)%len(h.samples)]
+}
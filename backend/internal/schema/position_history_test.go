@@ -0,0 +1,55 @@
+package schema
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPositionHistoryEvictsOldest(t *testing.T) {
+	history := NewPositionHistory(3)
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		history.Add(Position{X: float64(i)}, start.Add(time.Duration(i)*100*time.Millisecond))
+	}
+
+	if history.Len() != 3 {
+		t.Errorf("Len = %d, want 3", history.Len())
+	}
+	oldest, ok := history.Oldest()
+	if !ok || oldest.Position.X != 2 || !oldest.At.Equal(start.Add(200*time.Millisecond)) {
+		t.Errorf("Oldest = %+v, %v, want the third position", oldest, ok)
+	}
+	if _, ok := history.At(start.Add(150 * time.Millisecond)); ok {
+		t.Error("found a position from before everything kept")
+	}
+}
+
+func TestPositionHistoryAtPastTime(t *testing.T) {
+	history := NewPositionHistory(4)
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		history.Add(Position{X: float64(i)}, start.Add(time.Duration(i)*100*time.Millisecond))
+	}
+
+	tests := []struct {
+		after time.Duration
+		want  float64
+	}{
+		{0, 0},                      // Exactly at a sample
+		{50 * time.Millisecond, 0},  // Between samples, the earlier one
+		{100 * time.Millisecond, 1}, // Exactly at the next
+		{250 * time.Millisecond, 2}, // Between samples
+		{time.Second, 2},            // After the newest
+	}
+	for _, tt := range tests {
+		position, ok := history.At(start.Add(tt.after))
+		if !ok || position.X != tt.want {
+			t.Errorf("At(+%v) = %v, %v, want x %v", tt.after, position, ok, tt.want)
+		}
+	}
+
+	history.Reset()
+	if _, ok := history.At(start.Add(time.Second)); ok || history.Len() != 0 {
+		t.Error("positions left after Reset")
+	}
+}
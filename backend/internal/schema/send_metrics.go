@@ -0,0 +1,53 @@
+package schema
+
+import "time"
+
+// QueuedMessage is a message waiting on a game's Broadcast queue
+type QueuedMessage struct {
+	Message    interface{}
+	EnqueuedAt time.Time
+}
+
+// SendMetrics tracks how well a game's messages keep up with its clients: how
+// full their send buffers are when a message is queued for them and how long
+// broadcasts wait before reaching those buffers. Callers hold the game's lock.
+type SendMetrics struct {
+	DepthSamples int // Messages queued for a client
+	DepthTotal   int // Sum of the buffer depths they found
+	MaxDepth     int
+
+	Broadcasts   int
+	LatencyTotal time.Duration
+	MaxLatency   time.Duration
+}
+
+// RecordDepth records the depth of a client's send buffer a message is queued on
+func (m *SendMetrics) RecordDepth(depth int) {
+	m.DepthSamples++
+	m.DepthTotal += depth
+	m.MaxDepth = max(m.MaxDepth, depth)
+}
+
+// RecordLatency records how long a broadcast waited between being published
+// and being handed to the clients
+func (m *SendMetrics) RecordLatency(latency time.Duration) {
+	m.Broadcasts++
+	m.LatencyTotal += latency
+	m.MaxLatency = max(m.MaxLatency, latency)
+}
+
+// AverageDepth returns the mean send buffer depth messages found, 0 without any
+func (m *SendMetrics) AverageDepth() float64 {
+	if m.DepthSamples == 0 {
+		return 0
+	}
+	return float64(m.DepthTotal) / float64(m.DepthSamples)
+}
+
+// AverageLatency returns the mean broadcast latency, 0 without any broadcast
+func (m *SendMetrics) AverageLatency() time.Duration {
+	if m.Broadcasts == 0 {
+		return 0
+	}
+	return m.LatencyTotal / time.Duration(m.Broadcasts)
+}
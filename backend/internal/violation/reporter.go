@@ -0,0 +1,224 @@
+package violation
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ErrQueueFull is returned by FileReporter.Report when the writer has fallen
+// this far behind and the violation is dropped
+var ErrQueueFull = errors.New("violation: write queue full")
+
+// Violation is a single anti-cheat rejection
+type Violation struct {
+	GameID    string    `json:"game_id"`
+	Username  string    `json:"username"`
+	UserID    string    `json:"user_id"` // Empty for players who connected without one
+	Reason    string    `json:"reason"`
+	Speed     float64   `json:"speed"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Reporter records violations and lists them back per game. Report is called
+// with the game locked and must not block on I/O.
+type Reporter interface {
+	Report(v Violation) error
+	List(gameID string) ([]Violation, error)
+}
+
+// gameViolations are the violations kept for one game
+type gameViolations struct {
+	violations []Violation
+	lastReport time.Time
+}
+
+// MemoryReporter keeps violations in memory, they are lost on restart. Each
+// game keeps its newest maxPerGame violations, and a game is forgotten once
+// nothing was reported for it in retention.
+type MemoryReporter struct {
+	mu         sync.RWMutex
+	games      map[string]*gameViolations
+	maxPerGame int
+	retention  time.Duration
+	lastSweep  time.Time
+}
+
+// NewMemoryReporter creates an empty in-memory reporter
+func NewMemoryReporter(maxPerGame int, retention time.Duration) *MemoryReporter {
+	return &MemoryReporter{
+		games:      make(map[string]*gameViolations),
+		maxPerGame: max(maxPerGame, 1),
+		retention:  retention,
+	}
+}
+
+// Report stores the violation under its game, dropping the game's oldest
+// violation once it holds maxPerGame
+func (m *MemoryReporter) Report(v Violation) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	m.sweep(now)
+
+	game, exists := m.games[v.GameID]
+	if !exists {
+		game = &gameViolations{}
+		m.games[v.GameID] = game
+	}
+	if len(game.violations) >= m.maxPerGame {
+		game.violations = append(game.violations[:0], game.violations[len(game.violations)-m.maxPerGame+1:]...)
+	}
+	game.violations = append(game.violations, v)
+	game.lastReport = now
+	return nil
+}
+
+// sweep forgets the games nothing was reported for in retention, at most once
+// per retention period. The caller holds mu.
+func (m *MemoryReporter) sweep(now time.Time) {
+	if m.retention <= 0 || now.Sub(m.lastSweep) < m.retention {
+		return
+	}
+	m.lastSweep = now
+	for gameID, game := range m.games {
+		if now.Sub(game.lastReport) > m.retention {
+			delete(m.games, gameID)
+		}
+	}
+}
+
+// List returns the game's violations in the order they were reported
+func (m *MemoryReporter) List(gameID string) ([]Violation, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	game, exists := m.games[gameID]
+	if !exists || (m.retention > 0 && time.Since(game.lastReport) > m.retention) {
+		return []Violation{}, nil
+	}
+	violations := make([]Violation, len(game.violations))
+	copy(violations, game.violations)
+	return violations, nil
+}
+
+// FileReporter appends violations to an NDJSON file so they outlive the
+// server. Writes happen on a goroutine of its own, so reporting never waits
+// on the disk.
+type FileReporter struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+
+	queue chan fileRequest
+	done  chan struct{}
+	close sync.Once
+}
+
+// fileRequest is a violation to write, or a flush waiting for every write
+// queued before it
+type fileRequest struct {
+	violation Violation
+	flushed   chan struct{}
+}
+
+// fileQueueSize is how many violations may wait for the writer
+const fileQueueSize = 1024
+
+// NewFileReporter opens, or creates, the violations file at path for appending
+func NewFileReporter(path string) (*FileReporter, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("create violations dir: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open violations file: %w", err)
+	}
+
+	f := &FileReporter{
+		path:  path,
+		file:  file,
+		queue: make(chan fileRequest, fileQueueSize),
+		done:  make(chan struct{}),
+	}
+	go f.write()
+	return f, nil
+}
+
+// write appends queued violations to the file until the queue is closed
+func (f *FileReporter) write() {
+	defer close(f.done)
+	enc := json.NewEncoder(f.file)
+	for request := range f.queue {
+		if request.flushed != nil {
+			close(request.flushed)
+			continue
+		}
+
+		f.mu.Lock()
+		err := enc.Encode(request.violation)
+		f.mu.Unlock()
+		if err != nil {
+			zap.L().Error("Failed to write violation", zap.String("path", f.path), zap.Error(err))
+		}
+	}
+}
+
+// Report queues the violation to be appended as a line of the file
+func (f *FileReporter) Report(v Violation) error {
+	select {
+	case f.queue <- fileRequest{violation: v}:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// List waits for the violations reported so far to be written, then reads the
+// file back and returns the game's violations
+func (f *FileReporter) List(gameID string) ([]Violation, error) {
+	flushed := make(chan struct{})
+	f.queue <- fileRequest{flushed: flushed}
+	<-flushed
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	file, err := os.Open(f.path)
+	if err != nil {
+		return nil, fmt.Errorf("open violations file: %w", err)
+	}
+	defer file.Close()
+
+	violations := make([]Violation, 0)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var v Violation
+		if err := json.Unmarshal(scanner.Bytes(), &v); err != nil {
+			continue // Skip a line torn by a crash mid-write
+		}
+		if v.GameID == gameID {
+			violations = append(violations, v)
+		}
+	}
+	return violations, scanner.Err()
+}
+
+// Close writes the queued violations and closes the file. The reporter must
+// not be used afterwards.
+func (f *FileReporter) Close() error {
+	f.close.Do(func() {
+		close(f.queue)
+	})
+	<-f.done
+	return f.file.Close()
+}
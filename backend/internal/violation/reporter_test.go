@@ -0,0 +1,100 @@
+package violation
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMemoryReporterKeepsNewestPerGame(t *testing.T) {
+	m := NewMemoryReporter(3, time.Hour)
+	for i := 0; i < 5; i++ {
+		m.Report(Violation{GameID: "1", Speed: float64(i)})
+	}
+	m.Report(Violation{GameID: "2", Speed: 9})
+
+	violations, _ := m.List("1")
+	if len(violations) != 3 {
+		t.Fatalf("kept %d violations, want 3", len(violations))
+	}
+	for i, v := range violations {
+		if v.Speed != float64(i+2) {
+			t.Errorf("violation %d has speed %v, want %d", i, v.Speed, i+2)
+		}
+	}
+	if other, _ := m.List("2"); len(other) != 1 {
+		t.Errorf("game 2 has %d violations, want 1", len(other))
+	}
+}
+
+func TestMemoryReporterForgetsIdleGames(t *testing.T) {
+	m := NewMemoryReporter(10, time.Hour)
+	m.Report(Violation{GameID: "old"})
+
+	// Pretend the old game was last reported on long ago
+	m.games["old"].lastReport = time.Now().Add(-2 * time.Hour)
+	m.lastSweep = m.games["old"].lastReport
+	if violations, _ := m.List("old"); len(violations) != 0 {
+		t.Errorf("expired game lists %d violations", len(violations))
+	}
+
+	m.Report(Violation{GameID: "new"})
+	if _, kept := m.games["old"]; kept {
+		t.Error("expired game still held in memory after a sweep")
+	}
+}
+
+func TestFileReporterListsWhatWasReported(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "violations", "violations.ndjson")
+	f, err := NewFileReporter(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	at := time.Date(2025, 9, 28, 12, 0, 3, 0, time.UTC)
+	var wg sync.WaitGroup
+	for _, gameID := range []string{"1", "2", "1"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := f.Report(Violation{GameID: gameID, Username: "alice", UserID: "u1", Reason: "movement_too_fast", Timestamp: at}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	violations, err := f.List("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(violations) != 2 {
+		t.Fatalf("listed %d violations, want 2", len(violations))
+	}
+	if v := violations[0]; v.Username != "alice" || v.UserID != "u1" || !v.Timestamp.Equal(at) {
+		t.Errorf("violation = %+v", v)
+	}
+}
+
+func TestFileReporterSurvivesReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "violations.ndjson")
+	f, err := NewFileReporter(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Report(Violation{GameID: "1"})
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := NewFileReporter(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reopened.Close()
+	if violations, _ := reopened.List("1"); len(violations) != 1 {
+		t.Errorf("listed %d violations after reopening, want 1", len(violations))
+	}
+}
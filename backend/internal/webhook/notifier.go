@@ -0,0 +1,170 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ErrForbiddenAddress is returned for webhook destinations on loopback,
+// private, link-local and other non-public addresses
+var ErrForbiddenAddress = errors.New("webhook: destination address not allowed")
+
+// Event is the JSON body POSTed to a game's webhook
+type Event struct {
+	Event     string    `json:"event"`
+	GameID    string    `json:"game_id"`
+	Timestamp time.Time `json:"timestamp"`
+	Data      any       `json:"data,omitempty"`
+}
+
+// Notifier delivers events to webhook URLs in the background, retrying failed
+// deliveries with a growing delay. Callers never wait on the receiving server.
+type Notifier struct {
+	client       *http.Client
+	maxRetries   int
+	retryDelay   time.Duration
+	allowPrivate bool
+
+	// pending holds a slot per delivery in flight, events arriving while all
+	// slots are taken are dropped instead of piling up goroutines
+	pending chan struct{}
+}
+
+// NewNotifier creates a notifier giving each attempt timeout to complete,
+// retrying a failed delivery up to maxRetries times and keeping at most
+// maxPending deliveries in flight. Unless allowPrivate is set, connections to
+// non-public addresses are refused, so game creators can't aim the server at
+// internal services.
+func NewNotifier(timeout time.Duration, maxRetries, maxPending int, allowPrivate bool) *Notifier {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		// Checked on every dial, so DNS rebinding and redirects are covered too
+		dialer.Control = func(network, address string, c syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil || !publicAddr(addrPort.Addr()) {
+				return ErrForbiddenAddress
+			}
+			return nil
+		}
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	// A proxy would hide the destination from the dial check
+	transport.Proxy = nil
+
+	return &Notifier{
+		client:       &http.Client{Timeout: timeout, Transport: transport},
+		maxRetries:   maxRetries,
+		retryDelay:   time.Second,
+		allowPrivate: allowPrivate,
+		pending:      make(chan struct{}, max(maxPending, 1)),
+	}
+}
+
+// CheckURL resolves the host of rawURL and returns ErrForbiddenAddress if any
+// of its addresses is one the notifier refuses to connect to
+func (n *Notifier) CheckURL(ctx context.Context, rawURL string) error {
+	if n == nil || n.allowPrivate {
+		return nil
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", parsed.Hostname())
+	if err != nil {
+		return err
+	}
+	for _, addr := range addrs {
+		if !publicAddr(addr) {
+			return ErrForbiddenAddress
+		}
+	}
+	return nil
+}
+
+// publicAddr reports whether addr is a globally routable unicast address
+func publicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() && !addr.IsPrivate() && !sharedAddressSpace.Contains(addr)
+}
+
+// sharedAddressSpace is the carrier-grade NAT range, not routable on the internet
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// Notify sends the event to url without blocking. The event is encoded before
+// returning, so its data may be changed by the caller afterwards. A nil
+// notifier or an empty url sends nothing.
+func (n *Notifier) Notify(url string, event Event) {
+	if n == nil || url == "" {
+		return
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		zap.L().Error("Failed to encode webhook event", zap.String("event", event.Event), zap.Error(err))
+		return
+	}
+
+	select {
+	case n.pending <- struct{}{}:
+	default:
+		zap.L().Warn("Dropping webhook event, too many deliveries in flight",
+			zap.String("game_id", event.GameID),
+			zap.String("event", event.Event),
+		)
+		return
+	}
+	go func() {
+		defer func() { <-n.pending }()
+		n.deliver(url, event, body)
+	}()
+}
+
+// deliver posts the body until it is accepted or the retries run out
+func (n *Notifier) deliver(url string, event Event, body []byte) {
+	delay := n.retryDelay
+	for attempt := 0; ; attempt++ {
+		err := n.post(url, body)
+		if err == nil {
+			return
+		}
+		if attempt >= n.maxRetries || errors.Is(err, ErrForbiddenAddress) {
+			zap.L().Warn("Giving up on webhook delivery",
+				zap.String("game_id", event.GameID),
+				zap.String("event", event.Event),
+				zap.Int("attempts", attempt+1),
+				zap.Error(err),
+			)
+			return
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// post makes a single delivery attempt, any non-2xx status is a failure
+func (n *Notifier) post(url string, body []byte) error {
+	resp, err := n.client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// receive starts a server passing each decoded event to the returned channel
+func receive(t *testing.T, status func(attempt int) int) (*httptest.Server, <-chan Event) {
+	t.Helper()
+	events := make(chan Event, 16)
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event Event
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("decode webhook body: %v", err)
+		}
+		code := status(int(attempts.Add(1)))
+		if code < 300 {
+			events <- event
+		}
+		w.WriteHeader(code)
+	}))
+	t.Cleanup(server.Close)
+	return server, events
+}
+
+func waitEvent(t *testing.T, events <-chan Event) Event {
+	t.Helper()
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook not delivered")
+		return Event{}
+	}
+}
+
+func TestNotifyDeliversEvent(t *testing.T) {
+	server, events := receive(t, func(int) int { return http.StatusOK })
+	n := NewNotifier(time.Second, 0, 4, true)
+
+	n.Notify(server.URL, Event{Event: "game_started", GameID: "123456", Data: map[string]any{"player_count": 4}})
+
+	event := waitEvent(t, events)
+	if event.Event != "game_started" || event.GameID != "123456" {
+		t.Errorf("got %+v", event)
+	}
+	if count := event.Data.(map[string]any)["player_count"]; count != 4.0 {
+		t.Errorf("player_count = %v, want 4", count)
+	}
+}
+
+func TestNotifyRetriesFailedDelivery(t *testing.T) {
+	server, events := receive(t, func(attempt int) int {
+		if attempt < 3 {
+			return http.StatusServiceUnavailable
+		}
+		return http.StatusNoContent
+	})
+	n := NewNotifier(time.Second, 3, 4, true)
+	n.retryDelay = time.Millisecond
+
+	n.Notify(server.URL, Event{Event: "game_ended", GameID: "123456"})
+
+	if event := waitEvent(t, events); event.Event != "game_ended" {
+		t.Errorf("got %+v", event)
+	}
+}
+
+func TestNotifyRefusesPrivateAddresses(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+	}))
+	defer server.Close()
+
+	n := NewNotifier(time.Second, 0, 4, false)
+	if err := n.post(server.URL, []byte("{}")); !errors.Is(err, ErrForbiddenAddress) {
+		t.Errorf("post to loopback = %v, want ErrForbiddenAddress", err)
+	}
+	if hits.Load() != 0 {
+		t.Error("loopback server was reached")
+	}
+}
+
+func TestNotifyCapsDeliveriesInFlight(t *testing.T) {
+	release := make(chan struct{})
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	n := NewNotifier(5*time.Second, 0, 2, true)
+	for i := 0; i < 5; i++ {
+		n.Notify(server.URL, Event{Event: "milestone", GameID: "123456"})
+	}
+
+	if len(n.pending) != 2 {
+		t.Errorf("%d deliveries in flight, want 2", len(n.pending))
+	}
+	time.Sleep(50 * time.Millisecond)
+	if got := hits.Load(); got > 2 {
+		t.Errorf("server received %d requests, want at most 2", got)
+	}
+}
+
+func TestCheckURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		allowed bool
+	}{
+		{"http://127.0.0.1:8080/hook", false},
+		{"http://[::1]/hook", false},
+		{"http://10.1.2.3/hook", false},
+		{"http://172.16.0.1/hook", false},
+		{"http://192.168.1.10/hook", false},
+		{"http://169.254.169.254/latest/meta-data", false},
+		{"http://100.64.0.1/hook", false},
+		{"http://0.0.0.0/hook", false},
+		{"http://[::ffff:127.0.0.1]/hook", false},
+		{"https://93.184.216.34/hook", true},
+		{"https://[2606:4700::1111]/hook", true},
+	}
+
+	n := NewNotifier(time.Second, 0, 4, false)
+	for _, tt := range tests {
+		err := n.CheckURL(context.Background(), tt.url)
+		if tt.allowed && err != nil {
+			t.Errorf("CheckURL(%s) = %v, want allowed", tt.url, err)
+		}
+		if !tt.allowed && !errors.Is(err, ErrForbiddenAddress) {
+			t.Errorf("CheckURL(%s) = %v, want ErrForbiddenAddress", tt.url, err)
+		}
+	}
+
+	if err := NewNotifier(time.Second, 0, 4, true).CheckURL(context.Background(), "http://127.0.0.1/hook"); err != nil {
+		t.Errorf("CheckURL with private addresses allowed = %v", err)
+	}
+}
+
+func TestPublicAddr(t *testing.T) {
+	for addr, want := range map[string]bool{
+		"8.8.8.8":     true,
+		"127.0.0.1":   false,
+		"10.0.0.1":    false,
+		"fe80::1":     false,
+		"fc00::1":     false,
+		"224.0.0.1":   false,
+		"100.127.0.1": false,
+	} {
+		if got := publicAddr(netip.MustParseAddr(addr)); got != want {
+			t.Errorf("publicAddr(%s) = %v, want %v", addr, got, want)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+// Package wsschema describes the WebSocket protocol in code: a struct for the
+// data of every message the server sends or accepts, registered under its event
+// name, and a machine readable catalog built from them for GET /api/ws-schema.
+package wsschema
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Direction tells who sends a message
+type Direction string
+
+const (
+	ServerToClient Direction = "server_to_client"
+	ClientToServer Direction = "client_to_server"
+)
+
+// Message registers a message's event name with a struct its data marshals like
+type Message struct {
+	Event       string
+	Direction   Direction
+	Description string
+	Data        any // Zero value of the data struct, nil for messages without data
+}
+
+// Entry is a message in the catalog, with the shape of its data
+type Entry struct {
+	Event       string    `json:"event"`
+	Direction   Direction `json:"direction"`
+	Description string    `json:"description"`
+	Data        any       `json:"data,omitempty"`
+}
+
+var registry = make(map[string]Message)
+
+// Register adds a message to the catalog. Registering an event twice panics,
+// each event has one shape.
+func Register(message Message) {
+	key := string(message.Direction) + "/" + message.Event
+	if _, exists := registry[key]; exists {
+		panic("wsschema: message " + key + " registered twice")
+	}
+	registry[key] = message
+}
+
+// Catalog lists every registered message, server messages first, by event name
+func Catalog() []Entry {
+	entries := make([]Entry, 0, len(registry))
+	for _, message := range registry {
+		entry := Entry{
+			Event:       message.Event,
+			Direction:   message.Direction,
+			Description: message.Description,
+		}
+		if message.Data != nil {
+			entry.Data = describe(reflect.TypeOf(message.Data), make(map[reflect.Type]bool))
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Direction != entries[j].Direction {
+			return entries[i].Direction == ServerToClient
+		}
+		return entries[i].Event < entries[j].Event
+	})
+	return entries
+}
+
+// Registered reports whether a message with the event is in the catalog
+func Registered(direction Direction, event string) bool {
+	_, exists := registry[string(direction)+"/"+event]
+	return exists
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// describe turns a type into the JSON shape it marshals to: objects map their
+// field names to their shapes, arrays hold the shape of their elements and
+// everything else is a JSON type name. Optional fields end in "?".
+func describe(t reflect.Type, visiting map[reflect.Type]bool) any {
+	if t == timeType {
+		return "string (RFC 3339)"
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return describe(t.Elem(), visiting)
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array:
+		return []any{describe(t.Elem(), visiting)}
+	case reflect.Map:
+		return map[string]any{"<" + describe(t.Key(), visiting).(string) + ">": describe(t.Elem(), visiting)}
+	case reflect.Struct:
+		// A type containing itself is described once
+		if visiting[t] {
+			return t.Name()
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+
+		fields := make(map[string]any)
+		describeFields(t, fields, visiting)
+		return fields
+	default:
+		return "any"
+	}
+}
+
+// describeFields adds the JSON fields of a struct, flattening embedded structs
+// like encoding/json does
+func describeFields(t reflect.Type, fields map[string]any, visiting map[reflect.Type]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			describeFields(field.Type, fields, visiting)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if strings.Contains(options, "omitempty") || field.Type.Kind() == reflect.Pointer {
+			name += "?"
+		}
+		fields[name] = describe(field.Type, visiting)
+	}
+}
@@ -0,0 +1,387 @@
+package wsschema
+
+import (
+	"time"
+
+	"github.com/yorukot/blind-party/internal/schema"
+)
+
+// GameUpdate is sent in several forms as the game progresses: the full state on
+// connect, round starts, countdown ticks, block removal, elimination results,
+// round ends and the final result. Each form sets only its own fields.
+type GameUpdate struct {
+	// Full state, on connect
+	GameID         string             `json:"game_id,omitempty"`
+	CreatedAt      *time.Time         `json:"created_at,omitempty"`
+	StartedAt      *time.Time         `json:"started_at,omitempty"`
+	EndedAt        *time.Time         `json:"ended_at,omitempty"`
+	Phase          schema.GamePhase   `json:"phase,omitempty"`
+	CurrentRound   *schema.Round      `json:"current_round,omitempty"`
+	Round          *schema.Round      `json:"round,omitempty"`
+	Players        []schema.Player    `json:"players,omitempty"`
+	PlayerCount    int                `json:"player_count,omitempty"`
+	SpectatorCount int                `json:"spectator_count,omitempty"`
+	Config         *schema.GameConfig `json:"config,omitempty"`
+	ColorPalette   []schema.ColorInfo `json:"color_palette,omitempty"`
+	Map            [][]int            `json:"map,omitempty"`
+	RoundNumber    int                `json:"round_number,omitempty"`
+	AliveCount     int                `json:"alive_count,omitempty"`
+	Countdown      *float64           `json:"countdown_seconds,omitempty"`
+
+	// Round start and countdown
+	TargetColor     schema.WoolColor   `json:"target_color,omitempty"`
+	TargetColorInfo *schema.ColorInfo  `json:"target_color_info,omitempty"`
+	TargetColors    []schema.WoolColor `json:"target_colors,omitempty"`
+	UnsafeColors    []schema.WoolColor `json:"unsafe_colors,omitempty"` // Instead of the target colors in hard mode
+	RushDuration    float64            `json:"countdown,omitempty"`
+	BlocksRemoved   bool               `json:"blocks_removed,omitempty"`
+	Hazards         []schema.Hazard    `json:"hazards,omitempty"`
+
+	// Elimination results
+	EliminatedPlayers  []string                   `json:"eliminated_players,omitempty"`
+	EliminationDetails []schema.EliminationRecord `json:"elimination_details,omitempty"`
+	Eliminations       []EliminationAnimation     `json:"eliminations,omitempty"`
+
+	// Round end and game end
+	NextRoundIn float64    `json:"next_round_in,omitempty"`
+	WinnerID    string     `json:"winner_id,omitempty"`
+	EndTime     *time.Time `json:"end_time,omitempty"`
+	TotalRounds int        `json:"total_rounds,omitempty"`
+}
+
+// EliminationAnimation is one player eliminated in a check, for animating the fall
+type EliminationAnimation struct {
+	UserID        string           `json:"user_id"`
+	FinalPosition int              `json:"final_position"`
+	StoodOnColor  schema.WoolColor `json:"stood_on_color"`
+	CalledColor   schema.WoolColor `json:"called_color"`
+}
+
+// CompressedGameState is the initial state gzipped and base64 encoded, for
+// clients connecting with ?compress=gzip. The fields sit next to event.
+type CompressedGameState struct {
+	Encoding string `json:"encoding"`
+	Data     string `json:"data"`
+}
+
+// Winner is a player who won the game
+type Winner struct {
+	Name             string         `json:"name"`
+	RoundsSurvived   int            `json:"rounds_survived"`
+	JoinedRound      int            `json:"joined_round"`
+	EliminationBonus int            `json:"elimination_bonus"`
+	WinnerBonus      int            `json:"winner_bonus"`
+	SurvivalPoints   int            `json:"survival_points"`
+	HookBonuses      map[string]int `json:"hook_bonuses"`
+	DistancePoints   int            `json:"distance_points"`
+	CatchupBonuses   int            `json:"catchup_bonuses"`
+}
+
+// Standing is a player's final rank
+type Standing struct {
+	Name           string  `json:"name"`
+	Placement      int     `json:"placement"`
+	RoundsSurvived int     `json:"rounds_survived"`
+	IsEliminated   bool    `json:"is_eliminated"`
+	TotalDistance  float64 `json:"total_distance"` // Blocks traveled while the game was running
+}
+
+// WinnerAnnounced is the final result, also sent as final_results
+type WinnerAnnounced struct {
+	Winners     []Winner   `json:"winners"`
+	NoWinner    bool       `json:"no_winner"`
+	Standings   []Standing `json:"standings"`
+	VictoryType string     `json:"victory_type"`
+	TotalRounds int        `json:"total_rounds"`
+
+	DeadliestColors []ColorDeaths `json:"deadliest_colors"` // Deadliest first
+}
+
+// ColorDeaths is how many players a called color eliminated over the game
+type ColorDeaths struct {
+	Color        schema.WoolColor `json:"color"`
+	ColorInfo    schema.ColorInfo `json:"color_info"`
+	Eliminations int              `json:"eliminations"`
+	RoundsCalled int              `json:"rounds_called"`
+}
+
+// GameEnded is sent right after winner_announced
+type GameEnded struct {
+	GameID      string     `json:"game_id"`
+	WinnerID    string     `json:"winner_id"`
+	VictoryType string     `json:"victory_type"`
+	TotalRounds int        `json:"total_rounds"`
+	Duration    float64    `json:"duration"`
+	Standings   []Standing `json:"standings"`
+}
+
+// MovementRejected is sent to a player whose position update was refused
+type MovementRejected struct {
+	Reason         string          `json:"reason"`
+	ResetPosition  schema.Position `json:"reset_position"`
+	Message        string          `json:"message"`
+	FrozenMs       int64           `json:"frozen_ms,omitempty"`
+	Speed          float64         `json:"speed,omitempty"`
+	MaxSpeed       float64         `json:"max_speed,omitempty"`
+	ViolationCount int             `json:"violation_count,omitempty"`
+}
+
+// UpdateRejected is sent to a player whose update was dropped before the speed check
+type UpdateRejected struct {
+	Reason  string            `json:"reason"`
+	Phase   schema.RoundPhase `json:"phase,omitempty"`
+	Seq     int64             `json:"seq,omitempty"`
+	LastSeq int64             `json:"last_seq,omitempty"`
+}
+
+// ColorOdd is a color's share of the blocks on the map
+type ColorOdd struct {
+	Color  schema.WoolColor `json:"color"`
+	Name   string           `json:"name"`
+	Blocks int              `json:"blocks"`
+	Share  float64          `json:"share"`
+}
+
+// ColorOdds is sent with each new map and after blocks are removed
+type ColorOdds struct {
+	RoundNumber int        `json:"round_number"`
+	Colors      []ColorOdd `json:"colors"`
+}
+
+// ConnectionClosing is the last message before the server closes a connection
+type ConnectionClosing struct {
+	Code   int    `json:"code"`
+	Reason string `json:"reason"`
+}
+
+// ForfeitRejected is sent to a player whose forfeit couldn't be accepted
+type ForfeitRejected struct {
+	Reason string `json:"reason"`
+}
+
+// PlayerForfeited is broadcast when a player gives up
+type PlayerForfeited struct {
+	Name        string `json:"name"`
+	RoundNumber int    `json:"round_number"`
+	Placement   int    `json:"placement"`
+	AliveCount  int    `json:"alive_count"`
+}
+
+// DropHazard is sent by an eliminated player to knock a block out of the next round
+type DropHazard struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// HazardDropped is broadcast when an eliminated player drops a hazard
+type HazardDropped struct {
+	Name        string `json:"name"`
+	X           int    `json:"x"`
+	Y           int    `json:"y"`
+	RoundNumber int    `json:"round_number"`
+	HazardsLeft int    `json:"hazards_left"`
+}
+
+// HazardRejected is sent to a player whose hazard couldn't be accepted
+type HazardRejected struct {
+	Reason string `json:"reason"`
+}
+
+// GameAbandoned is sent when a lobby never fills up
+type GameAbandoned struct {
+	GameID         string  `json:"game_id"`
+	PlayerCount    int     `json:"player_count"`
+	MinPlayers     int     `json:"min_players"`
+	TimeoutSeconds float64 `json:"timeout_seconds"`
+}
+
+// GameError is sent when the game crashed
+type GameError struct {
+	GameID  string `json:"game_id"`
+	Message string `json:"message"`
+}
+
+// GamePaused is broadcast when an admin pauses the game
+type GamePaused struct {
+	GameID    string    `json:"game_id"`
+	PausedAt  time.Time `json:"paused_at"`
+	Countdown *float64  `json:"countdown_seconds"`
+}
+
+// GameResumed is broadcast when an admin resumes the game
+type GameResumed struct {
+	GameID    string   `json:"game_id"`
+	PausedMs  int64    `json:"paused_ms"`
+	Countdown *float64 `json:"countdown_seconds"`
+}
+
+// GameStartingSoon is broadcast at the start of the first round grace
+type GameStartingSoon struct {
+	GameID       string  `json:"game_id"`
+	Map          [][]int `json:"map"`
+	GraceSeconds float64 `json:"grace_seconds"`
+}
+
+// InputRejected is sent to an observer that sent anything but a ping
+type InputRejected struct {
+	Reason string `json:"reason"`
+	Event  any    `json:"event"`
+}
+
+// LatePlayerJoined is broadcast when a player joins a running game
+type LatePlayerJoined struct {
+	Player      schema.Player `json:"player"`
+	JoinedRound int           `json:"joined_round"`
+	ImmuneRound int           `json:"immune_round"`
+}
+
+// LifeLost is broadcast when a player with lives to spare stands on a wrong color
+type LifeLost struct {
+	Name        string                   `json:"name"`
+	Reason      schema.EliminationReason `json:"reason"`
+	RoundNumber int                      `json:"round_number"`
+	LivesLeft   int                      `json:"lives_left"`
+}
+
+// LobbyUpdate is the coalesced lobby roster
+type LobbyUpdate struct {
+	GameID      string          `json:"game_id"`
+	HostName    string          `json:"host_name"`
+	Players     []schema.Player `json:"players"`
+	PlayerCount int             `json:"player_count"`
+	MinPlayers  int             `json:"min_players"`
+	MaxPlayers  int             `json:"max_players"`
+}
+
+// MapChanged is broadcast when the host regenerates or uploads the map
+type MapChanged struct {
+	GameID  string  `json:"game_id"`
+	Map     [][]int `json:"map"`
+	MapSeed int64   `json:"map_seed,omitempty"`
+	Custom  bool    `json:"custom,omitempty"`
+}
+
+// Milestone is broadcast when the alive count drops to a threshold
+type Milestone struct {
+	Threshold   int `json:"threshold"`
+	AliveCount  int `json:"alive_count"`
+	RoundNumber int `json:"round_number"`
+}
+
+// Ping carries the server time for measuring round trips, echoed in a pong
+type Ping struct {
+	Timestamp int64 `json:"timestamp"`
+}
+
+// PlayerRevived is broadcast when a downed player respawns
+type PlayerRevived struct {
+	PlayerName  string          `json:"player_name"`
+	Position    schema.Position `json:"position"`
+	RevivesLeft int             `json:"revives_left"`
+	RoundNumber int             `json:"round_number"`
+}
+
+// PreparationCancelled is broadcast when the lobby drops below the minimum
+type PreparationCancelled struct {
+	PlayerCount int `json:"player_count"`
+	MinPlayers  int `json:"min_players"`
+}
+
+// ReplayStarted is the first message of a replay
+type ReplayStarted struct {
+	GameID    string            `json:"game_id"`
+	CreatedAt time.Time         `json:"created_at"`
+	Map       [][]int           `json:"map"`
+	MapSeed   int64             `json:"map_seed"`
+	Config    schema.GameConfig `json:"config"`
+}
+
+// SafetyHint tells a player whether they stand on a safe block
+type SafetyHint struct {
+	RoundNumber int  `json:"round_number"`
+	Safe        bool `json:"safe"`
+}
+
+// SwitchToSpectator is sent to a player the moment they are eliminated
+type SwitchToSpectator struct {
+	RoundNumber   int                      `json:"round_number"`
+	Reason        schema.EliminationReason `json:"reason"`
+	FinalPosition int                      `json:"final_position"`
+	Map           [][]int                  `json:"map"`
+	AlivePlayers  []schema.Player          `json:"alive_players"`
+	AliveCount    int                      `json:"alive_count"`
+}
+
+// FinalShowdown is broadcast when a slowed-down round between the last two players starts
+type FinalShowdown struct {
+	RoundNumber  int      `json:"round_number"`
+	Finalists    []string `json:"finalists"`
+	RushDuration float64  `json:"rush_duration"`
+	SlowMoFactor float64  `json:"slow_mo_factor"`
+}
+
+// WarmupRound is broadcast when a practice round starts
+type WarmupRound struct {
+	RoundNumber  int `json:"round_number"`
+	WarmupRounds int `json:"warmup_rounds"`
+}
+
+// WouldBeEliminated is sent to a player who failed a warmup round
+type WouldBeEliminated struct {
+	RoundNumber int                      `json:"round_number"`
+	Reason      schema.EliminationReason `json:"reason"`
+	SparedBy    string                   `json:"spared_by,omitempty"` // elimination_cap when spared outside a warmup round
+}
+
+// PlayerUpdate is a player's position update. Its fields sit next to event.
+type PlayerUpdate struct {
+	Player schema.Position `json:"player"`
+	Seq    int64           `json:"seq,omitempty"` // Increasing, older updates are dropped
+}
+
+func init() {
+	for _, message := range []Message{
+		{"game_update", ServerToClient, "Game state and progress, see GameUpdate for its forms", GameUpdate{}},
+		{"game_state_gz", ServerToClient, "Initial game_update, gzipped for clients connecting with ?compress=gzip", CompressedGameState{}},
+		{"winner_announced", ServerToClient, "The winners and final standings", WinnerAnnounced{}},
+		{"final_results", ServerToClient, "winner_announced, sent to clients connecting once the game is over", WinnerAnnounced{}},
+		{"game_ended", ServerToClient, "The game moved to settlement", GameEnded{}},
+		{"movement_rejected", ServerToClient, "A position update was refused, snap back to reset_position", MovementRejected{}},
+		{"update_rejected", ServerToClient, "A position update was dropped", UpdateRejected{}},
+		{"color_odds", ServerToClient, "Each color's share of the map, with show_color_odds", ColorOdds{}},
+		{"connection_closing", ServerToClient, "The server is about to close the connection", ConnectionClosing{}},
+		{"forfeit_rejected", ServerToClient, "A forfeit couldn't be accepted", ForfeitRejected{}},
+		{"player_forfeited", ServerToClient, "A player gave up", PlayerForfeited{}},
+		{"hazard_dropped", ServerToClient, "An eliminated player knocked a block out of the next round", HazardDropped{}},
+		{"hazard_rejected", ServerToClient, "A hazard couldn't be accepted", HazardRejected{}},
+		{"game_abandoned", ServerToClient, "The lobby never reached the minimum players", GameAbandoned{}},
+		{"game_error", ServerToClient, "The game crashed and was shut down", GameError{}},
+		{"game_paused", ServerToClient, "An admin paused the game", GamePaused{}},
+		{"game_resumed", ServerToClient, "An admin resumed the game", GameResumed{}},
+		{"game_starting_soon", ServerToClient, "The game started, the first round follows the grace", GameStartingSoon{}},
+		{"input_rejected", ServerToClient, "Observers can't send input", InputRejected{}},
+		{"late_player_joined", ServerToClient, "A player joined the running game", LatePlayerJoined{}},
+		{"life_lost", ServerToClient, "A player lost a life instead of being eliminated", LifeLost{}},
+		{"lobby_update", ServerToClient, "The lobby roster changed", LobbyUpdate{}},
+		{"map_changed", ServerToClient, "The host regenerated or uploaded the map", MapChanged{}},
+		{"milestone", ServerToClient, "The alive count dropped to a milestone threshold", Milestone{}},
+		{"ping", ServerToClient, "Echo the timestamp in a pong", Ping{}},
+		{"pong", ServerToClient, "Answer to a client ping", nil},
+		{"player_revived", ServerToClient, "A downed player respawned", PlayerRevived{}},
+		{"preparation_cancelled", ServerToClient, "The start countdown stopped, too few players", PreparationCancelled{}},
+		{"replay_started", ServerToClient, "First message of a replay", ReplayStarted{}},
+		{"safety_hint", ServerToClient, "Whether the player stands on a safe block, with safety_hints", SafetyHint{}},
+		{"switch_to_spectator", ServerToClient, "The player was eliminated and now spectates", SwitchToSpectator{}},
+		{"warmup_round", ServerToClient, "A practice round started", WarmupRound{}},
+		{"final_showdown", ServerToClient, "A slowed-down round between the last two players started", FinalShowdown{}},
+		{"would_be_eliminated", ServerToClient, "The player failed a warmup round", WouldBeEliminated{}},
+
+		{"player_update", ClientToServer, "Position update, the fields sit next to event", PlayerUpdate{}},
+		{"forfeit", ClientToServer, "Give up the game and spectate", nil},
+		{"drop_hazard", ClientToServer, "Eliminated players turn a block to Air for the next round", DropHazard{}},
+		{"ping", ClientToServer, "Keepalive, answered with pong", nil},
+		{"pong", ClientToServer, "Echo of a server ping", Ping{}},
+	} {
+		Register(message)
+	}
+}
@@ -0,0 +1,47 @@
+// Package msgpack is the MessagePack codec for the JSON-shaped messages the
+// game exchanges, built on github.com/vmihailenco/msgpack/v5. Struct json tags
+// decide field names and omission just as they do for JSON clients.
+package msgpack
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// structTag is the tag naming struct fields, shared with encoding/json
+const structTag = "json"
+
+// Marshal encodes v as MessagePack. The keys of message maps, string keyed
+// with interface, string or bool values, are sorted so the same message always
+// encodes the same way.
+func Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag(structTag)
+	enc.SetSortMapKeys(true)
+	enc.UseCompactInts(true)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Unmarshal decodes MessagePack data into v. The data is skipped over once
+// first: the decoder allocates for the length an array declares before reading
+// its elements, so a few bytes claiming billions of them would exhaust memory.
+// Skipping allocates nothing and fails on any length the data can't back.
+func Unmarshal(data []byte, v any) error {
+	r := bytes.NewReader(data)
+	if err := msgpack.NewDecoder(r).Skip(); err != nil {
+		return err
+	}
+	if r.Len() != 0 {
+		return fmt.Errorf("msgpack: %d trailing bytes", r.Len())
+	}
+
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	dec.SetCustomStructTag(structTag)
+	return dec.Decode(v)
+}
@@ -0,0 +1,178 @@
+package msgpack
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	vmsgpack "github.com/vmihailenco/msgpack/v5"
+)
+
+type inner struct {
+	Shared string `json:"shared"`
+	Depth  int    `json:"depth"`
+}
+
+type sample struct {
+	inner
+	Name     string         `json:"name"`
+	Shared   string         `json:"shared"`
+	Score    float64        `json:"score"`
+	Big      uint64         `json:"big"`
+	Skipped  string         `json:"-"`
+	Empty    string         `json:"empty,omitempty"`
+	Nil      *inner         `json:"nil"`
+	NilOmit  *inner         `json:"nil_omit,omitempty"`
+	Tags     []string       `json:"tags"`
+	Raw      []byte         `json:"raw"`
+	ByRound  map[int]string `json:"by_round"`
+	At       time.Time      `json:"at"`
+	Untagged bool
+	private  int
+}
+
+func newSample() sample {
+	return sample{
+		inner:    inner{Shared: "shadowed", Depth: 3},
+		Name:     "alice",
+		Shared:   "outer",
+		Score:    12.5,
+		Big:      1 << 63,
+		Skipped:  "hidden",
+		Tags:     []string{"a", "b"},
+		Raw:      []byte{0, 1, 0xff},
+		ByRound:  map[int]string{1: "red", 10: "blue"},
+		At:       time.Date(2025, 1, 2, 3, 4, 5, 6, time.UTC),
+		Untagged: true,
+		private:  7,
+	}
+}
+
+// keys returns the sorted keys of m
+func keys[V any](m map[string]V) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestFieldsFollowJSONTags(t *testing.T) {
+	v := newSample()
+	data, err := Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]vmsgpack.RawMessage
+	if err := Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	payload, _ := json.Marshal(v)
+	var viaJSON map[string]json.RawMessage
+	json.Unmarshal(payload, &viaJSON)
+	if got, want := keys(decoded), keys(viaJSON); !reflect.DeepEqual(got, want) {
+		t.Errorf("fields %v, JSON has %v", got, want)
+	}
+	var shared string
+	if err := Unmarshal(decoded["shared"], &shared); err != nil || shared != "outer" {
+		t.Errorf("shared = %q (%v), want the outer field", shared, err)
+	}
+}
+
+func TestRoundTripKeepsTypes(t *testing.T) {
+	want := newSample()
+	data, err := Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got sample
+	if err := Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	// Like JSON, the outer field shadows the embedded one
+	want.inner.Shared, want.Skipped, want.private = "", "", 0
+	if !got.At.Equal(want.At) {
+		t.Errorf("at = %v, want %v", got.At, want.At)
+	}
+	got.At = want.At
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip\n got %+v\nwant %+v", got, want)
+	}
+
+	// Untyped, integers stay integers and bytes stay bytes
+	want.ByRound = nil
+	if data, err = Marshal(want); err != nil {
+		t.Fatal(err)
+	}
+	var untyped map[string]any
+	if err := Unmarshal(data, &untyped); err != nil {
+		t.Fatal(err)
+	}
+	if big, ok := untyped["big"].(uint64); !ok || big != 1<<63 {
+		t.Errorf("big = %T %v, want uint64 %d", untyped["big"], untyped["big"], uint64(1<<63))
+	}
+	if score, ok := untyped["score"].(float64); !ok || score != 12.5 {
+		t.Errorf("score = %T %v, want float64 12.5", untyped["score"], untyped["score"])
+	}
+	if raw, ok := untyped["raw"].([]byte); !ok || !bytes.Equal(raw, want.Raw) {
+		t.Errorf("raw = %T %v, want bytes %v", untyped["raw"], untyped["raw"], want.Raw)
+	}
+}
+
+func TestMarshalIsDeterministic(t *testing.T) {
+	v := map[string]any{"b": 1, "a": 2, "c": map[string]string{"z": "1", "y": "2"}, "d": map[string]bool{"x": true, "w": false}}
+	first, err := Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		again, _ := Marshal(v)
+		if !bytes.Equal(first, again) {
+			t.Fatal("encoding differs between runs")
+		}
+	}
+}
+
+func TestUnmarshalRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"truncated string", []byte{0xa5, 'a', 'b'}},
+		{"truncated array", []byte{0x92, 0x01}},
+		{"truncated int", []byte{0xd2, 0x00}},
+		{"huge array length", []byte{0xdd, 0xff, 0xff, 0xff, 0xff}},
+		{"huge map length", []byte{0xdf, 0xff, 0xff, 0xff, 0xff}},
+		{"huge nested array length", []byte{0x81, 0xa1, 'k', 0xdd, 0xff, 0xff, 0xff, 0xff}},
+		{"huge binary length", []byte{0xc6, 0xff, 0xff, 0xff, 0xff}},
+		{"reserved type byte", []byte{0xc1}},
+		{"trailing bytes", []byte{0x01, 0x02}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v any
+			if err := Unmarshal(tt.data, &v); err == nil {
+				t.Errorf("Unmarshal(% x) succeeded", tt.data)
+			}
+			var m map[string]any
+			if err := Unmarshal(tt.data, &m); err == nil {
+				t.Errorf("Unmarshal(% x) into a map succeeded", tt.data)
+			}
+		})
+	}
+}
+
+func TestUnmarshalDeepNesting(t *testing.T) {
+	// As deep as a frame clients are allowed to send can nest
+	data := append(bytes.Repeat([]byte{0x91}, 64<<10-1), 0x01)
+	var v any
+	if err := Unmarshal(data, &v); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+}
@@ -5,40 +5,90 @@ import (
 	"net/http"
 )
 
-// ErrorResponse is the response for an error
-type ErrorResponse struct {
-	Message string `json:"message"`
-	ErrCode string `json:"err_code"`
+// RequestIDHeader is the header the request ID is exposed on, set by the logger middleware
+const RequestIDHeader = "X-Request-ID"
+
+// Response is the envelope every HTTP response is wrapped in
+type Response struct {
+	Data  any       `json:"data,omitempty"`
+	Meta  *Meta     `json:"meta,omitempty"`
+	Error *APIError `json:"error,omitempty"`
 }
 
-// SuccessResponse is the response for a success
-type SuccessResponse struct {
-	Message string `json:"message"`
-	Data    any    `json:"data"`
+// Meta carries pagination and request information alongside the data
+type Meta struct {
+	Page      int    `json:"page,omitempty"`
+	Limit     int    `json:"limit,omitempty"`
+	Total     int    `json:"total,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
 }
 
-// RespondWithError responds with an error message
-func RespondWithError(w http.ResponseWriter, statusCode int, message, errCode string) {
-	w.WriteHeader(statusCode)
-	json.NewEncoder(w).Encode(ErrorResponse{
-		Message: message,
-		ErrCode: errCode,
+// APIError is the error block of a failed response
+type APIError struct {
+	Code    string            `json:"code"`
+	Message string            `json:"message"`
+	Fields  map[string]string `json:"fields,omitempty"`
+}
+
+// OK responds with 200 and the given data
+func OK(w http.ResponseWriter, data any) {
+	OKWithMeta(w, data, nil)
+}
+
+// OKWithMeta responds with 200, the given data and meta
+func OKWithMeta(w http.ResponseWriter, data any, meta *Meta) {
+	write(w, http.StatusOK, Response{
+		Data: data,
+		Meta: withRequestID(w, meta),
 	})
 }
 
-// RespondWithJSON responds with a JSON object
-func RespondWithJSON(w http.ResponseWriter, statusCode int, message string, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-	json.NewEncoder(w).Encode(SuccessResponse{
-		Message: message,
-		Data:    data,
+// NoContent responds with 204 and no body, for successful lookups with nothing to return
+func NoContent(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Fail responds with the given status and an error block
+func Fail(w http.ResponseWriter, statusCode int, code, message string) {
+	write(w, statusCode, Response{
+		Meta: withRequestID(w, nil),
+		Error: &APIError{
+			Code:    code,
+			Message: message,
+		},
+	})
+}
+
+// FailValidation responds with 400 and the invalid fields mapped to their problems
+func FailValidation(w http.ResponseWriter, fields map[string]string) {
+	write(w, http.StatusBadRequest, Response{
+		Meta: withRequestID(w, nil),
+		Error: &APIError{
+			Code:    "VALIDATION_FAILED",
+			Message: "Validation failed",
+			Fields:  fields,
+		},
 	})
 }
 
-// RespondWithData responds with a JSON object
-func RespondWithData(w http.ResponseWriter, data interface{}) {
+// withRequestID fills in the request ID from the response headers
+func withRequestID(w http.ResponseWriter, meta *Meta) *Meta {
+	requestID := w.Header().Get(RequestIDHeader)
+	if requestID == "" {
+		return meta
+	}
+	if meta == nil {
+		meta = &Meta{}
+	}
+	if meta.RequestID == "" {
+		meta.RequestID = requestID
+	}
+	return meta
+}
+
+// write encodes the envelope as JSON with the given status
+func write(w http.ResponseWriter, statusCode int, resp Response) {
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(data)
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(resp)
 }
@@ -0,0 +1,82 @@
+package response
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestEnvelopes(t *testing.T) {
+	tests := []struct {
+		name   string
+		write  func(w http.ResponseWriter)
+		status int
+		body   string
+	}{
+		{
+			"OK",
+			func(w http.ResponseWriter) { OK(w, map[string]int{"count": 2}) },
+			http.StatusOK,
+			`{"data":{"count":2}}`,
+		},
+		{
+			"OKWithMeta",
+			func(w http.ResponseWriter) { OKWithMeta(w, []string{"a"}, &Meta{Total: 1}) },
+			http.StatusOK,
+			`{"data":["a"],"meta":{"total":1}}`,
+		},
+		{
+			"Fail",
+			func(w http.ResponseWriter) { Fail(w, http.StatusNotFound, "GAME_NOT_FOUND", "Game not found") },
+			http.StatusNotFound,
+			`{"error":{"code":"GAME_NOT_FOUND","message":"Game not found"}}`,
+		},
+		{
+			"FailValidation",
+			func(w http.ResponseWriter) { FailValidation(w, map[string]string{"lives": "must be at least 1"}) },
+			http.StatusBadRequest,
+			`{"error":{"code":"VALIDATION_FAILED","message":"Validation failed","fields":{"lives":"must be at least 1"}}}`,
+		},
+		{
+			"OK with a request ID",
+			func(w http.ResponseWriter) {
+				w.Header().Set(RequestIDHeader, "req-1")
+				OK(w, "pong")
+			},
+			http.StatusOK,
+			`{"data":"pong","meta":{"request_id":"req-1"}}`,
+		},
+		{
+			"Fail with a request ID",
+			func(w http.ResponseWriter) {
+				w.Header().Set(RequestIDHeader, "req-2")
+				Fail(w, http.StatusConflict, "GAME_ALREADY_STARTED", "Too late")
+			},
+			http.StatusConflict,
+			`{"meta":{"request_id":"req-2"},"error":{"code":"GAME_ALREADY_STARTED","message":"Too late"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		tt.write(rec)
+		if rec.Code != tt.status {
+			t.Errorf("%s: status = %d, want %d", tt.name, rec.Code, tt.status)
+		}
+		if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+			t.Errorf("%s: Content-Type = %q", tt.name, ct)
+		}
+		if body := strings.TrimSpace(rec.Body.String()); body != tt.body {
+			t.Errorf("%s: body = %s, want %s", tt.name, body, tt.body)
+		}
+	}
+}
+
+func TestNoContent(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NoContent(rec)
+	if rec.Code != http.StatusNoContent || rec.Body.Len() != 0 {
+		t.Errorf("got %d %q, want 204 and no body", rec.Code, rec.Body)
+	}
+}
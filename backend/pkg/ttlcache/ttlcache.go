@@ -0,0 +1,117 @@
+package ttlcache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// entry is a cached value with its expiry
+type entry struct {
+	key       string
+	value     string
+	expiresAt time.Time
+}
+
+// Cache is a concurrency-safe string cache with a TTL per entry and LRU
+// eviction once it holds capacity entries
+type Cache struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration
+	order    *list.List // front is most recently used
+	entries  map[string]*list.Element
+}
+
+// New creates a cache holding at most capacity entries for ttl each
+func New(capacity int, ttl time.Duration) *Cache {
+	return &Cache{
+		capacity: capacity,
+		ttl:      ttl,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// Get returns the value for key if it is present and not expired
+func (c *Cache) Get(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.get(key)
+}
+
+// Set stores value for key, evicting the least recently used entry if full
+func (c *Cache) Set(key, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.set(key, value)
+}
+
+// GetOrSet returns the value for key, or calls create and stores its result if
+// there is none. create runs under the cache lock, so concurrent callers with
+// the same key all get the value from a single call. existed reports whether
+// the value was already cached.
+func (c *Cache) GetOrSet(key string, create func() string) (value string, existed bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if value, ok := c.get(key); ok {
+		return value, true
+	}
+	value = create()
+	c.set(key, value)
+	return value, false
+}
+
+// GetOrSetValid is GetOrSet for values that can go stale before they expire:
+// a cached value valid rejects is replaced by a new one from create, as if
+// the key was missing.
+func (c *Cache) GetOrSetValid(key string, valid func(value string) bool, create func() string) (value string, existed bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if value, ok := c.get(key); ok && valid(value) {
+		return value, true
+	}
+	value = create()
+	c.set(key, value)
+	return value, false
+}
+
+// get looks up key, dropping it if expired. Callers must hold mu.
+func (c *Cache) get(key string) (string, bool) {
+	elem, ok := c.entries[key]
+	if !ok {
+		return "", false
+	}
+	e := elem.Value.(*entry)
+	if time.Now().After(e.expiresAt) {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+		return "", false
+	}
+	c.order.MoveToFront(elem)
+	return e.value, true
+}
+
+// set stores key, evicting the least recently used entry if full. Callers must hold mu.
+func (c *Cache) set(key, value string) {
+	expiresAt := time.Now().Add(c.ttl)
+	if elem, ok := c.entries[key]; ok {
+		e := elem.Value.(*entry)
+		e.value = value
+		e.expiresAt = expiresAt
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	if c.capacity > 0 && c.order.Len() >= c.capacity {
+		if oldest := c.order.Back(); oldest != nil {
+			c.order.Remove(oldest)
+			delete(c.entries, oldest.Value.(*entry).key)
+		}
+	}
+	c.entries[key] = c.order.PushFront(&entry{key: key, value: value, expiresAt: expiresAt})
+}
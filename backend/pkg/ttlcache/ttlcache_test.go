@@ -0,0 +1,49 @@
+package ttlcache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetExpires(t *testing.T) {
+	c := New(4, 20*time.Millisecond)
+	c.Set("a", "1")
+	if v, ok := c.Get("a"); !ok || v != "1" {
+		t.Fatalf("Get = %q, %v", v, ok)
+	}
+	time.Sleep(40 * time.Millisecond)
+	if _, ok := c.Get("a"); ok {
+		t.Error("expired entry still returned")
+	}
+}
+
+func TestEvictsLeastRecentlyUsed(t *testing.T) {
+	c := New(2, time.Minute)
+	c.Set("a", "1")
+	c.Set("b", "2")
+	c.Get("a")
+	c.Set("c", "3")
+
+	if _, ok := c.Get("b"); ok {
+		t.Error("least recently used entry was kept")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := c.Get(key); !ok {
+			t.Errorf("%s was evicted", key)
+		}
+	}
+}
+
+func TestGetOrSetValidReplacesRejectedValue(t *testing.T) {
+	c := New(4, time.Minute)
+	c.Set("a", "stale")
+
+	v, existed := c.GetOrSetValid("a", func(v string) bool { return v != "stale" }, func() string { return "fresh" })
+	if v != "fresh" || existed {
+		t.Errorf("GetOrSetValid = %q, %v", v, existed)
+	}
+	v, existed = c.GetOrSetValid("a", func(string) bool { return true }, func() string { return "other" })
+	if v != "fresh" || !existed {
+		t.Errorf("GetOrSetValid = %q, %v", v, existed)
+	}
+}
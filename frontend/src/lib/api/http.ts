@@ -7,6 +7,24 @@ export interface CreateGameResponse {
     game_id: string;
 }
 
+/**
+ * Envelope every backend HTTP response is wrapped in
+ */
+export interface ApiEnvelope<T> {
+    data?: T;
+    meta?: {
+        page?: number;
+        limit?: number;
+        total?: number;
+        request_id?: string;
+    };
+    error?: {
+        code: string;
+        message: string;
+        fields?: Record<string, string>;
+    };
+}
+
 /**
  * Base API error interface
  */
@@ -64,7 +82,7 @@ class HttpApiClient {
 
                 try {
                     const parsedError = JSON.parse(errorData);
-                    errorMessage = parsedError.message || errorMessage;
+                    errorMessage = parsedError.error?.message || errorMessage;
                 } catch {
                     // If parsing fails, use the raw text or default message
                     errorMessage = errorData || errorMessage;
@@ -75,7 +93,8 @@ class HttpApiClient {
 
             const contentType = response.headers.get('content-type');
             if (contentType && contentType.includes('application/json')) {
-                return await response.json();
+                const envelope = (await response.json()) as ApiEnvelope<T>;
+                return envelope.data as T;
             }
 
             // If response is not JSON, return empty object
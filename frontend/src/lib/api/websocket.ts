@@ -16,17 +16,18 @@ export type ConnectionState =
  */
 export type WebSocketMessage =
     | { event: 'game_update'; data: GameStateResponse }
-    | { type: 'error'; data: { message: string; code?: string } }
-    | { type: 'ping' }
-    | { type: 'pong' };
+    | { event: 'ping'; data: { timestamp: number } }
+    | { event: 'pong' }
+    | { event: 'connection_closing'; data: { code: number; reason: string } }
+    | { type: 'error'; data: { message: string; code?: string } };
 
 /**
  * WebSocket message types that can be sent to the server
  */
 export type OutgoingWebSocketMessage =
-    | { event: 'player_update'; player: { pos_x: number; pos_y: number } }
-    | { type: 'ping' }
-    | { type: 'pong' };
+    | { event: 'player_update'; seq: number; player: { pos_x: number; pos_y: number } }
+    | { event: 'ping' }
+    | { event: 'pong'; data: { timestamp: number } };
 
 /**
  * WebSocket client configuration options
@@ -52,6 +53,29 @@ export interface WebSocketEventListeners {
     onMessage?: (message: WebSocketMessage) => void;
 }
 
+/**
+ * Server close codes worth reconnecting after. Every other code the server
+ * closes with (4000-4999) means reconnecting won't help or would take the
+ * player back from a newer connection, e.g. `replaced_by_new_connection`.
+ */
+const RETRYABLE_CLOSE_CODES = new Set([
+    4017 // game_not_ready, the game was just created
+]);
+
+/**
+ * Identifier tying this tab's connections to its player, so the server lets a
+ * reconnect take the player back over
+ */
+function sessionUserId(): string {
+    const key = 'blind-party-user-id';
+    let userId = sessionStorage.getItem(key);
+    if (!userId) {
+        userId = crypto.randomUUID();
+        sessionStorage.setItem(key, userId);
+    }
+    return userId;
+}
+
 /**
  * WebSocket API client for game connections
  */
@@ -64,6 +88,9 @@ export class WebSocketGameClient {
     private readonly baseUrl: string;
     private gameId: string | null = null;
     private username: string | null = null;
+    private updateSeq = 0;
+    /** Why the server is closing the connection, from `connection_closing` */
+    private closing: { code: number; reason: string } | null = null;
 
     private readonly options: Required<WebSocketClientOptions>;
     private readonly listeners: WebSocketEventListeners = {};
@@ -131,6 +158,7 @@ export class WebSocketGameClient {
     sendPlayerUpdate(pos_x: number, pos_y: number): void {
         this.send({
             event: 'player_update',
+            seq: ++this.updateSeq,
             player: { pos_x: pos_x, pos_y: pos_y }
         });
     }
@@ -182,8 +210,9 @@ export class WebSocketGameClient {
         }
 
         this.setState('connecting');
+        this.closing = null;
 
-        const wsUrl = `${this.baseUrl}/api/game/${this.gameId}/ws?username=${encodeURIComponent(this.username)}`;
+        const wsUrl = `${this.baseUrl}/api/game/${this.gameId}/ws?username=${encodeURIComponent(this.username)}&user_id=${encodeURIComponent(sessionUserId())}`;
 
         try {
             this.ws = new WebSocket(wsUrl);
@@ -256,7 +285,16 @@ export class WebSocketGameClient {
         this.ws.onclose = (event) => {
             this.clearTimers();
 
-            if (event.code === 1000) {
+            const closing =
+                this.closing ??
+                (event.code >= 4000 ? { code: event.code, reason: event.reason } : null);
+            this.closing = null;
+
+            if (closing && !RETRYABLE_CLOSE_CODES.has(closing.code)) {
+                // Disconnected on purpose, reconnecting would only fight the server
+                this.setState('disconnected');
+                this.listeners.onError?.(`Disconnected by the server: ${closing.reason}`);
+            } else if (event.code === 1000 && !closing) {
                 // Normal closure
                 this.setState('disconnected');
             } else if (
@@ -298,6 +336,17 @@ export class WebSocketGameClient {
                 case 'game_update':
                     this.listeners.onGameUpdate?.(message.data);
                     break;
+                case 'ping':
+                    // Echo the timestamp so the server can measure round-trip time
+                    this.send({ event: 'pong', data: { timestamp: message.data.timestamp } });
+                    break;
+                case 'pong':
+                    // Server acknowledged our ping
+                    break;
+                case 'connection_closing':
+                    // The server is about to close the connection, onclose decides what's next
+                    this.closing = message.data;
+                    break;
             }
             return;
         }
@@ -308,12 +357,6 @@ export class WebSocketGameClient {
                 case 'error':
                     this.listeners.onError?.(message.data.message);
                     break;
-                case 'ping':
-                    this.send({ type: 'pong' });
-                    break;
-                case 'pong':
-                    // Server acknowledged our ping
-                    break;
             }
         }
     }
@@ -348,7 +391,7 @@ export class WebSocketGameClient {
     private startPingTimer(): void {
         this.pingTimer = window.setInterval(() => {
             if (this.state === 'connected') {
-                this.send({ type: 'ping' });
+                this.send({ event: 'ping' });
             }
         }, this.options.pingInterval);
     }